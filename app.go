@@ -0,0 +1,220 @@
+package patina
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	githubAppIDEnv             = "GITHUB_APP_ID"
+	githubAppInstallationIDEnv = "GITHUB_APP_INSTALLATION_ID"
+	githubAppPrivateKeyPathEnv = "GITHUB_APP_PRIVATE_KEY_PATH"
+
+	appJWTLifetime     = 9 * time.Minute // GitHub allows at most 10 minutes
+	appJWTClockSkew    = 60 * time.Second
+	appTokenRefreshGap = 5 * time.Minute // Refresh installation tokens this long before expiry
+)
+
+// appClient implements GitHubClient using a GitHub App installation token.
+// Installation tokens are minted on demand and refreshed before they expire.
+type appClient struct {
+	appID          int64
+	installationID int64
+	keyPath        string
+	baseURL        string
+	executor       *requestExecutor
+	now            func() time.Time
+
+	mu        sync.Mutex
+	key       *rsa.PrivateKey
+	token     string
+	expiresAt time.Time
+}
+
+// newAppClientFromEnv creates an appClient if all GitHub App environment
+// variables are set. It returns nil if any are missing.
+func newAppClientFromEnv() *appClient {
+	appID := os.Getenv(githubAppIDEnv)
+	installationID := os.Getenv(githubAppInstallationIDEnv)
+	keyPath := os.Getenv(githubAppPrivateKeyPathEnv)
+	if appID == "" || installationID == "" || keyPath == "" {
+		return nil
+	}
+
+	// Invalid IDs are left as zero and reported when a token is first minted
+	parsedAppID, _ := strconv.ParseInt(appID, 10, 64)
+	parsedInstallationID, _ := strconv.ParseInt(installationID, 10, 64)
+
+	return &appClient{
+		appID:          parsedAppID,
+		installationID: parsedInstallationID,
+		keyPath:        keyPath,
+		baseURL:        apiBaseURL(apiHost()),
+		executor:       newRequestExecutor(&http.Client{Timeout: 30 * time.Second}, defaultConcurrency),
+		now:            time.Now,
+	}
+}
+
+// FetchRepositories retrieves all repositories using an installation token.
+func (c *appClient) FetchRepositories(org string) ([]Repository, error) {
+	token, err := c.installationToken()
+	if err != nil {
+		return nil, err
+	}
+
+	client := &tokenClient{token: token, baseURL: c.baseURL, executor: c.executor}
+	return client.FetchRepositories(org)
+}
+
+// FetchRepositoriesConditional retrieves all repositories using an installation
+// token, revalidating pages from previous with their ETags.
+func (c *appClient) FetchRepositoriesConditional(org string, previous *OrganizationCache) ([]Repository, []PageETag, error) {
+	token, err := c.installationToken()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	client := &tokenClient{token: token, baseURL: c.baseURL, executor: c.executor}
+	return client.FetchRepositoriesConditional(org, previous)
+}
+
+// installationToken returns a valid installation token, minting a new one if
+// none has been issued yet or the current one is close to expiry.
+func (c *appClient) installationToken() (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.token != "" && c.now().Add(appTokenRefreshGap).Before(c.expiresAt) {
+		return c.token, nil
+	}
+
+	if c.appID <= 0 {
+		return "", fmt.Errorf("invalid %s: must be a positive integer", githubAppIDEnv)
+	}
+	if c.installationID <= 0 {
+		return "", fmt.Errorf("invalid %s: must be a positive integer", githubAppInstallationIDEnv)
+	}
+
+	if c.key == nil {
+		key, err := loadPrivateKey(c.keyPath)
+		if err != nil {
+			return "", err
+		}
+		c.key = key
+	}
+
+	jwt, err := signAppJWT(c.key, c.appID, c.now())
+	if err != nil {
+		return "", fmt.Errorf("failed to sign app JWT: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/app/installations/%d/access_tokens", c.baseURL, c.installationID)
+	slog.Debug("minting installation token", "url", url)
+
+	req, err := http.NewRequest("POST", url, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+jwt)
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+
+	resp, err := c.executor.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to mint installation token: %w", err)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusCreated {
+		return "", fmt.Errorf("GitHub API error minting installation token: %s (status %d)", string(body), resp.StatusCode)
+	}
+
+	var result struct {
+		Token     string    `json:"token"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return "", fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	c.token = result.Token
+	c.expiresAt = result.ExpiresAt
+	return c.token, nil
+}
+
+// loadPrivateKey reads a PEM-encoded RSA private key in PKCS#1 or PKCS#8 form.
+func loadPrivateKey(path string) (*rsa.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read app private key: %w", err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("failed to decode app private key: no PEM data found")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse app private key: %w", err)
+	}
+
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("failed to parse app private key: not an RSA key")
+	}
+	return key, nil
+}
+
+// signAppJWT creates an RS256-signed JWT identifying the GitHub App.
+func signAppJWT(key *rsa.PrivateKey, appID int64, now time.Time) (string, error) {
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+
+	claims, err := json.Marshal(map[string]any{
+		"iat": now.Add(-appJWTClockSkew).Unix(),
+		"exp": now.Add(appJWTLifetime).Unix(),
+		"iss": strconv.FormatInt(appID, 10),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	enc := base64.RawURLEncoding
+	signingInput := enc.EncodeToString(header) + "." + enc.EncodeToString(claims)
+
+	digest := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", err
+	}
+
+	return signingInput + "." + enc.EncodeToString(signature), nil
+}
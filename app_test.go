@@ -0,0 +1,213 @@
+package patina
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeTestKey(t *testing.T) (*rsa.PrivateKey, string) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "app.pem")
+	data := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	return key, path
+}
+
+func TestSignAppJWT(t *testing.T) {
+	key, _ := writeTestKey(t)
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+
+	jwt, err := signAppJWT(key, 12345, now)
+	if err != nil {
+		t.Fatalf("signAppJWT() error = %v", err)
+	}
+
+	parts := strings.Split(jwt, ".")
+	if len(parts) != 3 {
+		t.Fatalf("len(parts) = %d, want 3", len(parts))
+	}
+
+	claimsJSON, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		t.Fatalf("DecodeString() error = %v", err)
+	}
+
+	var claims struct {
+		IAT int64  `json:"iat"`
+		EXP int64  `json:"exp"`
+		ISS string `json:"iss"`
+	}
+	if err := json.Unmarshal(claimsJSON, &claims); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	if claims.ISS != "12345" {
+		t.Errorf("iss = %s, want 12345", claims.ISS)
+	}
+	if claims.IAT != now.Add(-appJWTClockSkew).Unix() {
+		t.Errorf("iat = %d, want %d", claims.IAT, now.Add(-appJWTClockSkew).Unix())
+	}
+	if claims.EXP != now.Add(appJWTLifetime).Unix() {
+		t.Errorf("exp = %d, want %d", claims.EXP, now.Add(appJWTLifetime).Unix())
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		t.Fatalf("DecodeString() error = %v", err)
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], signature); err != nil {
+		t.Errorf("VerifyPKCS1v15() error = %v", err)
+	}
+}
+
+func TestLoadPrivateKeyInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bad.pem")
+	if err := os.WriteFile(path, []byte("not a key"), 0600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	if _, err := loadPrivateKey(path); err == nil {
+		t.Error("loadPrivateKey() error = nil for invalid PEM, want error")
+	}
+	if _, err := loadPrivateKey(filepath.Join(t.TempDir(), "missing.pem")); err == nil {
+		t.Error("loadPrivateKey() error = nil for missing file, want error")
+	}
+}
+
+func TestAppClientInstallationToken(t *testing.T) {
+	_, keyPath := writeTestKey(t)
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Method != "POST" || r.URL.Path != "/app/installations/42/access_tokens" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ") {
+			t.Errorf("Authorization = %q, want Bearer JWT", r.Header.Get("Authorization"))
+		}
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]any{
+			"token":      "ghs_token",
+			"expires_at": now.Add(time.Hour),
+		})
+	}))
+	defer server.Close()
+
+	client := &appClient{
+		appID:          1,
+		installationID: 42,
+		keyPath:        keyPath,
+		baseURL:        server.URL,
+		executor:       newRequestExecutor(server.Client(), 1),
+		now:            func() time.Time { return now },
+	}
+
+	token, err := client.installationToken()
+	if err != nil {
+		t.Fatalf("installationToken() error = %v", err)
+	}
+	if token != "ghs_token" {
+		t.Errorf("token = %s, want ghs_token", token)
+	}
+
+	// A token that is not close to expiry is reused
+	if _, err := client.installationToken(); err != nil {
+		t.Fatalf("installationToken() error = %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("requests = %d after reuse, want 1", requests)
+	}
+
+	// A token within the refresh window is replaced
+	now = now.Add(time.Hour - appTokenRefreshGap + time.Second)
+	if _, err := client.installationToken(); err != nil {
+		t.Fatalf("installationToken() error = %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("requests = %d after expiry, want 2", requests)
+	}
+}
+
+func TestNewGitHubClientSelectsApp(t *testing.T) {
+	t.Setenv(githubAppIDEnv, "1")
+	t.Setenv(githubAppInstallationIDEnv, "42")
+	t.Setenv(githubAppPrivateKeyPathEnv, "/nonexistent.pem")
+	t.Setenv(githubTokenEnv, "ghp_token")
+
+	if _, ok := NewGitHubClient().(*appClient); !ok {
+		t.Error("NewGitHubClient() did not return an appClient with app credentials set")
+	}
+
+	t.Setenv(githubAppPrivateKeyPathEnv, "")
+	if _, ok := NewGitHubClient().(*authFallbackClient); !ok {
+		t.Error("NewGitHubClient() did not return a token client without app credentials")
+	}
+}
+
+func TestNewGitHubClientSelectsHostToken(t *testing.T) {
+	t.Setenv(githubAppIDEnv, "")
+	t.Setenv(githubTokenEnv, "ghp_dotcom")
+	t.Setenv("GH_ENTERPRISE_TOKEN", "")
+	t.Setenv("GITHUB_ENTERPRISE_TOKEN", "ghp_enterprise")
+
+	tests := []struct {
+		host        string
+		wantToken   string
+		wantBaseURL string
+	}{
+		{"", "ghp_dotcom", "https://api.github.com"},
+		{"github.com", "ghp_dotcom", "https://api.github.com"},
+		{"GHE.Example.com", "ghp_enterprise", "https://ghe.example.com/api/v3"},
+		{"acme.ghe.com", "ghp_enterprise", "https://api.acme.ghe.com"},
+	}
+
+	for _, tt := range tests {
+		t.Setenv(githubHostEnv, tt.host)
+		fallback, ok := NewGitHubClient().(*authFallbackClient)
+		if !ok {
+			t.Fatalf("NewGitHubClient() with GH_HOST=%q did not return a token client", tt.host)
+		}
+		client := fallback.token
+		if client.token != tt.wantToken || client.baseURL != tt.wantBaseURL {
+			t.Errorf("GH_HOST=%q: token %q, base URL %q; want %q, %q", tt.host, client.token, client.baseURL, tt.wantToken, tt.wantBaseURL)
+		}
+	}
+
+	// GH_ENTERPRISE_TOKEN takes precedence, and GITHUB_TOKEN is never sent to an Enterprise host
+	t.Setenv(githubHostEnv, "ghe.example.com")
+	t.Setenv("GH_ENTERPRISE_TOKEN", "ghp_gh_enterprise")
+	if client := NewGitHubClient().(*authFallbackClient).token; client.token != "ghp_gh_enterprise" {
+		t.Errorf("token = %q, want ghp_gh_enterprise", client.token)
+	}
+
+	t.Setenv("GH_ENTERPRISE_TOKEN", "")
+	t.Setenv("GITHUB_ENTERPRISE_TOKEN", "")
+	if _, ok := NewGitHubClient().(*ghCLIClient); !ok {
+		t.Error("NewGitHubClient() with only GITHUB_TOKEN set for an Enterprise host did not fall back to gh")
+	}
+}
@@ -0,0 +1,116 @@
+package patina
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+// repositoryArchiver is implemented by clients that can archive repositories.
+type repositoryArchiver interface {
+	ArchiveRepository(fullName string) error
+}
+
+// ArchiveRepository archives a repository, making it read-only. The
+// credentials must have admin rights to the repository; GitHub refuses
+// otherwise. Archiving an already archived repository succeeds.
+func ArchiveRepository(client GitHubClient, fullName string) error {
+	if err := ValidateRepository(fullName); err != nil {
+		return err
+	}
+	archiver, ok := client.(repositoryArchiver)
+	if !ok {
+		return errors.New("GitHub client cannot archive repositories")
+	}
+	return archiver.ArchiveRepository(fullName)
+}
+
+// ArchiveRepository archives a repository using the GitHub API with a token.
+func (c *tokenClient) ArchiveRepository(fullName string) error {
+	url := c.baseURL + repositoryPath(fullName)
+
+	slog.Debug("archiving repository", "url", url)
+
+	req, err := http.NewRequest("PATCH", url, bytes.NewReader([]byte(`{"archived":true}`)))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+
+	resp, err := c.executor.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to archive repository: %w", err)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusNotFound:
+		return fmt.Errorf("%w: %s", ErrRepositoryNotFound, fullName)
+	}
+	return apiError(fullName, resp.StatusCode, body)
+}
+
+// ArchiveRepository archives a repository using an installation token.
+func (c *appClient) ArchiveRepository(fullName string) error {
+	token, err := c.installationToken()
+	if err != nil {
+		return err
+	}
+
+	client := &tokenClient{token: token, baseURL: c.baseURL, executor: c.executor}
+	return client.ArchiveRepository(fullName)
+}
+
+// ArchiveRepository archives a repository using the gh CLI.
+func (c *ghCLIClient) ArchiveRepository(fullName string) error {
+	if err := c.ensureChecked(); err != nil {
+		return err
+	}
+
+	args := []string{"api", "--method", "PATCH", repositoryPath(fullName), "-F", "archived=true"}
+
+	slog.Debug("running gh", "args", args)
+
+	_, stderr, err := c.run(args...)
+	if err != nil {
+		switch {
+		case strings.Contains(stderr.String(), "HTTP 404"):
+			return fmt.Errorf("%w: %s", ErrRepositoryNotFound, fullName)
+		case isGHAccessDenied(stderr.String()):
+			return noAccessError(fullName, strings.TrimSpace(stderr.String()))
+		}
+		return wrapGHError(err, stderr.String())
+	}
+	return nil
+}
+
+// ArchiveRepository archives a repository with the REST API.
+func (c *graphqlClient) ArchiveRepository(fullName string) error {
+	return c.rest.ArchiveRepository(fullName)
+}
+
+// ArchiveRepository archives a repository with the token, or the gh CLI once it is rejected.
+func (c *authFallbackClient) ArchiveRepository(fullName string) error {
+	if !c.usingGH() {
+		err := c.token.ArchiveRepository(fullName)
+		if !c.fallBack(err) {
+			return err
+		}
+	}
+	return c.gh.ArchiveRepository(fullName)
+}
@@ -0,0 +1,68 @@
+package patina
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestTokenClientArchiveRepository(t *testing.T) {
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PATCH" {
+			t.Errorf("request method = %s, want PATCH", r.Method)
+		}
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+
+		switch r.URL.Path {
+		case "/repos/org/legacy":
+			w.Write([]byte(`{"full_name":"org/legacy","archived":true}`))
+		default:
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"message":"Must have admin rights to Repository."}`))
+		}
+	}))
+	defer server.Close()
+
+	client := &tokenClient{token: "ghp_token", baseURL: server.URL, executor: newRequestExecutor(server.Client(), 1)}
+
+	if err := ArchiveRepository(client, "org/legacy"); err != nil {
+		t.Fatalf("ArchiveRepository() error = %v", err)
+	}
+	if body != `{"archived":true}` {
+		t.Errorf("request body = %s, want {\"archived\":true}", body)
+	}
+
+	if err := client.ArchiveRepository("org/other"); !errors.Is(err, ErrNoAccess) {
+		t.Errorf("ArchiveRepository() error = %v, want ErrNoAccess without admin rights", err)
+	}
+}
+
+func TestGHCLIClientArchiveRepository(t *testing.T) {
+	var calls []string
+	client := &ghCLIClient{exec: func(args ...string) (stdout, stderr bytes.Buffer, err error) {
+		calls = append(calls, strings.Join(args, " "))
+		return stdout, stderr, nil
+	}}
+
+	if err := client.ArchiveRepository("org/legacy"); err != nil {
+		t.Fatalf("ArchiveRepository() error = %v", err)
+	}
+	if want := "api --method PATCH /repos/org/legacy -F archived=true"; calls[len(calls)-1] != want {
+		t.Errorf("gh call = %q, want %q", calls[len(calls)-1], want)
+	}
+}
+
+func TestArchiveRepositoryValidation(t *testing.T) {
+	if err := ArchiveRepository(&mockGitHubClient{}, "../etc"); !errors.Is(err, ErrInvalidRepository) {
+		t.Errorf("ArchiveRepository() error = %v, want ErrInvalidRepository", err)
+	}
+	if err := ArchiveRepository(&mockGitHubClient{}, "org/legacy"); err == nil {
+		t.Error("ArchiveRepository() error = nil for a client that cannot archive")
+	}
+}
@@ -0,0 +1,144 @@
+package patina
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/cli/go-gh/v2"
+)
+
+// ghTokenEnvs are the variables the gh CLI prefers to its stored credentials.
+var ghTokenEnvs = append([]string{"GH_TOKEN", githubTokenEnv}, githubEnterpriseTokenEnvs...)
+
+// ghExecWithoutTokens runs gh like gh.Exec, but without the token variables in
+// its environment, so it uses the credentials stored by 'gh auth login'.
+func ghExecWithoutTokens(args ...string) (stdout, stderr bytes.Buffer, err error) {
+	ghExe, err := gh.Path()
+	if err != nil {
+		return stdout, stderr, err
+	}
+
+	cmd := exec.Command(ghExe, args...)
+	cmd.Env = slices.DeleteFunc(os.Environ(), func(kv string) bool {
+		name, _, _ := strings.Cut(kv, "=")
+		return slices.Contains(ghTokenEnvs, name)
+	})
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return stdout, stderr, fmt.Errorf("gh execution failed: %w", err)
+	}
+	return stdout, stderr, nil
+}
+
+// authFallbackClient sends requests with a token, switching to the gh CLI for
+// the rest of the run once GitHub rejects the token, as it does when a token
+// has expired or been revoked but 'gh auth login' still works.
+type authFallbackClient struct {
+	token *tokenClient
+	gh    *ghCLIClient
+
+	mu       sync.Mutex // Guards switched so the switch is announced once
+	switched bool
+}
+
+// usingGH reports whether the client has switched to the gh CLI.
+func (c *authFallbackClient) usingGH() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.switched
+}
+
+// fallBack reports whether a failed token request should be retried with the
+// gh CLI, switching to it for later requests. It only does so when GitHub
+// rejected the token and gh is installed and signed in; otherwise the token's
+// error stands.
+func (c *authFallbackClient) fallBack(err error) bool {
+	if !errors.Is(err, ErrUnauthorized) {
+		return false
+	}
+	if ghErr := c.gh.ensureChecked(); ghErr != nil {
+		slog.Debug("gh CLI unavailable as a fallback for the rejected token", "error", ghErr)
+		return false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.switched {
+		slog.Warn("GitHub rejected the token; using the gh CLI's credentials instead (update or unset the token to silence this)", "error", err)
+		c.switched = true
+	}
+	return true
+}
+
+// FetchRepositories retrieves all repositories with the token, or the gh CLI once it is rejected.
+func (c *authFallbackClient) FetchRepositories(org string) ([]Repository, error) {
+	repos, _, err := c.FetchRepositoriesConditional(org, nil)
+	return repos, err
+}
+
+// FetchRepositoriesConditional retrieves all repositories like
+// tokenClient.FetchRepositoriesConditional. The gh CLI does not send ETags,
+// so after switching to it every page is fetched and none are returned.
+func (c *authFallbackClient) FetchRepositoriesConditional(org string, previous *OrganizationCache) ([]Repository, []PageETag, error) {
+	if !c.usingGH() {
+		repos, pages, err := c.token.FetchRepositoriesConditional(org, previous)
+		if !c.fallBack(err) {
+			return repos, pages, err
+		}
+	}
+	repos, err := c.gh.FetchRepositories(org)
+	return repos, nil, err
+}
+
+// FetchLastCommit retrieves the last commit of a repository with the token, or the gh CLI once it is rejected.
+func (c *authFallbackClient) FetchLastCommit(fullName string) (LastCommit, error) {
+	if !c.usingGH() {
+		commit, err := c.token.FetchLastCommit(fullName)
+		if !c.fallBack(err) {
+			return commit, err
+		}
+	}
+	return c.gh.FetchLastCommit(fullName)
+}
+
+// FetchProtection looks up branch protection with the token, or the gh CLI once it is rejected.
+func (c *authFallbackClient) FetchProtection(fullName, branch string) (ProtectionStatus, error) {
+	if !c.usingGH() {
+		status, err := c.token.FetchProtection(fullName, branch)
+		if !c.fallBack(err) {
+			return status, err
+		}
+	}
+	return c.gh.FetchProtection(fullName, branch)
+}
+
+// FetchRepository retrieves a single repository with the token, or the gh CLI once it is rejected.
+func (c *authFallbackClient) FetchRepository(fullName string) (Repository, error) {
+	if !c.usingGH() {
+		repo, err := c.token.FetchRepository(fullName)
+		if !c.fallBack(err) {
+			return repo, err
+		}
+	}
+	return c.gh.FetchRepository(fullName)
+}
+
+// FetchRateLimits retrieves the rate limits of the token, or of the gh CLI's
+// credentials once the token is rejected.
+func (c *authFallbackClient) FetchRateLimits() (RateLimits, error) {
+	if !c.usingGH() {
+		limits, err := c.token.FetchRateLimits()
+		if !c.fallBack(err) {
+			return limits, err
+		}
+	}
+	return c.gh.FetchRateLimits()
+}
@@ -0,0 +1,163 @@
+package patina
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// newRejectingTokenClient returns a token client whose server answers every
+// request with status, counting the requests.
+func newRejectingTokenClient(t *testing.T, status int, requests *int) *tokenClient {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		w.WriteHeader(status)
+		fmt.Fprint(w, `{"message":"Bad credentials"}`)
+	}))
+	t.Cleanup(server.Close)
+
+	return &tokenClient{token: "ghp_expired", baseURL: server.URL, executor: newRequestExecutor(server.Client(), 1)}
+}
+
+func TestAPIErrorUnauthorized(t *testing.T) {
+	err := apiError("org", http.StatusUnauthorized, []byte(`{"message":"Bad credentials"}`))
+	if !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("apiError() = %v, want ErrUnauthorized", err)
+	}
+	if !strings.Contains(err.Error(), "Bad credentials") {
+		t.Errorf("apiError() = %v, want GitHub's message", err)
+	}
+}
+
+func TestAuthFallbackClientSwitchesToGHOn401(t *testing.T) {
+	tokenRequests := 0
+	var ghCalls []string
+	pages := fakeGHPages(t, 3, new([]string))
+
+	client := &authFallbackClient{
+		token: newRejectingTokenClient(t, http.StatusUnauthorized, &tokenRequests),
+		gh: &ghCLIClient{exec: func(args ...string) (stdout, stderr bytes.Buffer, err error) {
+			ghCalls = append(ghCalls, args[0])
+			if args[0] == "api" {
+				return pages(args...)
+			}
+			return stdout, stderr, nil
+		}},
+	}
+
+	for range 2 {
+		repos, err := client.FetchRepositories("org")
+		if err != nil {
+			t.Fatalf("FetchRepositories() error = %v", err)
+		}
+		if len(repos) != 3 {
+			t.Errorf("FetchRepositories() returned %d repositories, want 3", len(repos))
+		}
+	}
+
+	if tokenRequests != 1 {
+		t.Errorf("token requests = %d, want 1: the rejected token should not be retried", tokenRequests)
+	}
+	if got := strings.Join(ghCalls, " "); got != "--version auth api api" {
+		t.Errorf("gh calls = %q, want the precheck followed by one request per fetch", got)
+	}
+}
+
+func TestAuthFallbackClientKeepsTokenErrorWithoutGH(t *testing.T) {
+	tokenRequests := 0
+	client := &authFallbackClient{
+		token: newRejectingTokenClient(t, http.StatusUnauthorized, &tokenRequests),
+		gh: &ghCLIClient{exec: func(args ...string) (stdout, stderr bytes.Buffer, err error) {
+			if args[0] == "auth" {
+				stderr.WriteString("You are not logged into any GitHub hosts. To log in, run: gh auth login")
+				return stdout, stderr, errors.New("exit status 1")
+			}
+			if args[0] == "api" {
+				t.Errorf("gh api called although gh is not signed in")
+			}
+			return stdout, stderr, nil
+		}},
+	}
+
+	_, err := client.FetchRepositories("org")
+	if !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("FetchRepositories() error = %v, want the token's ErrUnauthorized", err)
+	}
+	if client.usingGH() {
+		t.Error("client switched to gh although gh is not signed in")
+	}
+}
+
+func TestAuthFallbackClientIgnoresOtherErrors(t *testing.T) {
+	tokenRequests := 0
+	client := &authFallbackClient{
+		token: newRejectingTokenClient(t, http.StatusForbidden, &tokenRequests),
+		gh: &ghCLIClient{exec: func(args ...string) (stdout, stderr bytes.Buffer, err error) {
+			t.Errorf("gh called for a non-authentication error: %v", args)
+			return stdout, stderr, nil
+		}},
+	}
+
+	if _, err := client.FetchRepositories("org"); !errors.Is(err, ErrNoAccess) {
+		t.Errorf("FetchRepositories() error = %v, want ErrNoAccess", err)
+	}
+}
+
+func TestAuthFallbackClientFetchRateLimits(t *testing.T) {
+	tokenRequests := 0
+	client := &authFallbackClient{
+		token: newRejectingTokenClient(t, http.StatusUnauthorized, &tokenRequests),
+		gh: &ghCLIClient{exec: func(args ...string) (stdout, stderr bytes.Buffer, err error) {
+			if args[0] == "api" {
+				stdout.WriteString(rateLimitResponse)
+			}
+			return stdout, stderr, nil
+		}},
+	}
+
+	limits, err := FetchRateLimits(client)
+	if err != nil {
+		t.Fatalf("FetchRateLimits() error = %v", err)
+	}
+	if limits.Core.Remaining != 4990 {
+		t.Errorf("Core.Remaining = %d, want 4990 from gh", limits.Core.Remaining)
+	}
+}
+
+func TestGHExecWithoutTokens(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script in place of gh")
+	}
+
+	script := filepath.Join(t.TempDir(), "gh")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\nenv\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GH_PATH", script)
+	t.Setenv("GH_TOKEN", "ghp_gh")
+	t.Setenv(githubTokenEnv, "ghp_expired")
+	t.Setenv("GH_ENTERPRISE_TOKEN", "ghp_enterprise")
+	t.Setenv(githubHostEnv, "github.com")
+
+	stdout, _, err := ghExecWithoutTokens()
+	if err != nil {
+		t.Fatalf("ghExecWithoutTokens() error = %v", err)
+	}
+	env := stdout.String()
+	for _, name := range []string{"GH_TOKEN=", githubTokenEnv + "=", "GH_ENTERPRISE_TOKEN="} {
+		if strings.Contains(env, name) {
+			t.Errorf("gh environment contains %s", name)
+		}
+	}
+	if !strings.Contains(env, githubHostEnv+"=github.com") {
+		t.Errorf("gh environment lacks %s: %s", githubHostEnv, env)
+	}
+}
@@ -1,29 +1,98 @@
 package patina
 
 import (
+	"bytes"
+	"compress/gzip"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strings"
 	"time"
 )
 
 const (
 	cacheDirName  = "patina"
 	cacheValidity = 30 * 24 * time.Hour // 30 days
+
+	// CacheDirEnv overrides the default cache directory when set.
+	CacheDirEnv = "PATINA_CACHE_DIR"
+
+	// xdgCacheHomeEnv is honoured on every platform, not just where Go's
+	// os.UserCacheDir reads it, so dotfile setups behave the same on macOS.
+	xdgCacheHomeEnv = "XDG_CACHE_HOME"
 )
 
 var (
-	ErrCacheExpired  = errors.New("cache expired")
-	ErrCacheNotFound = errors.New("cache not found")
+	ErrCacheExpired        = errors.New("cache expired")
+	ErrCacheNotFound       = errors.New("cache not found")
+	ErrInvalidOrganization = errors.New("invalid organization name")
+
+	// ErrCacheCorrupt is returned when a cache file cannot be decoded, for
+	// example because a write was interrupted
+	ErrCacheCorrupt = errors.New("cache corrupt")
 )
 
+// organizationPattern matches the characters GitHub permits in organization
+// and user names, including the underscore used by managed user accounts.
+var organizationPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]{0,99}$`)
+
+// ValidateOrganization checks that an organization name is one GitHub could
+// issue, so it is safe to use as a cache filename.
+func ValidateOrganization(org string) error {
+	if !organizationPattern.MatchString(org) {
+		return fmt.Errorf("%w: %q", ErrInvalidOrganization, org)
+	}
+	return nil
+}
+
 // Repository represents a GitHub repository with its last update timestamp.
 type Repository struct {
+	ID          int64     `json:"id,omitempty"` // Stable across renames and transfers
 	Name        string    `json:"name"`
 	FullName    string    `json:"full_name"`
-	LastUpdated time.Time `json:"last_updated"`
+	LastUpdated time.Time `json:"last_updated"` // Drives freshness; the last push unless another activity metric is chosen
+	PushedAt    time.Time `json:"pushed_at,omitzero"`
+	UpdatedAt   time.Time `json:"updated_at,omitzero"` // Also changes with issues, settings, and other non-push activity
 	HTMLURL     string    `json:"html_url"`
+	CloneURL    string    `json:"clone_url,omitempty"`
+	SSHURL      string    `json:"ssh_url,omitempty"`
+	Description string    `json:"description,omitempty"`
+	Owner       string    `json:"owner"`
+	License     string    `json:"license"`
+	Stars       int       `json:"stars"`
+	OpenIssues  int       `json:"open_issues"`
+	SizeKB      int       `json:"size_kb,omitempty"`
+	Fork        bool      `json:"fork"`
+	MirrorURL   string    `json:"mirror_url,omitempty"` // Set for mirrors of repositories maintained elsewhere
+	Archived    bool      `json:"archived,omitempty"`
+	Topics      []string  `json:"topics,omitempty"`
+	Language    string    `json:"language,omitempty"` // Primary language, as detected by GitHub
+
+	// Disabled features, often a sign a repository was abandoned. Stored as
+	// disabled so caches written before they were recorded read as enabled.
+	IssuesDisabled   bool `json:"issues_disabled,omitempty"`
+	WikiDisabled     bool `json:"wiki_disabled,omitempty"`
+	ProjectsDisabled bool `json:"projects_disabled,omitempty"`
+
+	DefaultBranch string `json:"default_branch,omitempty"`
+	Visibility    string `json:"visibility,omitempty"`   // public, private, or internal
+	NeverPushed   bool   `json:"never_pushed,omitempty"` // Created but never pushed to; LastUpdated is zero
+
+	// Populated only when branch protection lookup is requested
+	Protection ProtectionStatus `json:"protection,omitempty"`
+
+	// Populated only when last-commit enrichment is requested. The lookup is
+	// recorded as checked even if it found no commit or failed, so cached
+	// scans do not repeat it; a later fetch does if the repository is pushed to.
+	LastCommitAuthor  string    `json:"last_commit_author,omitempty"`
+	LastCommitEmail   string    `json:"last_commit_email,omitempty"`
+	LastCommitDate    time.Time `json:"last_commit_date,omitzero"`
+	LastCommitChecked bool      `json:"last_commit_checked,omitempty"`
 }
 
 // OrganizationCache holds cached repository data for an organization.
@@ -31,16 +100,50 @@ type OrganizationCache struct {
 	Organization string       `json:"organization"`
 	FetchedAt    time.Time    `json:"fetched_at"`
 	Repositories []Repository `json:"repositories"`
+	PageETags    []PageETag   `json:"page_etags,omitempty"`
+}
+
+// PageETag records the ETag of one page of API results and how many of the
+// cached repositories came from that page.
+type PageETag struct {
+	ETag  string `json:"etag"`
+	Count int    `json:"count"`
+}
+
+// page returns the ETag and cached repositories for a 1-based page number.
+// It reports false if the cache is nil or does not cover the page.
+func (c *OrganizationCache) page(n int) (PageETag, []Repository, bool) {
+	if c == nil || n < 1 || n > len(c.PageETags) {
+		return PageETag{}, nil, false
+	}
+
+	offset := 0
+	for _, p := range c.PageETags[:n-1] {
+		offset += p.Count
+	}
+
+	p := c.PageETags[n-1]
+	if offset+p.Count > len(c.Repositories) {
+		return PageETag{}, nil, false
+	}
+	return p, c.Repositories[offset : offset+p.Count], true
 }
 
 // Cache provides methods for storing and retrieving organization data.
 type Cache struct {
 	baseDir string
+	now     func() time.Time // Current time for FetchedAt and expiry; time.Now if nil
 }
 
-// NewCache creates a new Cache instance with the default cache directory.
+// NewCache creates a new Cache instance in the directory named by
+// PATINA_CACHE_DIR, or else in a patina directory under XDG_CACHE_HOME or
+// the platform's default cache directory.
 func NewCache() (*Cache, error) {
-	cacheDir, err := os.UserCacheDir()
+	if dir := os.Getenv(CacheDirEnv); dir != "" {
+		return &Cache{baseDir: dir}, nil
+	}
+
+	cacheDir, err := userCacheDir()
 	if err != nil {
 		return nil, err
 	}
@@ -49,43 +152,188 @@ func NewCache() (*Cache, error) {
 	return &Cache{baseDir: baseDir}, nil
 }
 
+// userCacheDir returns XDG_CACHE_HOME if it is set to an absolute path, as
+// the XDG specification requires, or else os.UserCacheDir.
+func userCacheDir() (string, error) {
+	if dir := os.Getenv(xdgCacheHomeEnv); filepath.IsAbs(dir) {
+		return dir, nil
+	}
+	return os.UserCacheDir()
+}
+
 // NewCacheWithDir creates a Cache with a custom base directory (useful for testing).
 func NewCacheWithDir(baseDir string) *Cache {
 	return &Cache{baseDir: baseDir}
 }
 
-// cacheFilePath returns the path to the cache file for an organization.
+// SetClock replaces the source of the current time, which stamps saved data
+// and decides expiry (useful for testing).
+func (c *Cache) SetClock(now func() time.Time) {
+	c.now = now
+}
+
+// clock returns the current time from the cache's clock.
+func (c *Cache) clock() time.Time {
+	if c.now == nil {
+		return time.Now()
+	}
+	return c.now()
+}
+
+// cacheKey returns the file name stem for an organization. GitHub names are
+// case-insensitive, so differently cased names share one cache entry.
+func cacheKey(org string) string {
+	return strings.ToLower(org)
+}
+
+// cacheFilePath returns the path to the compressed cache file for an organization.
 func (c *Cache) cacheFilePath(org string) string {
-	return filepath.Join(c.baseDir, org+".json")
+	return filepath.Join(c.baseDir, cacheKey(org)+".json.gz")
+}
+
+// legacyCacheFilePath returns the path to the uncompressed cache file written
+// by earlier versions, which is still read if no compressed file exists.
+func (c *Cache) legacyCacheFilePath(org string) string {
+	return filepath.Join(c.baseDir, cacheKey(org)+".json")
 }
 
-// Save stores organization repository data to the cache.
+// Save stores organization repository data to the cache as gzipped JSON.
+// An organization with no repositories is cached like any other, so it is
+// not fetched again until the cache expires. Files are replaced atomically
+// under an advisory lock, so concurrent saves of one organization, even from
+// separate processes, leave one complete version with a matching summary.
 func (c *Cache) Save(data OrganizationCache) error {
 	if err := os.MkdirAll(c.baseDir, 0755); err != nil {
 		return err
 	}
 
-	data.FetchedAt = time.Now()
+	// Record a known-empty organization as an empty list rather than null
+	if data.Repositories == nil {
+		data.Repositories = []Repository{}
+	}
+	data.FetchedAt = c.clock()
+	return c.write(data)
+}
+
+// write stores organization data as gzipped JSON, preserving its FetchedAt,
+// and updates its summary file to match.
+func (c *Cache) write(data OrganizationCache) error {
+	if err := ValidateOrganization(data.Organization); err != nil {
+		return err
+	}
+
+	unlock, err := c.lock(data.Organization, true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(jsonData); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+
+	if err := writeFileAtomic(c.cacheFilePath(data.Organization), buf.Bytes(), 0644); err != nil {
+		return err
+	}
+	if err := c.writeSummary(data); err != nil {
+		return err
+	}
+
+	// Remove any uncompressed cache left by an earlier version
+	if err := os.Remove(c.legacyCacheFilePath(data.Organization)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// readCacheFile reads the cache file for an organization, falling back to the
+// legacy uncompressed file, and decompresses it if it is gzipped.
+func (c *Cache) readCacheFile(org string) ([]byte, error) {
+	if err := ValidateOrganization(org); err != nil {
+		return nil, err
+	}
+
+	unlock, err := c.lock(org, false)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	raw, err := os.ReadFile(c.cacheFilePath(org))
+	if os.IsNotExist(err) {
+		raw, err = os.ReadFile(c.legacyCacheFilePath(org))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Sniff the gzip magic bytes rather than trusting the extension
+	if len(raw) < 2 || raw[0] != 0x1f || raw[1] != 0x8b {
+		return raw, nil
+	}
+
+	zr, err := gzip.NewReader(bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCacheCorrupt, err)
+	}
+	defer zr.Close()
+
+	data, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCacheCorrupt, err)
+	}
+	return data, nil
+}
 
-	jsonData, err := json.MarshalIndent(data, "", "  ")
+// writeFileAtomic writes data to a temporary file beside path and renames it
+// into place, so an interrupted write cannot leave a truncated file behind.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
 	if err != nil {
 		return err
 	}
+	defer os.Remove(f.Name()) // Fails harmlessly once renamed
 
-	return os.WriteFile(c.cacheFilePath(data.Organization), jsonData, 0644)
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Chmod(perm); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
 }
 
 // Load retrieves organization repository data from the cache.
-// Returns ErrCacheNotFound if no cache exists, or ErrCacheExpired if cache is stale.
+// Returns ErrCacheNotFound if no cache exists, ErrCacheExpired if cache is stale,
+// or ErrCacheCorrupt if the cache file cannot be decoded.
 func (c *Cache) Load(org string) (OrganizationCache, error) {
-	return c.LoadWithTime(org, time.Now())
+	return c.LoadWithTime(org, c.clock())
 }
 
 // LoadWithTime retrieves organization data using a specific reference time (for testing).
 func (c *Cache) LoadWithTime(org string, now time.Time) (OrganizationCache, error) {
 	var data OrganizationCache
 
-	jsonData, err := os.ReadFile(c.cacheFilePath(org))
+	jsonData, err := c.readCacheFile(org)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return data, ErrCacheNotFound
@@ -94,7 +342,7 @@ func (c *Cache) LoadWithTime(org string, now time.Time) (OrganizationCache, erro
 	}
 
 	if err := json.Unmarshal(jsonData, &data); err != nil {
-		return data, err
+		return OrganizationCache{}, fmt.Errorf("%w: %v", ErrCacheCorrupt, err)
 	}
 
 	if now.Sub(data.FetchedAt) > cacheValidity {
@@ -104,6 +352,76 @@ func (c *Cache) LoadWithTime(org string, now time.Time) (OrganizationCache, erro
 	return data, nil
 }
 
+// LoadAll retrieves every valid cached organization, sorted by cache key.
+// Organizations whose caches have expired are skipped and returned by name;
+// corrupt caches are skipped with a warning.
+// Caches of repository lists from ScanRepositories are not organizations and
+// are left out.
+func (c *Cache) LoadAll() ([]OrganizationCache, []string, error) {
+	return c.LoadAllWithTime(c.clock())
+}
+
+// LoadAllWithTime retrieves every valid cached organization using a specific reference time (for testing).
+func (c *Cache) LoadAllWithTime(now time.Time) ([]OrganizationCache, []string, error) {
+	keys, err := c.organizations()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var caches []OrganizationCache
+	var expired []string
+	for _, key := range keys {
+		if isRepoListKey(key) {
+			continue
+		}
+		data, err := c.LoadWithTime(key, now)
+		switch {
+		case err == nil:
+			caches = append(caches, data)
+		case errors.Is(err, ErrCacheExpired):
+			expired = append(expired, data.Organization)
+		case errors.Is(err, ErrCacheCorrupt):
+			slog.Warn("skipping corrupt cache file; the next scan of the organization replaces it", "org", key, "error", err)
+		default:
+			return nil, nil, fmt.Errorf("failed to load cache for %s: %w", key, err)
+		}
+	}
+
+	return caches, expired, nil
+}
+
+// organizations returns the cache key of every organization with a cache file,
+// sorted. An organization may have both a compressed and a legacy file; it is
+// listed once and Load reads the right one.
+func (c *Cache) organizations() ([]string, error) {
+	entries, err := os.ReadDir(c.baseDir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cache directory: %w", err)
+	}
+
+	var keys []string
+	seen := make(map[string]bool)
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasSuffix(name, historyFileSuffix) || strings.HasSuffix(name, summaryFileSuffix) {
+			continue
+		}
+		key, ok := strings.CutSuffix(name, ".json.gz")
+		if !ok {
+			key, ok = strings.CutSuffix(name, ".json")
+		}
+		if !ok || seen[key] || ValidateOrganization(key) != nil {
+			continue
+		}
+		seen[key] = true
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
 // IsValid checks if a valid (non-expired) cache exists for the organization.
 func (c *Cache) IsValid(org string) bool {
 	_, err := c.Load(org)
@@ -116,18 +434,74 @@ func (c *Cache) IsValidWithTime(org string, now time.Time) bool {
 	return err == nil
 }
 
-// Clear removes the cache file for an organization.
+// Clear removes the cache and summary files for an organization. Its lock
+// file is left in place, as another process may be waiting on it.
 func (c *Cache) Clear(org string) error {
-	err := os.Remove(c.cacheFilePath(org))
+	if err := ValidateOrganization(org); err != nil {
+		return err
+	}
+
+	unlock, err := c.lock(org, true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	for _, path := range []string{c.cacheFilePath(org), c.legacyCacheFilePath(org), c.summaryFilePath(org)} {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// ClearAll removes the files patina keeps in the cache directory and returns
+// how many organizations were cached. Other files are left alone, as the
+// directory may be shared, and repository lists are not counted.
+func (c *Cache) ClearAll() (int, error) {
+	keys, err := c.organizations()
+	if err != nil {
+		return 0, err
+	}
+	entries, err := os.ReadDir(c.baseDir)
 	if os.IsNotExist(err) {
-		return nil
+		return 0, nil
 	}
-	return err
+	if err != nil {
+		return 0, fmt.Errorf("failed to read cache directory: %w", err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !isCacheFile(entry.Name()) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(c.baseDir, entry.Name())); err != nil && !os.IsNotExist(err) {
+			return 0, err
+		}
+	}
+
+	n := 0
+	for _, key := range keys {
+		if !isRepoListKey(key) {
+			n++
+		}
+	}
+	return n, nil
 }
 
-// ClearAll removes all cache files.
-func (c *Cache) ClearAll() error {
-	return os.RemoveAll(c.baseDir)
+// isCacheFile reports whether a file name is one patina writes to the cache
+// directory: a cache, summary, history, or lock file, or a temporary file
+// left by an interrupted write.
+func isCacheFile(name string) bool {
+	if strings.Contains(name, ".json.tmp") || strings.Contains(name, ".json.gz.tmp") {
+		return true
+	}
+	for _, suffix := range []string{".json.gz", ".json", lockFileSuffix} {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
 }
 
 // CacheDir returns the cache directory path.
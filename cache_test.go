@@ -1,8 +1,13 @@
 package patina
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -87,6 +92,112 @@ func TestCacheExpired(t *testing.T) {
 	}
 }
 
+func TestCacheCorrupt(t *testing.T) {
+	cache := NewCacheWithDir(t.TempDir())
+	if err := cache.Save(OrganizationCache{Organization: "test-org", Repositories: []Repository{{Name: "api"}}}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	raw, err := os.ReadFile(cache.cacheFilePath("test-org"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		path string
+		data []byte
+	}{
+		{"truncated gzip", cache.cacheFilePath("test-org"), raw[:len(raw)/2]},
+		{"invalid JSON", cache.legacyCacheFilePath("test-org"), []byte(`{"organization":"test-org","repositories":[`)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache.Clear("test-org")
+			if err := os.WriteFile(tt.path, tt.data, 0644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := cache.Load("test-org"); !errors.Is(err, ErrCacheCorrupt) {
+				t.Errorf("Load() error = %v, want %v", err, ErrCacheCorrupt)
+			}
+		})
+	}
+}
+
+func TestCacheSaveLeavesNoTemporaryFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	cache := NewCacheWithDir(tmpDir)
+
+	for range 2 {
+		if err := cache.Save(OrganizationCache{Organization: "test-org"}); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+
+	entries, err := os.ReadDir(tmpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		if strings.Contains(entry.Name(), ".tmp") {
+			t.Errorf("temporary file %s left in the cache directory", entry.Name())
+		}
+	}
+	if info, err := os.Stat(cache.cacheFilePath("test-org")); err != nil || info.Mode().Perm() != 0644 {
+		t.Errorf("cache file mode = %v, %v, want 0644", info.Mode().Perm(), err)
+	}
+}
+
+func TestCacheConcurrentSaves(t *testing.T) {
+	tmpDir := t.TempDir()
+	cache := NewCacheWithDir(tmpDir)
+
+	const writers = 8
+	var wg sync.WaitGroup
+	for i := range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			repos := make([]Repository, 200)
+			for j := range repos {
+				repos[j] = Repository{Name: fmt.Sprintf("writer%d-repo%d", i, j)}
+			}
+			if err := cache.Save(OrganizationCache{Organization: "test-org", Repositories: repos}); err != nil {
+				t.Errorf("Save() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	loaded, err := cache.Load("test-org")
+	if err != nil {
+		t.Fatalf("Load() error = %v after concurrent saves", err)
+	}
+	if len(loaded.Repositories) != 200 {
+		t.Fatalf("len(Repositories) = %d, want 200 from a single save", len(loaded.Repositories))
+	}
+	writer, _, _ := strings.Cut(loaded.Repositories[0].Name, "-")
+	for _, repo := range loaded.Repositories {
+		if !strings.HasPrefix(repo.Name, writer+"-") {
+			t.Fatalf("repository %s mixed into the save of %s", repo.Name, writer)
+		}
+	}
+
+	if _, err := cache.LoadSummary("test-org"); err != nil {
+		t.Errorf("LoadSummary() error = %v after concurrent saves", err)
+	}
+
+	entries, err := os.ReadDir(tmpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		if strings.Contains(entry.Name(), ".tmp") {
+			t.Errorf("temporary file %s left in the cache directory", entry.Name())
+		}
+	}
+}
+
 func TestCacheIsValid(t *testing.T) {
 	tmpDir := t.TempDir()
 	cache := NewCacheWithDir(tmpDir)
@@ -160,16 +271,42 @@ func TestCacheClearAll(t *testing.T) {
 		if err := cache.Save(data); err != nil {
 			t.Fatalf("Save() error = %v", err)
 		}
+		if err := cache.AppendHistory(org, HistoryEntry{Timestamp: time.Now()}); err != nil {
+			t.Fatalf("AppendHistory() error = %v", err)
+		}
+	}
+	// A repository list is cleared but not counted as an organization
+	if err := cache.Save(OrganizationCache{Organization: repoListKey([]string{"org1/api"})}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	for _, name := range []string{"org1.json.gz.tmp123", "notes.txt", "org1.json.gz.bak"} {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), nil, 0644); err != nil {
+			t.Fatalf("WriteFile() error = %v", err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(tmpDir, "photos"), 0755); err != nil {
+		t.Fatalf("Mkdir() error = %v", err)
 	}
 
-	if err := cache.ClearAll(); err != nil {
+	n, err := cache.ClearAll()
+	if err != nil {
 		t.Fatalf("ClearAll() error = %v", err)
 	}
+	if n != len(orgs) {
+		t.Errorf("ClearAll() = %d, want %d", n, len(orgs))
+	}
 
-	for _, org := range orgs {
-		if cache.IsValid(org) {
-			t.Errorf("IsValid(%q) = true after ClearAll(), want false", org)
-		}
+	entries, err := os.ReadDir(tmpDir)
+	if err != nil {
+		t.Fatalf("ReadDir() error = %v", err)
+	}
+	var left []string
+	for _, entry := range entries {
+		left = append(left, entry.Name())
+	}
+	// Files patina did not write are left in place
+	if got := strings.Join(left, ","); got != "notes.txt,org1.json.gz.bak,photos" {
+		t.Errorf("files left after ClearAll() = %s, want notes.txt,org1.json.gz.bak,photos", got)
 	}
 }
 
@@ -186,13 +323,102 @@ func TestCacheFilePath(t *testing.T) {
 	tmpDir := t.TempDir()
 	cache := NewCacheWithDir(tmpDir)
 
-	expected := filepath.Join(tmpDir, "my-org.json")
+	expected := filepath.Join(tmpDir, "my-org.json.gz")
 	if got := cache.cacheFilePath("my-org"); got != expected {
 		t.Errorf("cacheFilePath() = %v, want %v", got, expected)
 	}
 }
 
+func TestCacheCompression(t *testing.T) {
+	tmpDir := t.TempDir()
+	cache := NewCacheWithDir(tmpDir)
+
+	var repos []Repository
+	for i := 0; i < 500; i++ {
+		name := fmt.Sprintf("repo%d", i)
+		repos = append(repos, Repository{
+			Name:        name,
+			FullName:    "test-org/" + name,
+			LastUpdated: time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC),
+			HTMLURL:     "https://github.com/test-org/" + name,
+		})
+	}
+
+	data := OrganizationCache{
+		Organization: "test-org",
+		Repositories: repos,
+	}
+
+	if err := cache.Save(data); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	info, err := os.Stat(cache.cacheFilePath("test-org"))
+	if err != nil {
+		t.Fatalf("Stat() error = %v", err)
+	}
+
+	uncompressed, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		t.Fatalf("MarshalIndent() error = %v", err)
+	}
+
+	if info.Size()*5 > int64(len(uncompressed)) {
+		t.Errorf("compressed size = %d, want less than a fifth of %d", info.Size(), len(uncompressed))
+	}
+
+	loaded, err := cache.Load("test-org")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if len(loaded.Repositories) != len(repos) {
+		t.Fatalf("len(Repositories) = %d, want %d", len(loaded.Repositories), len(repos))
+	}
+	if loaded.Repositories[499].FullName != "test-org/repo499" {
+		t.Errorf("Repositories[499].FullName = %v, want test-org/repo499", loaded.Repositories[499].FullName)
+	}
+}
+
+func TestCacheLoadLegacyUncompressed(t *testing.T) {
+	tmpDir := t.TempDir()
+	cache := NewCacheWithDir(tmpDir)
+
+	data := OrganizationCache{
+		Organization: "test-org",
+		FetchedAt:    time.Now(),
+		Repositories: []Repository{{Name: "repo1", FullName: "test-org/repo1"}},
+	}
+
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		t.Fatalf("MarshalIndent() error = %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "test-org.json"), jsonData, 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	loaded, err := cache.Load("test-org")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(loaded.Repositories) != 1 || loaded.Repositories[0].Name != "repo1" {
+		t.Errorf("Repositories = %v, want [repo1]", loaded.Repositories)
+	}
+
+	// Saving replaces the legacy file with a compressed one
+	if err := cache.Save(loaded); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "test-org.json")); !os.IsNotExist(err) {
+		t.Errorf("legacy cache file still exists after Save(), err = %v", err)
+	}
+}
+
 func TestNewCache(t *testing.T) {
+	t.Setenv(CacheDirEnv, "")
+	t.Setenv(xdgCacheHomeEnv, "")
+
 	cache, err := NewCache()
 	if err != nil {
 		t.Fatalf("NewCache() error = %v", err)
@@ -205,6 +431,35 @@ func TestNewCache(t *testing.T) {
 	}
 }
 
+func TestNewCacheHonoursXDGCacheHome(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv(CacheDirEnv, "")
+	t.Setenv(xdgCacheHomeEnv, tmpDir)
+
+	cache, err := NewCache()
+	if err != nil {
+		t.Fatalf("NewCache() error = %v", err)
+	}
+
+	if want := filepath.Join(tmpDir, cacheDirName); cache.CacheDir() != want {
+		t.Errorf("CacheDir() = %v, want %v", cache.CacheDir(), want)
+	}
+}
+
+func TestNewCacheHonoursEnv(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv(CacheDirEnv, tmpDir)
+
+	cache, err := NewCache()
+	if err != nil {
+		t.Fatalf("NewCache() error = %v", err)
+	}
+
+	if cache.CacheDir() != tmpDir {
+		t.Errorf("CacheDir() = %v, want %v", cache.CacheDir(), tmpDir)
+	}
+}
+
 func TestCacheFetchedAtIsSetOnSave(t *testing.T) {
 	tmpDir := t.TempDir()
 	cache := NewCacheWithDir(tmpDir)
@@ -231,3 +486,172 @@ func TestCacheFetchedAtIsSetOnSave(t *testing.T) {
 		t.Errorf("FetchedAt = %v, want between %v and %v", loaded.FetchedAt, beforeSave, afterSave)
 	}
 }
+
+func TestCacheOrganizationIsCaseInsensitive(t *testing.T) {
+	tmpDir := t.TempDir()
+	cache := NewCacheWithDir(tmpDir)
+
+	data := OrganizationCache{
+		Organization: "MyOrg",
+		Repositories: []Repository{{Name: "repo1"}},
+	}
+	if err := cache.Save(data); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	loaded, err := cache.Load("myorg")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if loaded.Organization != "MyOrg" {
+		t.Errorf("Organization = %q, want original casing %q", loaded.Organization, "MyOrg")
+	}
+
+	if _, err := os.Stat(filepath.Join(tmpDir, "myorg.json.gz")); err != nil {
+		t.Errorf("expected lowercased cache file: %v", err)
+	}
+}
+
+func TestValidateOrganization(t *testing.T) {
+	tests := []struct {
+		org     string
+		wantErr bool
+	}{
+		{"my-org", false},
+		{"MyOrg123", false},
+		{"octo_shortcode", false},
+		{"", true},
+		{"-leading", true},
+		{"../evil", true},
+		{"a/b", true},
+		{"my org", true},
+		{strings.Repeat("a", 101), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.org, func(t *testing.T) {
+			err := ValidateOrganization(tt.org)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateOrganization(%q) error = %v, wantErr %v", tt.org, err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidOrganization) {
+				t.Errorf("ValidateOrganization(%q) error = %v, want ErrInvalidOrganization", tt.org, err)
+			}
+		})
+	}
+}
+
+func TestCacheRejectsPathTraversal(t *testing.T) {
+	parent := t.TempDir()
+	cacheDir := filepath.Join(parent, "cache")
+	cache := NewCacheWithDir(cacheDir)
+
+	err := cache.Save(OrganizationCache{Organization: "../evil"})
+	if !errors.Is(err, ErrInvalidOrganization) {
+		t.Errorf("Save() error = %v, want ErrInvalidOrganization", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(parent, "evil.json.gz")); !os.IsNotExist(err) {
+		t.Errorf("cache file written outside cache dir: %v", err)
+	}
+
+	if _, err := cache.Load("../evil"); !errors.Is(err, ErrInvalidOrganization) {
+		t.Errorf("Load() error = %v, want ErrInvalidOrganization", err)
+	}
+	if err := cache.Clear("../evil"); !errors.Is(err, ErrInvalidOrganization) {
+		t.Errorf("Clear() error = %v, want ErrInvalidOrganization", err)
+	}
+}
+
+func TestCacheLoadAll(t *testing.T) {
+	cache := NewCacheWithDir(t.TempDir())
+
+	for _, org := range []string{"org-b", "Org-A", "old-org"} {
+		if err := cache.Save(OrganizationCache{Organization: org, Repositories: []Repository{{Name: "repo"}}}); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+	if err := cache.AppendHistory("org-b", HistoryEntry{Timestamp: time.Now()}); err != nil {
+		t.Fatalf("AppendHistory() error = %v", err)
+	}
+
+	// Backdate one organization so its cache has expired
+	old, err := cache.Load("old-org")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	old.FetchedAt = time.Now().Add(-31 * 24 * time.Hour)
+	if err := cache.write(old); err != nil {
+		t.Fatalf("write() error = %v", err)
+	}
+
+	caches, expired, err := cache.LoadAll()
+	if err != nil {
+		t.Fatalf("LoadAll() error = %v", err)
+	}
+
+	var orgs []string
+	for _, c := range caches {
+		orgs = append(orgs, c.Organization)
+	}
+	if strings.Join(orgs, ",") != "Org-A,org-b" {
+		t.Errorf("organizations = %v, want [Org-A org-b]", orgs)
+	}
+	if strings.Join(expired, ",") != "old-org" {
+		t.Errorf("expired = %v, want [old-org]", expired)
+	}
+
+	// A missing cache directory has nothing to load
+	caches, expired, err = NewCacheWithDir(filepath.Join(t.TempDir(), "missing")).LoadAll()
+	if err != nil || caches != nil || expired != nil {
+		t.Errorf("LoadAll() on missing directory = %v, %v, %v; want nil, nil, nil", caches, expired, err)
+	}
+}
+
+func TestCacheClock(t *testing.T) {
+	cache := NewCacheWithDir(t.TempDir())
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	cache.SetClock(func() time.Time { return now })
+
+	if err := cache.Save(OrganizationCache{Organization: "org"}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	data, err := cache.Load("org")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !data.FetchedAt.Equal(now) {
+		t.Errorf("FetchedAt = %v, want %v", data.FetchedAt, now)
+	}
+
+	now = now.Add(cacheValidity + time.Second)
+	if _, err := cache.Load("org"); !errors.Is(err, ErrCacheExpired) {
+		t.Errorf("Load() after expiry error = %v, want ErrCacheExpired", err)
+	}
+	if cache.IsValid("org") {
+		t.Error("IsValid() = true after expiry")
+	}
+}
+
+func TestCacheEmptyOrganization(t *testing.T) {
+	cache := NewCacheWithDir(t.TempDir())
+
+	if err := cache.Save(OrganizationCache{Organization: "empty-org"}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	data, err := cache.Load("empty-org")
+	if err != nil {
+		t.Fatalf("Load() error = %v, want an empty but valid cache", err)
+	}
+	if data.Repositories == nil || len(data.Repositories) != 0 {
+		t.Errorf("Repositories = %#v, want an empty list", data.Repositories)
+	}
+	if data.FetchedAt.IsZero() {
+		t.Error("FetchedAt is zero")
+	}
+	if !cache.IsValid("empty-org") {
+		t.Error("IsValid() = false for a cached empty organization")
+	}
+}
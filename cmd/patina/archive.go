@@ -0,0 +1,170 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/scottbrown/patina"
+	"github.com/spf13/cobra"
+)
+
+var (
+	archiveFreshness string
+	archiveDryRun    bool
+	archiveForce     bool
+	archiveExempt    string
+	archiveForks     bool
+	archiveMirrors   bool
+)
+
+var archiveCmd = &cobra.Command{
+	Use:   "archive <organization>",
+	Short: "Archive an organization's stale repositories",
+	Long: `Archive makes an organization's repositories at one freshness level,
+red by default, read-only on GitHub. Archived repositories can be
+unarchived from their settings page.
+
+By default archive only lists the repositories it would archive. Pass
+--dry-run=false to archive them; archive then refreshes the repository
+data from GitHub, so a repository pushed to since the cache was written
+is not archived, and asks for confirmation. Use --force to skip the
+confirmation, for example in scripts. The credentials need admin rights
+to each repository.
+
+Green repositories are never archived; choose yellow or red.
+
+Use --exemptions to skip known-stale repositories that should stay
+unarchived. Forked and mirrored repositories are skipped unless
+--include-forks or --include-mirrors is given; archived repositories are
+always skipped.
+
+Example:
+  patina archive my-org
+  patina archive my-org --freshness red --exemptions exemptions.csv --dry-run=false`,
+	Args: cobra.ExactArgs(1),
+	RunE: runArchive,
+}
+
+func init() {
+	archiveCmd.Flags().StringVarP(&archiveFreshness, "freshness", "f", string(patina.FreshnessRed), "Archive repos at this freshness (yellow, red)")
+	archiveCmd.Flags().BoolVar(&archiveDryRun, "dry-run", true, "Only list the repos that would be archived; pass --dry-run=false to archive them")
+	archiveCmd.Flags().BoolVarP(&archiveForce, "force", "y", false, "Archive without asking for confirmation")
+	archiveCmd.Flags().StringVar(&archiveExempt, "exemptions", "", "CSV file of repo,reason rows; repos listed are never archived")
+	archiveCmd.Flags().BoolVar(&archiveForks, "include-forks", false, "Include forked repositories")
+	archiveCmd.Flags().BoolVar(&archiveMirrors, "include-mirrors", false, "Include mirrors of repositories maintained elsewhere")
+}
+
+func runArchive(cmd *cobra.Command, args []string) error {
+	org := args[0]
+
+	freshness, ok := patina.ParseFreshness(archiveFreshness)
+	if !ok {
+		return fmt.Errorf("invalid freshness value: %q (must be yellow or red)", archiveFreshness)
+	}
+	if freshness == patina.FreshnessGreen {
+		return fmt.Errorf("refusing to archive green repositories: they are actively maintained (use yellow or red)")
+	}
+
+	exemptions, err := readExemptionsFile(archiveExempt)
+	if err != nil {
+		return err
+	}
+
+	client, err := newClient()
+	if err != nil {
+		return fmt.Errorf("failed to initialize scanner: %w", err)
+	}
+	cache, err := newCache()
+	if err != nil {
+		return fmt.Errorf("failed to initialize scanner: %w", err)
+	}
+	return archiveOrg(cmd, org, client, cache, freshness, exemptions)
+}
+
+// archiveOrg lists, and unless --dry-run is set archives, an organization's
+// unexempted repositories at a freshness level.
+func archiveOrg(cmd *cobra.Command, org string, client patina.GitHubClient, cache *patina.Cache, freshness patina.Freshness, exemptions patina.Exemptions) error {
+	w := cmd.OutOrStdout()
+	scanner := patina.NewScannerWithDeps(client, cache)
+
+	// Never archive on the word of a cache that may predate a recent push
+	result, err := scanner.Scan(org, patina.ScanOptions{Refresh: !archiveDryRun, IncludeForks: archiveForks, IncludeMirrors: archiveMirrors, ActivityMetric: activityMetric, Freshness: freshnessOptions})
+	if err != nil {
+		return fmt.Errorf("failed to scan organization: %w", err)
+	}
+	warnCacheWrite(result)
+	printScanSummary(cmd.ErrOrStderr(), result)
+
+	now := time.Now()
+	repos := archiveCandidates(result.Repositories, freshness, exemptions, now)
+	printArchivePlan(w, org, repos, freshness, now)
+	if len(repos) == 0 {
+		return nil
+	}
+
+	if archiveDryRun {
+		fmt.Fprintln(statusWriter(w), "\nDry run: nothing was archived. Pass --dry-run=false to archive these repositories.")
+		return nil
+	}
+
+	question := fmt.Sprintf("\nArchive %d repositories in %s? They become read-only.", len(repos), org)
+	if !archiveForce && !confirm(cmd.InOrStdin(), w, question) {
+		fmt.Fprintln(w, "Aborted.")
+		return nil
+	}
+
+	archived, err := archiveRepositories(w, client, repos)
+	if archived > 0 {
+		// The cache still lists the repositories as unarchived
+		if err := cache.Clear(org); err != nil {
+			slog.Warn("failed to clear the cache after archiving", "org", org, "error", err)
+		}
+	}
+	return err
+}
+
+// archiveCandidates returns the unexempted repositories at a freshness level,
+// oldest first.
+func archiveCandidates(repos []patina.Repository, freshness patina.Freshness, exemptions patina.Exemptions, now time.Time) []patina.Repository {
+	candidates := exemptions.Exclude(freshnessOptions.FilterByFreshness(repos, freshness, now))
+	patina.SortByAge(candidates)
+	return candidates
+}
+
+// printArchivePlan lists the repositories that would be archived with their ages.
+func printArchivePlan(w io.Writer, org string, repos []patina.Repository, freshness patina.Freshness, now time.Time) {
+	if len(repos) == 0 {
+		fmt.Fprintf(w, "No %s repositories to archive in %s\n", freshness, org)
+		return
+	}
+
+	fmt.Fprintf(w, "Would archive %d %s repositories in %s:\n", len(repos), freshness, org)
+	t := &table{}
+	for _, repo := range repos {
+		t.addRow("  "+repo.FullName, freshnessOptions.Age(repo.LastUpdated, now))
+	}
+	t.render(w)
+}
+
+// archiveRepositories archives each repository in turn, reporting each one,
+// and returns how many were archived. A failure does not stop the others;
+// the error reports how many failed.
+func archiveRepositories(w io.Writer, client patina.GitHubClient, repos []patina.Repository) (int, error) {
+	var archived, failed int
+	for _, repo := range repos {
+		if err := patina.ArchiveRepository(client, repo.FullName); err != nil {
+			slog.Error("failed to archive repository", "repo", repo.FullName, "error", err)
+			failed++
+			continue
+		}
+		fmt.Fprintf(w, "Archived %s\n", repo.FullName)
+		archived++
+	}
+
+	if failed > 0 {
+		return archived, fmt.Errorf("failed to archive %d of %d repositories", failed, len(repos))
+	}
+	return archived, nil
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scottbrown/patina"
+	"github.com/spf13/cobra"
+)
+
+// fakeArchiver lists repos and records the repositories it is asked to
+// archive, failing for those in fail.
+type fakeArchiver struct {
+	repos    []patina.Repository
+	archived []string
+	fail     map[string]bool
+}
+
+func (f *fakeArchiver) FetchRepositories(org string) ([]patina.Repository, error) {
+	return f.repos, nil
+}
+
+func (f *fakeArchiver) ArchiveRepository(fullName string) error {
+	if f.fail[fullName] {
+		return errors.New("forbidden")
+	}
+	f.archived = append(f.archived, fullName)
+	return nil
+}
+
+func TestArchiveCandidates(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	repos := []patina.Repository{
+		{Name: "old", FullName: "org/old", LastUpdated: now.AddDate(-1, 0, 0)},
+		{Name: "frozen", FullName: "org/frozen", LastUpdated: now.AddDate(-3, 0, 0)},
+		{Name: "oldest", FullName: "org/oldest", LastUpdated: now.AddDate(-2, 0, 0)},
+		{Name: "web", FullName: "org/web", LastUpdated: now.AddDate(0, 0, -3)},
+	}
+
+	got := archiveCandidates(repos, patina.FreshnessRed, patina.Exemptions{"frozen": "reference"}, now)
+	var names []string
+	for _, repo := range got {
+		names = append(names, repo.Name)
+	}
+	if strings.Join(names, ",") != "oldest,old" {
+		t.Errorf("archiveCandidates() = %v, want [oldest old]: red, unexempted, oldest first", names)
+	}
+}
+
+func TestPrintArchivePlan(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	repos := []patina.Repository{{FullName: "org/legacy", LastUpdated: now.AddDate(-1, 0, 0)}}
+
+	var b strings.Builder
+	printArchivePlan(&b, "org", repos, patina.FreshnessRed, now)
+	if want := "Would archive 1 red repositories in org:\n  org/legacy  1 year ago\n"; b.String() != want {
+		t.Errorf("printArchivePlan() = %q, want %q", b.String(), want)
+	}
+
+	b.Reset()
+	printArchivePlan(&b, "org", nil, patina.FreshnessRed, now)
+	if want := "No red repositories to archive in org\n"; b.String() != want {
+		t.Errorf("printArchivePlan() = %q, want %q", b.String(), want)
+	}
+}
+
+func TestArchiveRepositories(t *testing.T) {
+	client := &fakeArchiver{fail: map[string]bool{"org/locked": true}}
+	repos := []patina.Repository{{FullName: "org/a"}, {FullName: "org/locked"}, {FullName: "org/b"}}
+
+	var b strings.Builder
+	archived, err := archiveRepositories(&b, client, repos)
+	if err == nil || !strings.Contains(err.Error(), "1 of 3") {
+		t.Errorf("archiveRepositories() error = %v, want 1 of 3 failed", err)
+	}
+	if archived != 2 || strings.Join(client.archived, ",") != "org/a,org/b" {
+		t.Errorf("archived %d: %v, want org/a and org/b despite the failure", archived, client.archived)
+	}
+	if b.String() != "Archived org/a\nArchived org/b\n" {
+		t.Errorf("output = %q", b.String())
+	}
+}
+
+func TestArchiveOrg(t *testing.T) {
+	prevDryRun, prevForce := archiveDryRun, archiveForce
+	t.Cleanup(func() { archiveDryRun, archiveForce = prevDryRun, prevForce })
+
+	now := time.Now()
+	repos := []patina.Repository{
+		{Name: "old", FullName: "org/old", LastUpdated: now.AddDate(-1, 0, 0)},
+		{Name: "frozen", FullName: "org/frozen", LastUpdated: now.AddDate(-3, 0, 0)},
+		{Name: "oldest", FullName: "org/oldest", LastUpdated: now.AddDate(-2, 0, 0)},
+		{Name: "web", FullName: "org/web", LastUpdated: now.AddDate(0, 0, -3)},
+	}
+	exemptions := patina.Exemptions{"frozen": "reference"}
+
+	run := func(input string, dryRun, force bool) (*fakeArchiver, string) {
+		t.Helper()
+		archiveDryRun, archiveForce = dryRun, force
+		client := &fakeArchiver{repos: repos}
+		cmd := &cobra.Command{}
+		var out, errOut strings.Builder
+		cmd.SetIn(strings.NewReader(input))
+		cmd.SetOut(&out)
+		cmd.SetErr(&errOut)
+		if err := archiveOrg(cmd, "org", client, patina.NewCacheWithDir(t.TempDir()), patina.FreshnessRed, exemptions); err != nil {
+			t.Fatalf("archiveOrg() error = %v", err)
+		}
+		return client, out.String()
+	}
+
+	// The defaults only list what would be archived, even when told yes
+	client, out := run("y\n", true, false)
+	if len(client.archived) != 0 {
+		t.Errorf("dry run archived %v, want nothing", client.archived)
+	}
+	if !strings.Contains(out, "Would archive 2 red repositories") || !strings.Contains(out, "Dry run") {
+		t.Errorf("dry run output = %q", out)
+	}
+
+	for _, input := range []string{"n\n", ""} {
+		client, out := run(input, false, false)
+		if len(client.archived) != 0 {
+			t.Errorf("answer %q archived %v, want nothing", input, client.archived)
+		}
+		if !strings.Contains(out, "Aborted.") {
+			t.Errorf("answer %q output = %q, want Aborted.", input, out)
+		}
+	}
+
+	client, _ = run("", false, true)
+	if got := strings.Join(client.archived, ","); got != "org/oldest,org/old" {
+		t.Errorf("--force archived %s, want org/oldest,org/old: red and unexempted", got)
+	}
+}
+
+func TestRunArchiveRejectsGreen(t *testing.T) {
+	prev := archiveFreshness
+	t.Cleanup(func() { archiveFreshness = prev })
+	archiveFreshness = "green"
+
+	err := runArchive(&cobra.Command{}, []string{"org"})
+	if err == nil || !strings.Contains(err.Error(), "green") {
+		t.Errorf("runArchive(--freshness green) error = %v, want a refusal", err)
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/scottbrown/patina"
+	"github.com/spf13/cobra"
+)
+
+var cacheForce bool
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage cached repository data",
+}
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear [organization]",
+	Short: "Remove cached repository data",
+	Long: `Clear removes cached repository data. With an organization, only that
+organization's cache is removed. Without one, every file patina keeps in
+the cache directory is removed after asking for confirmation; other files
+in the directory are left alone.
+
+Use --force to skip the confirmation, for example in scripts.
+
+Example:
+  patina cache clear my-org
+  patina cache clear --force`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runCacheClear,
+}
+
+func init() {
+	cacheClearCmd.Flags().BoolVarP(&cacheForce, "force", "y", false, "Clear every organization without asking for confirmation")
+	cacheCmd.AddCommand(cacheClearCmd)
+}
+
+func runCacheClear(cmd *cobra.Command, args []string) error {
+	w := cmd.OutOrStdout()
+
+	cache, err := newCache()
+	if err != nil {
+		return fmt.Errorf("failed to initialize cache: %w", err)
+	}
+
+	if len(args) == 1 {
+		org := args[0]
+		if err := cache.Clear(org); err != nil {
+			return fmt.Errorf("failed to clear cache for %s: %w", org, err)
+		}
+		fmt.Fprintf(w, "Cleared cache for %s\n", org)
+		return nil
+	}
+
+	if !cacheForce && !confirm(cmd.InOrStdin(), w, fmt.Sprintf("Remove all cached data in %s?", cache.CacheDir())) {
+		fmt.Fprintln(w, "Aborted.")
+		return nil
+	}
+
+	n, err := cache.ClearAll()
+	if err != nil {
+		return fmt.Errorf("failed to clear cache: %w", err)
+	}
+	fmt.Fprintf(w, "Removed %s\n", patina.Pluralize(n, "organization cache"))
+	return nil
+}
+
+// confirm asks a yes/no question and reports whether the answer was yes.
+// Anything other than y or yes, including no input, counts as no.
+func confirm(r io.Reader, w io.Writer, question string) bool {
+	fmt.Fprintf(w, "%s [y/N] ", question)
+	answer, _ := bufio.NewReader(r).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/scottbrown/patina"
+	"github.com/spf13/cobra"
+)
+
+func TestConfirm(t *testing.T) {
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{"y\n", true},
+		{"YES\n", true},
+		{"n\n", false},
+		{"\n", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		var b strings.Builder
+		if got := confirm(strings.NewReader(tt.input), &b, "Continue?"); got != tt.want {
+			t.Errorf("confirm(%q) = %t, want %t", tt.input, got, tt.want)
+		}
+		if b.String() != "Continue? [y/N] " {
+			t.Errorf("prompt = %q, want %q", b.String(), "Continue? [y/N] ")
+		}
+	}
+}
+
+func TestRunCacheClear(t *testing.T) {
+	prevDir, prevForce := cacheDir, cacheForce
+	t.Cleanup(func() { cacheDir, cacheForce = prevDir, prevForce })
+	cacheDir = t.TempDir()
+
+	cache := patina.NewCacheWithDir(cacheDir)
+	for _, org := range []string{"org-a", "org-b", "org-c"} {
+		if err := cache.Save(patina.OrganizationCache{Organization: org}); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+
+	run := func(input string, force bool, args ...string) string {
+		t.Helper()
+		cacheForce = force
+		cmd := &cobra.Command{}
+		var b strings.Builder
+		cmd.SetIn(strings.NewReader(input))
+		cmd.SetOut(&b)
+		if err := runCacheClear(cmd, args); err != nil {
+			t.Fatalf("runCacheClear() error = %v", err)
+		}
+		return b.String()
+	}
+
+	if got := run("", false, "org-a"); got != "Cleared cache for org-a\n" {
+		t.Errorf("clear org-a = %q", got)
+	}
+	if cache.IsValid("org-a") {
+		t.Error("org-a still cached after clear")
+	}
+
+	if got := run("n\n", false); !strings.HasSuffix(got, "Aborted.\n") {
+		t.Errorf("declined clear = %q, want Aborted", got)
+	}
+	if !cache.IsValid("org-b") {
+		t.Error("org-b removed after declining")
+	}
+
+	if got := run("", true); got != "Removed 2 organization caches\n" {
+		t.Errorf("forced clear = %q", got)
+	}
+	if cache.IsValid("org-b") || cache.IsValid("org-c") {
+		t.Error("caches remain after forced clear")
+	}
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/scottbrown/patina"
+	"github.com/spf13/cobra"
+)
+
+var (
+	cloneFreshness string
+	cloneProtocol  string
+	cloneRefresh   bool
+	cloneForks     bool
+	cloneMirrors   bool
+)
+
+// Protocols accepted by clone-script --protocol.
+const (
+	protocolHTTPS = "https"
+	protocolSSH   = "ssh"
+)
+
+var cloneScriptCmd = &cobra.Command{
+	Use:   "clone-script <organization>",
+	Short: "Print a shell script that clones an organization's repositories",
+	Long: `Clone-script prints a shell script with one git clone line per
+repository, for bulk operations such as archiving stale repos. Use
+--freshness to clone only repos at one freshness level, and --protocol to
+clone over https (default) or ssh.
+
+The script is written to stdout; progress messages go to stderr.
+Repositories whose clone URL is not cached are listed as comments in the
+script; use --refresh to fetch them.
+
+Forked, mirrored, and archived repositories are excluded. Use
+--include-forks or --include-mirrors to clone forks or mirrors as well.
+
+Example:
+  patina clone-script my-org --freshness red --protocol ssh > clone-stale.sh
+  sh clone-stale.sh`,
+	Args: cobra.ExactArgs(1),
+	RunE: runCloneScript,
+}
+
+func init() {
+	cloneScriptCmd.Flags().StringVarP(&cloneFreshness, "freshness", "f", "", "Only clone repos at this freshness (green, yellow, red)")
+	cloneScriptCmd.Flags().StringVar(&cloneProtocol, "protocol", protocolHTTPS, "Clone protocol (https, ssh)")
+	cloneScriptCmd.Flags().BoolVarP(&cloneRefresh, "refresh", "r", false, "Force refresh from GitHub API")
+	cloneScriptCmd.Flags().BoolVar(&cloneForks, "include-forks", false, "Include forked repositories")
+	cloneScriptCmd.Flags().BoolVar(&cloneMirrors, "include-mirrors", false, "Include mirrors of repositories maintained elsewhere")
+}
+
+func runCloneScript(cmd *cobra.Command, args []string) error {
+	org := args[0]
+
+	var freshness patina.Freshness
+	if cloneFreshness != "" {
+		f, ok := patina.ParseFreshness(cloneFreshness)
+		if !ok {
+			return fmt.Errorf("invalid freshness value: %q (must be green, yellow, or red)", cloneFreshness)
+		}
+		freshness = f
+	}
+	if cloneProtocol != protocolHTTPS && cloneProtocol != protocolSSH {
+		return fmt.Errorf("invalid protocol value: %q (must be https or ssh)", cloneProtocol)
+	}
+
+	scanner, err := newScanner()
+	if err != nil {
+		return fmt.Errorf("failed to initialize scanner: %w", err)
+	}
+
+	result, err := scanner.Scan(org, patina.ScanOptions{Refresh: cloneRefresh, IncludeForks: cloneForks, IncludeMirrors: cloneMirrors, ActivityMetric: activityMetric, Freshness: freshnessOptions})
+	if err != nil {
+		return fmt.Errorf("failed to scan organization: %w", err)
+	}
+	warnCacheWrite(result)
+	printScanSummary(cmd.ErrOrStderr(), result)
+
+	now := time.Now()
+	repos := result.Repositories
+	if freshness != "" {
+		repos = freshnessOptions.FilterByFreshness(repos, freshness, now)
+	}
+	patina.SortByAge(repos)
+
+	if missing := printCloneScript(cmd.OutOrStdout(), org, repos, freshness, cloneProtocol); missing > 0 {
+		fmt.Fprintf(statusWriter(cmd.ErrOrStderr()), "%d repositories have no cached %s URL (use --refresh)\n", missing, cloneProtocol)
+	}
+	return nil
+}
+
+// printCloneScript writes a shell script cloning each repository over the
+// protocol, and returns how many had no cached URL for it.
+func printCloneScript(w io.Writer, org string, repos []patina.Repository, freshness patina.Freshness, protocol string) int {
+	which := "all"
+	if freshness != "" {
+		which = string(freshness)
+	}
+
+	fmt.Fprintln(w, "#!/bin/sh")
+	fmt.Fprintf(w, "# Clone %s repositories in %s (%d), generated by patina\n", which, org, len(repos))
+
+	var missing int
+	for _, repo := range repos {
+		url := repo.CloneURL
+		if protocol == protocolSSH {
+			url = repo.SSHURL
+		}
+		if url == "" {
+			missing++
+			fmt.Fprintf(w, "# %s: no cached %s URL\n", repo.Name, protocol)
+			continue
+		}
+		fmt.Fprintf(w, "git clone %s\n", shellQuote(url))
+	}
+	return missing
+}
+
+// shellQuote quotes s as a single shell word.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/scottbrown/patina"
+)
+
+func TestPrintCloneScript(t *testing.T) {
+	repos := []patina.Repository{
+		{Name: "legacy", CloneURL: "https://github.com/org/legacy.git", SSHURL: "git@github.com:org/legacy.git"},
+		{Name: "old"},
+	}
+
+	tests := []struct {
+		protocol string
+		want     string
+	}{
+		{protocolHTTPS, "#!/bin/sh\n# Clone red repositories in org (2), generated by patina\n" +
+			"git clone 'https://github.com/org/legacy.git'\n# old: no cached https URL\n"},
+		{protocolSSH, "#!/bin/sh\n# Clone red repositories in org (2), generated by patina\n" +
+			"git clone 'git@github.com:org/legacy.git'\n# old: no cached ssh URL\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.protocol, func(t *testing.T) {
+			var b strings.Builder
+			if missing := printCloneScript(&b, "org", repos, patina.FreshnessRed, tt.protocol); missing != 1 {
+				t.Errorf("printCloneScript() = %d missing, want 1", missing)
+			}
+			if b.String() != tt.want {
+				t.Errorf("printCloneScript() =\n%q\nwant\n%q", b.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	if got := shellQuote("it's"); got != `'it'\''s'` {
+		t.Errorf("shellQuote() = %s, want 'it'\\''s'", got)
+	}
+}
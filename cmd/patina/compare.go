@@ -0,0 +1,141 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/scottbrown/patina"
+	"github.com/spf13/cobra"
+)
+
+var (
+	compareRefresh bool
+	compareFormat  string
+	compareForks   bool
+	compareMirrors bool
+	compareOrgs    string
+)
+
+var compareCmd = &cobra.Command{
+	Use:   "compare <organization> <organization>...",
+	Short: "Compare freshness summaries across organizations",
+	Long: `Compare scans several GitHub organizations and prints one row per
+organization with its total, green, yellow, and red counts and the
+percentage of stale (red) repositories.
+
+Organizations are sorted by stale percentage, highest first, and scanned in
+parallel. An organization whose cached counts are still current is read
+from its small summary file instead of its full cache. Use --output json or --output yaml to print the comparison for
+dashboards.
+
+Use --orgs-file to read organizations from a file, one per line (- reads
+stdin), in addition to any given as arguments.
+
+Forked repositories are excluded by default. Use --include-forks to count them.
+Mirrors are also excluded; use --include-mirrors to count them.
+
+Repository data is cached for 30 days. Use --refresh to force a fresh fetch.`,
+	Args: cobra.ArbitraryArgs,
+	RunE: runCompare,
+}
+
+func init() {
+	compareCmd.Flags().BoolVarP(&compareRefresh, "refresh", "r", false, "Force refresh from GitHub API")
+	compareCmd.Flags().BoolVar(&compareForks, "include-forks", false, "Include forked repositories")
+	compareCmd.Flags().BoolVar(&compareMirrors, "include-mirrors", false, "Include mirrors of repositories maintained elsewhere")
+	compareCmd.Flags().StringVar(&compareOrgs, "orgs-file", "", "Read organizations from this file, one per line (- for stdin)")
+	compareCmd.Flags().StringVarP(&compareFormat, "output", "o", outputText, "Output format (text, json, yaml)")
+}
+
+func runCompare(cmd *cobra.Command, args []string) error {
+	w := cmd.OutOrStdout()
+	if err := validateOutputFormat(compareFormat); err != nil {
+		return err
+	}
+
+	orgs, err := resolveOrgs(cmd, args, compareOrgs, 2)
+	if err != nil {
+		return err
+	}
+
+	client, err := newClient()
+	if err != nil {
+		return fmt.Errorf("failed to initialize scanner: %w", err)
+	}
+	cache, err := newCache()
+	if err != nil {
+		return fmt.Errorf("failed to initialize scanner: %w", err)
+	}
+	scanner := patina.NewScannerWithDeps(client, cache)
+
+	now := time.Now()
+
+	summaries := make(map[string]patina.FreshnessSummary, len(orgs))
+	pending := orgs
+	if !compareRefresh && !compareForks && !compareMirrors {
+		pending = nil
+		for _, org := range orgs {
+			summary, ok := cachedCounts(cache, org, now)
+			if !ok {
+				pending = append(pending, org)
+				continue
+			}
+			printSummaryLine(cmd.ErrOrStderr(), org, summary, "from-summary")
+			summaries[org] = summary
+		}
+	}
+
+	scans := scanner.ScanAll(pending, patina.ScanOptions{Refresh: compareRefresh, IncludeForks: compareForks, IncludeMirrors: compareMirrors, ActivityMetric: activityMetric, Freshness: freshnessOptions})
+	for _, scan := range scans {
+		if scan.Err != nil {
+			return fmt.Errorf("failed to scan organization %s: %w", scan.Organization, scan.Err)
+		}
+		warnCacheWrite(scan.Result)
+		printScanSummary(cmd.ErrOrStderr(), scan.Result)
+		summaries[scan.Organization] = freshnessOptions.Summary(scan.Result.Repositories, now)
+	}
+
+	return writeComparison(w, compareFormat, patina.CompareSummaries(summaries))
+}
+
+// cachedCounts returns an organization's counts from its cached summary, if
+// one exists and is current for the freshness options and activity metric.
+func cachedCounts(cache *patina.Cache, org string, now time.Time) (patina.FreshnessSummary, bool) {
+	cached, err := cache.LoadSummary(org)
+	if err != nil {
+		return patina.FreshnessSummary{}, false
+	}
+	return cached.Counts(freshnessOptions, activityMetric, now)
+}
+
+// writeComparison writes the comparison rows as a table, or as JSON or YAML.
+func writeComparison(w io.Writer, format string, rows []patina.OrgComparison) error {
+	if format != outputText {
+		return writeStructured(w, format, rows)
+	}
+	printComparison(w, rows)
+	return nil
+}
+
+func printComparison(w io.Writer, rows []patina.OrgComparison) {
+	maxNameLen := len("Organization")
+	for _, row := range rows {
+		if len(row.Organization) > maxNameLen {
+			maxNameLen = len(row.Organization)
+		}
+	}
+
+	fmt.Fprintf(w, "%-*s  %6s  %6s  %6s  %6s  %7s\n", maxNameLen, "Organization", "Total", "Green", "Yellow", "Red", "Stale")
+	for _, row := range rows {
+		fmt.Fprintf(w, "%-*s  %6d  %6d  %6d  %6d  %6.1f%%\n",
+			maxNameLen,
+			row.Organization,
+			row.Total,
+			row.Green,
+			row.Yellow,
+			row.Red,
+			row.StalePercent,
+		)
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scottbrown/patina"
+)
+
+func TestPrintComparison(t *testing.T) {
+	rows := []patina.OrgComparison{
+		{Organization: "legacy-org", Total: 40, Green: 8, Yellow: 7, Red: 25, StalePercent: 62.5},
+		{Organization: "my-org", Total: 42, Green: 20, Yellow: 10, Red: 12, StalePercent: 28.571},
+	}
+
+	var b strings.Builder
+	printComparison(&b, rows)
+
+	want := "Organization   Total   Green  Yellow     Red    Stale\n" +
+		"legacy-org        40       8       7      25    62.5%\n" +
+		"my-org            42      20      10      12    28.6%\n"
+	if b.String() != want {
+		t.Errorf("printComparison() =\n%s\nwant\n%s", b.String(), want)
+	}
+}
+
+func TestWriteComparison(t *testing.T) {
+	rows := []patina.OrgComparison{{Organization: "my-org", Total: 4, Green: 2, Yellow: 1, Red: 1, StalePercent: 25}}
+
+	var b strings.Builder
+	if err := writeComparison(&b, outputJSON, rows); err != nil {
+		t.Fatalf("writeComparison() error = %v", err)
+	}
+	want := `[
+  {
+    "organization": "my-org",
+    "total": 4,
+    "green": 2,
+    "yellow": 1,
+    "red": 1,
+    "stale_percent": 25
+  }
+]
+`
+	if b.String() != want {
+		t.Errorf("writeComparison(json) =\n%s\nwant\n%s", b.String(), want)
+	}
+
+	b.Reset()
+	if err := writeComparison(&b, outputYAML, rows); err != nil {
+		t.Fatalf("writeComparison() error = %v", err)
+	}
+	if !strings.HasPrefix(b.String(), "- organization: my-org\n") {
+		t.Errorf("writeComparison(yaml) = %q", b.String())
+	}
+
+	b.Reset()
+	if err := writeComparison(&b, outputText, rows); err != nil {
+		t.Fatalf("writeComparison() error = %v", err)
+	}
+	if !strings.HasPrefix(b.String(), "Organization") {
+		t.Errorf("writeComparison(text) = %q", b.String())
+	}
+}
+
+func TestCachedCounts(t *testing.T) {
+	prevOptions, prevMetric := freshnessOptions, activityMetric
+	t.Cleanup(func() { freshnessOptions, activityMetric = prevOptions, prevMetric })
+	freshnessOptions, activityMetric = patina.FreshnessOptions{}, patina.ActivityPushed
+
+	now := time.Now()
+	cache := patina.NewCacheWithDir(t.TempDir())
+	if _, ok := cachedCounts(cache, "my-org", now); ok {
+		t.Error("cachedCounts() = ok with no cache")
+	}
+
+	err := cache.Save(patina.OrganizationCache{
+		Organization: "my-org",
+		FetchedAt:    now,
+		Repositories: []patina.Repository{
+			{Name: "api", LastUpdated: now.AddDate(0, 0, -1)},
+			{Name: "legacy", LastUpdated: now.AddDate(-2, 0, 0)},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	got, ok := cachedCounts(cache, "my-org", now)
+	if want := (patina.FreshnessSummary{Green: 1, Red: 1, Total: 2}); !ok || got != want {
+		t.Errorf("cachedCounts() = %+v, %t; want %+v, true", got, ok, want)
+	}
+
+	// Counts calculated differently are not reused
+	activityMetric = patina.ActivityUpdated
+	if _, ok := cachedCounts(cache, "my-org", now); ok {
+		t.Error("cachedCounts() = ok with --activity-metric updated")
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/scottbrown/patina"
+	"github.com/spf13/cobra"
+)
+
+var (
+	leaderboardTop     int
+	leaderboardForks   bool
+	leaderboardMirrors bool
+)
+
+var leaderboardCmd = &cobra.Command{
+	Use:   "leaderboard",
+	Short: "List the most stale repositories across all cached organizations",
+	Long: `Leaderboard reads every organization in the cache and lists the most
+stale repositories across all of them, oldest first (10 by default). Use
+--top to change how many are shown, or --top 0 to show them all.
+
+Only cached data is used; nothing is fetched from GitHub. Organizations
+whose caches have expired are skipped with a note. Run scan or warm to
+cache organizations first.
+
+Forked repositories are excluded by default. Use --include-forks to include them.
+Mirrors are also excluded; use --include-mirrors to include them.
+
+Example:
+  patina warm org-a org-b org-c && patina leaderboard --top 25`,
+	Args: cobra.NoArgs,
+	RunE: runLeaderboard,
+}
+
+func init() {
+	leaderboardCmd.Flags().IntVarP(&leaderboardTop, "top", "n", 10, "Number of most stale repositories to show (0 for all)")
+	leaderboardCmd.Flags().BoolVar(&leaderboardForks, "include-forks", false, "Include forked repositories")
+	leaderboardCmd.Flags().BoolVar(&leaderboardMirrors, "include-mirrors", false, "Include mirrors of repositories maintained elsewhere")
+}
+
+func runLeaderboard(cmd *cobra.Command, args []string) error {
+	w := cmd.OutOrStdout()
+
+	if leaderboardTop < 0 {
+		return fmt.Errorf("invalid top value: %d (must be 0 or greater)", leaderboardTop)
+	}
+
+	cache, err := newCache()
+	if err != nil {
+		return fmt.Errorf("failed to initialize cache: %w", err)
+	}
+
+	caches, expired, err := cache.LoadAll()
+	if err != nil {
+		return err
+	}
+
+	status := statusWriter(w)
+	if len(expired) > 0 {
+		fmt.Fprintf(status, "Skipping expired caches: %s (run warm to refresh them)\n\n", strings.Join(expired, ", "))
+	}
+
+	if len(caches) == 0 {
+		fmt.Fprintln(w, "No cached organizations found. Run scan or warm first.")
+		return nil
+	}
+
+	// Merge every organization's repositories, tagging each with its organization
+	var repos []patina.Repository
+	for _, c := range caches {
+		orgRepos := patina.ExcludeArchived(patina.UseActivityMetric(c.Repositories, activityMetric))
+		if !leaderboardForks {
+			orgRepos = patina.ExcludeForks(orgRepos)
+		}
+		if !leaderboardMirrors {
+			orgRepos = patina.ExcludeMirrors(orgRepos)
+		}
+		for _, repo := range patina.ExcludeNeverPushed(orgRepos) {
+			if repo.FullName == "" {
+				repo.FullName = c.Organization + "/" + repo.Name
+			}
+			repos = append(repos, repo)
+		}
+	}
+
+	printLeaderboard(w, repos, len(caches), time.Now(), leaderboardTop)
+	return nil
+}
+
+// printLeaderboard writes the n most stale repositories, named with their
+// organization, from the merged repositories of orgCount organizations.
+func printLeaderboard(w io.Writer, repos []patina.Repository, orgCount int, now time.Time, n int) {
+	topStale := patina.GetTopStale(repos, n)
+
+	if len(topStale) == 0 {
+		fmt.Fprintln(w, "No repositories found.")
+		return
+	}
+
+	title := fmt.Sprintf("Top %d Most Stale Repositories Across %d Organizations", len(topStale), orgCount)
+	fmt.Fprintln(w, title)
+	fmt.Fprintln(w, strings.Repeat("=", len(title)))
+	fmt.Fprintln(w)
+
+	t := &table{borders: borders}
+	for i, view := range freshnessOptions.RepositoryViews(topStale, now) {
+		label := symbol(view.Freshness) + " " + colour(view.Freshness) + truncateName(view.FullName, maxNameWidth) + patina.ColourReset()
+		t.addRow(fmt.Sprintf("%2d. %s", i+1, label), view.Age)
+	}
+	t.render(w)
+}
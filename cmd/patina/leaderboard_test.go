@@ -0,0 +1,30 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scottbrown/patina"
+)
+
+func TestPrintLeaderboard(t *testing.T) {
+	useASCII(t)
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	repos := []patina.Repository{
+		{Name: "api", FullName: "org-a/api", LastUpdated: now.AddDate(-1, 0, 0)},
+		{Name: "web", FullName: "org-b/web", LastUpdated: now.AddDate(0, 0, -5)},
+		{Name: "legacy", FullName: "org-b/legacy", LastUpdated: now.AddDate(-2, 0, 0)},
+	}
+
+	var b strings.Builder
+	printLeaderboard(&b, repos, 2, now, 2)
+
+	want := "Top 2 Most Stale Repositories Across 2 Organizations\n" +
+		"====================================================\n\n" +
+		" 1. [R] " + red + "org-b/legacy" + reset + "  2 years ago\n" +
+		" 2. [R] " + red + "org-a/api" + reset + "     1 year ago\n"
+	if b.String() != want {
+		t.Errorf("printLeaderboard() =\n%q\nwant\n%q", b.String(), want)
+	}
+}
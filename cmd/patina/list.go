@@ -2,6 +2,9 @@ package main
 
 import (
 	"fmt"
+	"io"
+	"os"
+	"slices"
 	"time"
 
 	"github.com/scottbrown/patina"
@@ -9,10 +12,37 @@ import (
 )
 
 var (
-	listFreshness string
-	listRefresh   bool
+	listFreshness  string
+	listMinLevel   string
+	listSince      string
+	listNewerThan  string
+	listFrom       string
+	listTo         string
+	listMinAge     int
+	listNoLicense  bool
+	listMinStars   int
+	listOnly       string
+	listFormat     string
+	listSort       string
+	listWeights    string
+	listRefresh    bool
+	listLastCommit bool
+	listShowDates  bool
+	listCountdown  bool
+	listForks      bool
+	listMirrors    bool
+	listArchived   bool
+	listURLsOnly   bool
+	listURLType    string
+	listTopicAll   string
+	listTopicAny   string
+	listTopicNone  string
+	listOwners     string
+	listGroupBy    string
 )
 
+const scoreWeightsEnv = "PATINA_SCORE_WEIGHTS"
+
 var listCmd = &cobra.Command{
 	Use:   "list <organization>",
 	Short: "List all repositories with their freshness status",
@@ -24,6 +54,88 @@ Use the --freshness flag to filter by status:
   --freshness yellow  Show only aging repos (updated 2-6 months ago)
   --freshness red     Show only stale repos (not updated in >6 months)
 
+Use the --min-freshness flag to show repos at least as stale as a level,
+ordering green < yellow < red:
+  --min-freshness yellow  Show aging and stale repos
+
+Use the --since flag to show repos not updated since a cutoff, independent
+of the freshness thresholds. Accepts a date or a relative duration:
+  --since 2023-01-01  Show repos not updated since 1 January 2023
+  --since 180d        Show repos not updated in the last 180 days
+  --since 6mo         Show repos not updated in the last 6 months
+
+Use the --newer-than flag for the opposite: repos updated more recently than
+a duration ago. Combine it with --sort newest for a most active view:
+  --newer-than 30d --sort newest  Show repos updated in the last 30 days
+
+Use the --from and --to flags to show repos last updated within an inclusive
+date range. Either bound can be omitted:
+  --from 2023-04-01 --to 2023-06-30  Show repos last updated in Q2 2023
+  --to 2022-12-31                    Show repos last updated before 2023
+
+Use the --min-age flag to show repos not updated in at least N days:
+  --min-age 90        Show repos not updated in the last 90 days
+
+Use the --no-license flag to show only repos without a license.
+
+Use the --only-file flag to list only the repos named in a file, one per
+line. Names in the file that are not found in the organization are reported.
+
+Use the --min-stars flag to hide repos with fewer stars, such as one-off
+experiments:
+  --min-stars 5       Show only repos with at least 5 stars
+
+Use the --topic-all, --topic-any, and --topic-none flags to select repos by
+their GitHub topics. Each takes a comma-separated list, and they combine:
+  --topic-all go,service      Show repos with both topics
+  --topic-any go,typescript   Show repos with either topic
+  --topic-none deprecated     Hide repos with the topic
+
+Use --owners with a CSV file of repo,team rows to show the team that owns
+each repo, for organizations that track ownership outside GitHub. Repos
+missing from the file are shown as unowned:
+  --owners owners.csv
+
+Use --sort newest to order repos by last update, most recent first.
+
+Use --sort score to order repos by a staleness score combining age, open
+issues, and stars, highest priority first. Tune the weights with
+--score-weights or the PATINA_SCORE_WEIGHTS environment variable:
+  --score-weights age=1,issues=1,stars=2
+
+Use --group-by to print the repos in sections, each under a header with its
+count: freshness gives green, yellow, and red sections, and an archived one
+with --include-archived, and language gives
+one section per primary language. Repos keep the --sort order within each:
+  --group-by language
+
+Use --show-dates to show the date each repo was last updated beside its
+relative age, for audit records.
+
+Use --countdown to show how many days each yellow repo has before it turns
+red, and each green repo before it turns yellow, to plan maintenance ahead.
+
+Use --with-last-commit to look up who made the last commit to each repo and
+when. This makes one extra API call per repo; results are cached. The last
+commit date can differ from the last push shown as the repo's age.
+
+Forked repositories are excluded by default. Use --include-forks to list them.
+Mirrors of repositories maintained elsewhere are also excluded; use
+--include-mirrors to list them. Archived repositories are excluded too; use
+--include-archived to list them as their own state, with a padlock.
+
+Use --output json or --output yaml to print the repositories as structured
+data; progress messages then go to stderr.
+
+Use --output table to print a header row and one tab-separated row per repo,
+with Name, Freshness, Age, and Last Updated columns and no emoji or colour,
+for post-processing with awk, cut, or column:
+  --output table | column -t -s $'\t'
+
+Use --urls-only to print just one URL per repo, for piping into other tools.
+--url-type chooses the URL: html (default), clone (HTTPS), or ssh:
+  --freshness red --urls-only --url-type ssh | xargs -n1 git clone
+
 Repository data is cached for 30 days. Use --refresh to force a fresh fetch.`,
 	Args: cobra.ExactArgs(1),
 	RunE: runList,
@@ -31,10 +143,45 @@ Repository data is cached for 30 days. Use --refresh to force a fresh fetch.`,
 
 func init() {
 	listCmd.Flags().StringVarP(&listFreshness, "freshness", "f", "", "Filter by freshness (green, yellow, red)")
+	listCmd.Flags().StringVar(&listMinLevel, "min-freshness", "", "Show repos at least this stale (green, yellow, red)")
+	listCmd.Flags().StringVarP(&listSince, "since", "s", "", "Show repos not updated since a date (YYYY-MM-DD) or duration (180d, 6mo)")
+	listCmd.Flags().StringVar(&listNewerThan, "newer-than", "", "Show repos updated more recently than a duration ago (30d, 4w, 6mo)")
+	listCmd.Flags().StringVar(&listFrom, "from", "", "Show repos last updated on or after this date (YYYY-MM-DD)")
+	listCmd.Flags().StringVar(&listTo, "to", "", "Show repos last updated on or before this date (YYYY-MM-DD)")
+	listCmd.Flags().IntVar(&listMinAge, "min-age", 0, "Show repos not updated in at least this many days")
+	listCmd.Flags().BoolVar(&listNoLicense, "no-license", false, "Show only repos without a license")
+	listCmd.Flags().StringVar(&listOnly, "only-file", "", "Only include repos named in this file (one per line)")
+	listCmd.Flags().StringVar(&listTopicAll, "topic-all", "", "Show only repos with every one of these topics (comma-separated)")
+	listCmd.Flags().StringVar(&listTopicAny, "topic-any", "", "Show only repos with at least one of these topics (comma-separated)")
+	listCmd.Flags().StringVar(&listTopicNone, "topic-none", "", "Hide repos with any of these topics (comma-separated)")
+	listCmd.Flags().StringVar(&listOwners, "owners", "", "CSV file of repo,team rows; shows each repo's owning team")
+	listCmd.Flags().IntVar(&listMinStars, "min-stars", 0, "Show only repos with at least this many stars")
+	listCmd.Flags().StringVarP(&listFormat, "output", "o", outputText, "Output format (text, table, json, yaml)")
+	listCmd.Flags().StringVar(&listSort, "sort", "age", "Sort order (age, newest, score, size)")
+	listCmd.Flags().StringVar(&listGroupBy, "group-by", "", "Print repos in sections by freshness or language")
+	listCmd.Flags().StringVar(&listWeights, "score-weights", "", "Score weights as name=value pairs (age, issues, stars)")
 	listCmd.Flags().BoolVarP(&listRefresh, "refresh", "r", false, "Force refresh from GitHub API")
+	listCmd.Flags().BoolVar(&listForks, "include-forks", false, "Include forked repositories")
+	listCmd.Flags().BoolVar(&listMirrors, "include-mirrors", false, "Include mirrors of repositories maintained elsewhere")
+	listCmd.Flags().BoolVar(&listArchived, "include-archived", false, "Include archived repositories as their own state")
+	listCmd.Flags().BoolVar(&listShowDates, "show-dates", false, "Show the date each repo was last updated beside its age")
+	listCmd.Flags().BoolVar(&listCountdown, "countdown", false, "Show the days until each yellow repo turns red and each green repo turns yellow")
+	listCmd.Flags().BoolVar(&listLastCommit, "with-last-commit", false, "Look up the last commit author and date for each repo")
+	listCmd.Flags().BoolVar(&listURLsOnly, "urls-only", false, "Print only one URL per repo, with no other output")
+	listCmd.Flags().StringVar(&listURLType, "url-type", urlTypeHTML, "URL printed by --urls-only (html, clone, ssh)")
+	listCmd.MarkFlagsMutuallyExclusive("urls-only", "output")
+	listCmd.MarkFlagsMutuallyExclusive("freshness", "min-freshness")
 }
 
+// URL types accepted by --url-type.
+const (
+	urlTypeHTML  = "html"
+	urlTypeClone = "clone"
+	urlTypeSSH   = "ssh"
+)
+
 func runList(cmd *cobra.Command, args []string) error {
+	w := cmd.OutOrStdout()
 	org := args[0]
 
 	// Validate freshness filter if provided
@@ -46,67 +193,422 @@ func runList(cmd *cobra.Command, args []string) error {
 		}
 		filterFreshness = f
 	}
+	var minFreshness patina.Freshness
+	if listMinLevel != "" {
+		f, ok := patina.ParseFreshness(listMinLevel)
+		if !ok {
+			return fmt.Errorf("invalid min-freshness value: %q (must be green, yellow, or red)", listMinLevel)
+		}
+		minFreshness = f
+	}
+
+	// Validate since cutoff if provided
+	var cutoff time.Time
+	if listSince != "" {
+		c, err := patina.ParseSince(listSince, time.Now())
+		if err != nil {
+			return err
+		}
+		cutoff = c
+	}
 
-	scanner, err := patina.NewScanner()
+	// Validate newer-than duration if provided
+	var newerThan time.Duration
+	var activeSince time.Time
+	if listNewerThan != "" {
+		now := time.Now()
+		c, err := patina.ParseSince(listNewerThan, now)
+		if err != nil {
+			return err
+		}
+		newerThan = now.Sub(c)
+		activeSince = c
+	}
+
+	// Validate date range if provided
+	from, err := parseDateFlag("from", listFrom)
+	if err != nil {
+		return err
+	}
+	to, err := parseDateFlag("to", listTo)
+	if err != nil {
+		return err
+	}
+	if !from.IsZero() && !to.IsZero() && to.Before(from) {
+		return fmt.Errorf("invalid date range: --to %s is before --from %s", listTo, listFrom)
+	}
+
+	if cmd.Flags().Changed("min-age") && listMinAge <= 0 {
+		return fmt.Errorf("invalid min-age value: %d (must be a positive number of days)", listMinAge)
+	}
+
+	if listMinStars < 0 {
+		return fmt.Errorf("invalid min-stars value: %d (must be 0 or greater)", listMinStars)
+	}
+
+	if listSort != "age" && listSort != "newest" && listSort != "score" && listSort != "size" {
+		return fmt.Errorf("invalid sort value: %q (must be age, newest, score, or size)", listSort)
+	}
+
+	weightsSpec := listWeights
+	if weightsSpec == "" {
+		weightsSpec = os.Getenv(scoreWeightsEnv)
+	}
+	weights, err := patina.ParseScoreWeights(weightsSpec)
+	if err != nil {
+		return err
+	}
+
+	if listFormat != outputTable && validateOutputFormat(listFormat) != nil {
+		return fmt.Errorf("invalid output value: %q (must be text, table, json, or yaml)", listFormat)
+	}
+
+	if listGroupBy != "" && listGroupBy != groupByFreshness && listGroupBy != groupByLanguage {
+		return fmt.Errorf("invalid group-by value: %q (must be freshness or language)", listGroupBy)
+	}
+	if listGroupBy != "" && (listFormat != outputText || listURLsOnly) {
+		return fmt.Errorf("--group-by only applies to text output")
+	}
+
+	if listURLType != urlTypeHTML && listURLType != urlTypeClone && listURLType != urlTypeSSH {
+		return fmt.Errorf("invalid url-type value: %q (must be html, clone, or ssh)", listURLType)
+	}
+
+	only, err := readOnlyFile(listOnly)
+	if err != nil {
+		return err
+	}
+
+	ownership, err := readOwnersFile(listOwners)
+	if err != nil {
+		return err
+	}
+
+	scanner, err := newScanner()
 	if err != nil {
 		return fmt.Errorf("failed to initialize scanner: %w", err)
 	}
 
-	result, err := scanner.Scan(org, patina.ScanOptions{Refresh: listRefresh})
+	result, err := scanner.Scan(org, patina.ScanOptions{Refresh: listRefresh, WithLastCommit: listLastCommit, IncludeForks: listForks, IncludeMirrors: listMirrors, IncludeArchived: listArchived, ActivityMetric: activityMetric, Freshness: freshnessOptions})
 	if err != nil {
 		return fmt.Errorf("failed to scan organization: %w", err)
 	}
+	warnCacheWrite(result)
+	printScanSummary(cmd.ErrOrStderr(), result)
 
 	now := time.Now()
 
-	repos := result.Repositories
+	// Keep stdout clean for structured output and URLs
+	status := statusWriter(w)
+	if listFormat != outputText || listURLsOnly {
+		status = statusWriter(cmd.ErrOrStderr())
+	}
+	if result.FromCache {
+		fmt.Fprintf(status, "Using cached data from %s\n\n", result.FetchedAt.Format("2006-01-02 15:04:05"))
+	}
+
+	// Narrow to the repositories named in the only-file if specified
+	repos := filterOnly(status, org, result.Repositories, only)
 
 	// Apply freshness filter if specified
 	if filterFreshness != "" {
-		repos = patina.FilterByFreshness(repos, filterFreshness, now)
+		repos = freshnessOptions.FilterByFreshness(repos, filterFreshness, now)
+	}
+	if minFreshness != "" {
+		repos = freshnessOptions.FilterAtLeast(repos, minFreshness, now)
 	}
 
-	// Sort by age (oldest first)
-	patina.SortByAge(repos)
+	// Apply since cutoff if specified
+	if !cutoff.IsZero() {
+		repos = patina.FilterStaleSince(repos, cutoff)
+	}
 
-	// Print header
-	if result.FromCache {
-		fmt.Printf("Using cached data from %s\n\n", result.FetchedAt.Format("2006-01-02 15:04:05"))
+	// Apply newer-than bound if specified
+	if listNewerThan != "" {
+		repos = patina.FilterNewerThan(repos, newerThan, now)
+	}
+
+	// Apply date range if specified
+	if !from.IsZero() || !to.IsZero() {
+		repos = patina.FilterByDateRange(repos, from, to)
+	}
+
+	// Apply minimum age filter if specified
+	if listMinAge > 0 {
+		repos = patina.FilterByMinAge(repos, listMinAge, now)
 	}
 
+	// Apply minimum stars filter if specified, counting what it drops
+	var excludedByStars int
+	if listMinStars > 0 {
+		before := len(repos)
+		repos = patina.FilterByMinStars(repos, listMinStars)
+		excludedByStars = before - len(repos)
+	}
+
+	// Apply license filter if specified
+	if listNoLicense {
+		repos = patina.FilterNoLicense(repos)
+	}
+
+	// Apply topic query if specified
+	topics := patina.TopicQuery{
+		All:  patina.ParseTopics(listTopicAll),
+		Any:  patina.ParseTopics(listTopicAny),
+		None: patina.ParseTopics(listTopicNone),
+	}
+	if len(topics.All) > 0 || len(topics.Any) > 0 || len(topics.None) > 0 {
+		if !hasTopics(result.Repositories) {
+			fmt.Fprintln(status, "No cached repository has topics (use --refresh if the cache predates topic support)")
+		}
+		repos = patina.FilterByTopics(repos, topics)
+	}
+
+	// Sort by age (oldest first), by last update (newest first), by score
+	// (highest first), or by size (largest first, oldest first among equals)
+	switch listSort {
+	case "score":
+		patina.SortByScore(repos, weights, now)
+	case "newest":
+		patina.SortByAgeDesc(repos)
+	case "size":
+		patina.SortByAge(repos)
+		patina.SortBySize(repos)
+	default:
+		patina.SortByAge(repos)
+	}
+
+	if listURLsOnly {
+		if missing := printURLs(w, repos, listURLType); missing > 0 {
+			fmt.Fprintf(status, "%d repositories have no cached %s URL (use --refresh)\n", missing, listURLType)
+		}
+		return nil
+	}
+
+	if listFormat == outputTable {
+		printTable(w, repos, now, ownership)
+		return nil
+	}
+
+	if listFormat != outputText {
+		return writeStructured(w, listFormat, listOutput{
+			Organization:    org,
+			FetchedAt:       result.FetchedAt,
+			FromCache:       result.FromCache,
+			ExcludedForks:   result.ExcludedForks,
+			ExcludedMirrors: result.ExcludedMirrors,
+			NeverPushed:     result.NeverPushed,
+			Repositories:    annotatedViews(repos, now, ownership),
+		})
+	}
+
+	// Print header
 	if filterFreshness != "" {
-		fmt.Printf("Repositories in %s (%s): %d\n\n", org, filterFreshness, len(repos))
+		fmt.Fprintf(w, "Repositories in %s (%s): %d\n\n", org, filterFreshness, len(repos))
+	} else if !cutoff.IsZero() {
+		fmt.Fprintf(w, "Repositories in %s not updated since %s: %d\n\n", org, cutoff.Format("2006-01-02"), len(repos))
+	} else if listNewerThan != "" {
+		fmt.Fprintf(w, "Repositories in %s updated since %s: %d\n\n", org, activeSince.Format("2006-01-02"), len(repos))
+	} else if !from.IsZero() || !to.IsZero() {
+		fmt.Fprintf(w, "Repositories in %s last updated %s: %d\n\n", org, describeDateRange(listFrom, listTo), len(repos))
 	} else {
-		fmt.Printf("All repositories in %s: %d\n\n", org, len(repos))
+		fmt.Fprintf(w, "All repositories in %s: %d\n\n", org, len(repos))
 	}
 
 	if len(repos) == 0 {
-		fmt.Println("No repositories found matching the criteria.")
+		fmt.Fprintln(w, "No repositories found matching the criteria.")
+		printExcludedForks(status, result.ExcludedForks)
+		printExcludedMirrors(status, result.ExcludedMirrors)
+		printNeverPushed(status, result.NeverPushed)
+		printExcludedByStars(status, excludedByStars, listMinStars)
 		return nil
 	}
 
-	// Calculate max name length for alignment
-	maxNameLen := 0
+	detail := func(view patina.RepositoryView) string {
+		age := view.Age
+		if listShowDates {
+			age = fmt.Sprintf("%s (%s)", view.LastUpdated.Format("2006-01-02"), age)
+		}
+		if countdown := freshnessOptions.Countdown(view.Repository, now); listCountdown && countdown != "" {
+			age = fmt.Sprintf("%s (%s)", age, countdown)
+		}
+		if listSort == "score" {
+			age = fmt.Sprintf("%s (score %.1f)", age, weights.Score(view.Repository, now))
+		}
+		if listSort == "size" {
+			age = fmt.Sprintf("%s (%s)", age, patina.FormatSize(int64(view.SizeKB)))
+		}
+		if listLastCommit && !view.LastCommitDate.IsZero() {
+			age = fmt.Sprintf("%s, last commit %s by %s", age, view.LastCommitDate.Format("2006-01-02"), view.LastCommitAuthor)
+		}
+		if ownership != nil {
+			age = fmt.Sprintf("%s, owner %s", age, ownership.Team(view.Repository))
+		}
+		return age
+	}
+	if listGroupBy != "" {
+		printGroupedList(w, groupRepositories(repos, now, listGroupBy), now, detail)
+	} else {
+		printRepositoryList(w, repos, now, detail)
+	}
+
+	printExcludedForks(status, result.ExcludedForks)
+	printExcludedMirrors(status, result.ExcludedMirrors)
+	printNeverPushed(status, result.NeverPushed)
+	printExcludedByStars(status, excludedByStars, listMinStars)
+
+	return nil
+}
+
+// Groupings accepted by --group-by.
+const (
+	groupByFreshness = "freshness"
+	groupByLanguage  = "language"
+)
+
+// unknownLanguage names the group of repositories with no detected language.
+const unknownLanguage = "Unknown"
+
+// repoGroup is one section of a grouped repository list.
+type repoGroup struct {
+	name  string
+	repos []patina.Repository
+}
+
+// groupRepositories splits repositories into sections, keeping their order
+// within each. Freshness sections run green, yellow, red, archived; language sections
+// are alphabetical, with repositories of no detected language last. Empty
+// sections are left out.
+func groupRepositories(repos []patina.Repository, now time.Time, by string) []repoGroup {
+	var groups []repoGroup
+	if by == groupByFreshness {
+		for _, level := range []struct {
+			name      string
+			freshness patina.Freshness
+		}{
+			{"Green", patina.FreshnessGreen},
+			{"Yellow", patina.FreshnessYellow},
+			{"Red", patina.FreshnessRed},
+			{"Archived", patina.FreshnessArchived},
+		} {
+			if filtered := freshnessOptions.FilterByFreshness(repos, level.freshness, now); len(filtered) > 0 {
+				groups = append(groups, repoGroup{name: level.name, repos: filtered})
+			}
+		}
+		return groups
+	}
+
+	byLanguage := make(map[string][]patina.Repository)
+	var languages []string
+	for _, repo := range repos {
+		if _, ok := byLanguage[repo.Language]; !ok && repo.Language != "" {
+			languages = append(languages, repo.Language)
+		}
+		byLanguage[repo.Language] = append(byLanguage[repo.Language], repo)
+	}
+	slices.Sort(languages)
+	for _, language := range languages {
+		groups = append(groups, repoGroup{name: language, repos: byLanguage[language]})
+	}
+	if unknown := byLanguage[""]; len(unknown) > 0 {
+		groups = append(groups, repoGroup{name: unknownLanguage, repos: unknown})
+	}
+	return groups
+}
+
+// printGroupedList writes each group under a header with its count, separated
+// by blank lines.
+func printGroupedList(w io.Writer, groups []repoGroup, now time.Time, detail func(patina.RepositoryView) string) {
+	for i, group := range groups {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "%s (%d)\n", group.name, len(group.repos))
+		printRepositoryList(w, group.repos, now, detail)
+	}
+}
+
+// printRepositoryList writes one aligned line per repository with its freshness
+// indicator, name, and the detail text returned for its view.
+func printRepositoryList(w io.Writer, repos []patina.Repository, now time.Time, detail func(patina.RepositoryView) string) {
+	t := &table{borders: borders}
+	for _, view := range freshnessOptions.RepositoryViews(repos, now) {
+		t.addRow(freshnessLabel(view), detail(view))
+	}
+	t.render(w)
+}
+
+// printTable writes a header row and one tab-separated row per repository,
+// with no emoji or colour. An Owner column is added if ownership is loaded.
+func printTable(w io.Writer, repos []patina.Repository, now time.Time, ownership patina.Ownership) {
+	header := "Name\tFreshness\tAge\tLast Updated"
+	if ownership != nil {
+		header += "\tOwner"
+	}
+	fmt.Fprintln(w, header)
+
+	for _, view := range annotatedViews(repos, now, ownership) {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s", view.Name, view.Freshness, view.Age, view.LastUpdated.Format("2006-01-02"))
+		if ownership != nil {
+			fmt.Fprintf(w, "\t%s", view.Team)
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+// hasTopics reports whether any repository has topics. Caches written before
+// topics were stored have none.
+func hasTopics(repos []patina.Repository) bool {
 	for _, repo := range repos {
-		if len(repo.Name) > maxNameLen {
-			maxNameLen = len(repo.Name)
+		if len(repo.Topics) > 0 {
+			return true
 		}
 	}
+	return false
+}
 
-	// Print each repository
+// printURLs writes the URL of the given type for each repository, one per
+// line, and returns how many repositories had no such URL. Caches written
+// before clone and SSH URLs were stored have only the HTML URL.
+func printURLs(w io.Writer, repos []patina.Repository, urlType string) int {
+	var missing int
 	for _, repo := range repos {
-		freshness := patina.CalculateFreshness(repo.LastUpdated, now)
-		age := patina.Age(repo.LastUpdated, now)
+		url := repo.HTMLURL
+		switch urlType {
+		case urlTypeClone:
+			url = repo.CloneURL
+		case urlTypeSSH:
+			url = repo.SSHURL
+		}
+		if url == "" {
+			missing++
+			continue
+		}
+		fmt.Fprintln(w, url)
+	}
+	return missing
+}
 
-		fmt.Printf("%s %s%-*s%s  %s\n",
-			freshness.Emoji(),
-			freshness.Colour(),
-			maxNameLen,
-			repo.Name,
-			patina.ColourReset(),
-			age,
-		)
+// parseDateFlag parses a YYYY-MM-DD flag value, returning the zero time if it is empty.
+func parseDateFlag(name, value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
 	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s date: %q (must be YYYY-MM-DD)", name, value)
+	}
+	return t, nil
+}
 
-	return nil
+// describeDateRange describes an inclusive date range with optional bounds.
+func describeDateRange(from, to string) string {
+	switch {
+	case from == "":
+		return "on or before " + to
+	case to == "":
+		return "on or after " + from
+	default:
+		return fmt.Sprintf("from %s to %s", from, to)
+	}
 }
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scottbrown/patina"
+)
+
+func TestPrintRepositoryList(t *testing.T) {
+	useASCII(t)
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+
+	repos := []patina.Repository{
+		{Name: "legacy-api", LastUpdated: now.AddDate(-1, 0, 0), Stars: 7},
+		{Name: "web", LastUpdated: now.AddDate(0, 0, -10), Stars: 2},
+	}
+
+	tests := []struct {
+		name   string
+		detail func(patina.RepositoryView) string
+		want   string
+	}{
+		{
+			name:   "age",
+			detail: func(v patina.RepositoryView) string { return v.Age },
+			want: "[R] " + red + "legacy-api" + reset + "  1 year ago\n" +
+				"[G] " + green + "web" + reset + "         10 days ago\n",
+		},
+		{
+			name:   "custom detail",
+			detail: func(v patina.RepositoryView) string { return fmt.Sprintf("%d stars", v.Stars) },
+			want: "[R] " + red + "legacy-api" + reset + "  7 stars\n" +
+				"[G] " + green + "web" + reset + "         2 stars\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b strings.Builder
+			printRepositoryList(&b, repos, now, tt.detail)
+
+			if b.String() != tt.want {
+				t.Errorf("printRepositoryList() =\n%q\nwant\n%q", b.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestDescribeDateRange(t *testing.T) {
+	tests := []struct {
+		from, to string
+		want     string
+	}{
+		{"2023-04-01", "2023-06-30", "from 2023-04-01 to 2023-06-30"},
+		{"2023-04-01", "", "on or after 2023-04-01"},
+		{"", "2023-06-30", "on or before 2023-06-30"},
+	}
+
+	for _, tt := range tests {
+		if got := describeDateRange(tt.from, tt.to); got != tt.want {
+			t.Errorf("describeDateRange(%q, %q) = %q, want %q", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestPrintURLs(t *testing.T) {
+	repos := []patina.Repository{
+		{
+			Name:     "api",
+			HTMLURL:  "https://github.com/org/api",
+			CloneURL: "https://github.com/org/api.git",
+			SSHURL:   "git@github.com:org/api.git",
+		},
+		{Name: "legacy", HTMLURL: "https://github.com/org/legacy"}, // Cached before clone URLs were stored
+	}
+
+	tests := []struct {
+		urlType     string
+		want        string
+		wantMissing int
+	}{
+		{urlTypeHTML, "https://github.com/org/api\nhttps://github.com/org/legacy\n", 0},
+		{urlTypeClone, "https://github.com/org/api.git\n", 1},
+		{urlTypeSSH, "git@github.com:org/api.git\n", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.urlType, func(t *testing.T) {
+			var b strings.Builder
+			missing := printURLs(&b, repos, tt.urlType)
+			if b.String() != tt.want {
+				t.Errorf("printURLs() =\n%q\nwant\n%q", b.String(), tt.want)
+			}
+			if missing != tt.wantMissing {
+				t.Errorf("missing = %d, want %d", missing, tt.wantMissing)
+			}
+		})
+	}
+}
+
+func TestPrintGroupedList(t *testing.T) {
+	useASCII(t)
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	repos := []patina.Repository{
+		{Name: "legacy", LastUpdated: now.AddDate(-1, 0, 0), Language: "Go"},
+		{Name: "tool", LastUpdated: now.AddDate(0, -3, 0)},
+		{Name: "web", LastUpdated: now.AddDate(0, 0, -10), Language: "TypeScript"},
+		{Name: "api", LastUpdated: now.AddDate(0, 0, -5), Language: "Go"},
+	}
+	age := func(v patina.RepositoryView) string { return v.Age }
+
+	tests := []struct {
+		by   string
+		want string
+	}{
+		{groupByFreshness, "Green (2)\n" +
+			"[G] " + green + "web" + reset + "  10 days ago\n" +
+			"[G] " + green + "api" + reset + "  5 days ago\n" +
+			"\nYellow (1)\n" +
+			"[Y] " + yellow + "tool" + reset + "  3 months ago\n" +
+			"\nRed (1)\n" +
+			"[R] " + red + "legacy" + reset + "  1 year ago\n"},
+		{groupByLanguage, "Go (2)\n" +
+			"[R] " + red + "legacy" + reset + "  1 year ago\n" +
+			"[G] " + green + "api" + reset + "     5 days ago\n" +
+			"\nTypeScript (1)\n" +
+			"[G] " + green + "web" + reset + "  10 days ago\n" +
+			"\nUnknown (1)\n" +
+			"[Y] " + yellow + "tool" + reset + "  3 months ago\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.by, func(t *testing.T) {
+			var b strings.Builder
+			printGroupedList(&b, groupRepositories(repos, now, tt.by), now, age)
+
+			if b.String() != tt.want {
+				t.Errorf("printGroupedList() =\n%q\nwant\n%q", b.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestGroupRepositoriesArchived(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	repos := []patina.Repository{
+		{Name: "old", LastUpdated: now.AddDate(-3, 0, 0), Archived: true},
+		{Name: "api", LastUpdated: now.AddDate(0, 0, -5)},
+	}
+
+	groups := groupRepositories(repos, now, groupByFreshness)
+	if len(groups) != 2 || groups[1].name != "Archived" || groups[1].repos[0].Name != "old" {
+		t.Errorf("groupRepositories() = %+v, want Green then Archived", groups)
+	}
+}
+
+func TestPrintTable(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	repos := []patina.Repository{
+		{Name: "legacy-api", LastUpdated: now.AddDate(-2, 0, 0)},
+		{Name: "web", LastUpdated: now.AddDate(0, 0, -10)},
+	}
+
+	var b strings.Builder
+	printTable(&b, repos, now, nil)
+
+	want := "Name\tFreshness\tAge\tLast Updated\n" +
+		"legacy-api\tred\t2 years ago\t2022-06-15\n" +
+		"web\tgreen\t10 days ago\t2024-06-05\n"
+	if b.String() != want {
+		t.Errorf("printTable() =\n%q\nwant\n%q", b.String(), want)
+	}
+}
+
+func TestPrintTableOwnership(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	repos := []patina.Repository{
+		{Name: "legacy-api", FullName: "my-org/legacy-api", LastUpdated: now.AddDate(-2, 0, 0)},
+		{Name: "web", FullName: "my-org/web", LastUpdated: now.AddDate(0, 0, -10)},
+	}
+
+	var b strings.Builder
+	printTable(&b, repos, now, patina.Ownership{"legacy-api": "platform"})
+
+	want := "Name\tFreshness\tAge\tLast Updated\tOwner\n" +
+		"legacy-api\tred\t2 years ago\t2022-06-15\tplatform\n" +
+		"web\tgreen\t10 days ago\t2024-06-05\tunowned\n"
+	if b.String() != want {
+		t.Errorf("printTable() =\n%q\nwant\n%q", b.String(), want)
+	}
+}
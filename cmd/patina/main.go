@@ -2,12 +2,45 @@ package main
 
 import (
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/scottbrown/patina"
 	"github.com/spf13/cobra"
 )
 
-var version = "dev"
+var (
+	version      = "dev"
+	verbose      bool
+	emoji        bool
+	noEmoji      bool
+	cacheDir     string
+	quiet        bool
+	borders      bool
+	calendar     bool
+	calendarDays bool
+	activity     string
+	palette      string
+	api          string
+	tokensFile   string
+
+	// maxNameWidth is the --max-name-width value, resolved to fit the terminal
+	// when not given; 0 means names are never truncated
+	maxNameWidth int
+
+	// activityMetric is the parsed --activity-metric value
+	activityMetric patina.ActivityMetric
+
+	// freshnessOptions counts ages as --calendar and --calendar-days select
+	freshnessOptions patina.FreshnessOptions
+
+	// colourPalette is the parsed --palette value
+	colourPalette patina.Palette
+)
 
 var rootCmd = &cobra.Command{
 	Use:   "patina",
@@ -23,14 +56,302 @@ It categorizes repositories by freshness:
 Repository data is cached for 30 days to speed up subsequent commands.
 
 Authentication:
-  Set GITHUB_TOKEN environment variable, or use 'gh auth login'.`,
+  Set GITHUB_TOKEN environment variable, or use 'gh auth login'.
+  For a GitHub App, set GITHUB_APP_ID, GITHUB_APP_INSTALLATION_ID, and
+  GITHUB_APP_PRIVATE_KEY_PATH.`,
 	Version: version,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		freshnessOptions = patina.FreshnessOptions{CalendarMonths: calendar, CalendarDays: calendarDays}
+
+		metric, ok := patina.ParseActivityMetric(activity)
+		if !ok {
+			return fmt.Errorf("invalid activity-metric value: %q (must be pushed or updated)", activity)
+		}
+		activityMetric = metric
+
+		p, ok := patina.ParsePalette(palette)
+		if !ok {
+			return fmt.Errorf("invalid palette value: %q (must be default or colorblind)", palette)
+		}
+		colourPalette = p
+
+		if api != patina.BackendREST && api != patina.BackendGraphQL {
+			return fmt.Errorf("invalid api value: %q (must be rest or graphql)", api)
+		}
+
+		if maxNameWidth < 0 {
+			return fmt.Errorf("invalid max-name-width value: %d (must be 0 or greater)", maxNameWidth)
+		}
+		if !cmd.Flags().Changed("max-name-width") {
+			maxNameWidth = fitNameWidth(cmd.OutOrStdout())
+		}
+
+		switch {
+		case verbose:
+			handler := slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug})
+			slog.SetDefault(slog.New(handler))
+		case quiet:
+			handler := slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError})
+			slog.SetDefault(slog.New(handler))
+		}
+		return nil
+	},
 }
 
 func init() {
+	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Enable debug logging to stderr")
+	rootCmd.PersistentFlags().BoolVar(&emoji, "emoji", false, "Always use emoji freshness indicators")
+	rootCmd.PersistentFlags().BoolVar(&noEmoji, "no-emoji", false, "Use ASCII freshness indicators ([G], [Y], [R]) instead of emoji")
+	rootCmd.MarkFlagsMutuallyExclusive("emoji", "no-emoji")
+	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "Suppress non-essential output such as progress messages, notes, and warnings")
+	rootCmd.MarkFlagsMutuallyExclusive("verbose", "quiet")
+	rootCmd.PersistentFlags().BoolVar(&borders, "borders", false, "Draw borders around repository tables")
+	rootCmd.PersistentFlags().StringVar(&activity, "activity-metric", string(patina.ActivityPushed), "Timestamp that drives freshness (pushed, updated)")
+	rootCmd.PersistentFlags().StringVar(&palette, "palette", string(patina.PaletteDefault), "Freshness colours in the terminal and report (default, colorblind)")
+	rootCmd.PersistentFlags().BoolVar(&calendar, "calendar", false, "Count ages and thresholds in calendar months instead of 30-day months")
+	rootCmd.PersistentFlags().BoolVar(&calendarDays, "calendar-days", false, "Count ages in days by local calendar date, so \"today\" means today's date, instead of 24-hour periods")
+	rootCmd.PersistentFlags().StringVar(&api, "api", patina.BackendREST, "GitHub API used to list repositories (rest, graphql); graphql needs a token or GitHub App")
+	rootCmd.PersistentFlags().StringVar(&tokensFile, "tokens-file", "", "File of GitHub tokens, one per line, to rotate across as each nears its rate limit")
+	rootCmd.PersistentFlags().IntVar(&maxNameWidth, "max-name-width", 0, "Truncate repository names to this many columns in text output (0 for no limit; fits the terminal by default)")
+	rootCmd.PersistentFlags().StringVar(&cacheDir, "cache-dir", "", "Cache directory (default: $PATINA_CACHE_DIR or the user cache directory)")
+
 	rootCmd.AddCommand(scanCmd)
 	rootCmd.AddCommand(listCmd)
 	rootCmd.AddCommand(reportCmd)
+	rootCmd.AddCommand(whyCmd)
+	rootCmd.AddCommand(compareCmd)
+	rootCmd.AddCommand(metricsCmd)
+	rootCmd.AddCommand(warmCmd)
+	rootCmd.AddCommand(leaderboardCmd)
+	rootCmd.AddCommand(cacheCmd)
+	rootCmd.AddCommand(reposCmd)
+	rootCmd.AddCommand(cloneScriptCmd)
+	rootCmd.AddCommand(archiveCmd)
+	rootCmd.AddCommand(rateLimitCmd)
+	rootCmd.AddCommand(schemaCmd)
+}
+
+// useEmoji reports whether freshness indicators should be rendered as emoji.
+// Unless overridden by --emoji or --no-emoji, emoji are used when the locale
+// is UTF-8 or no locale is set.
+func useEmoji() bool {
+	if emoji {
+		return true
+	}
+	if noEmoji {
+		return false
+	}
+	for _, env := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if v := os.Getenv(env); v != "" {
+			v = strings.ToLower(v)
+			return strings.Contains(v, "utf-8") || strings.Contains(v, "utf8")
+		}
+	}
+	return true
+}
+
+// newCache opens the --cache-dir directory if one was given, or the default cache.
+func newCache() (*patina.Cache, error) {
+	if cacheDir != "" {
+		return patina.NewCacheWithDir(cacheDir), nil
+	}
+	return patina.NewCache()
+}
+
+// newClient creates a GitHub client for the --api backend, using the tokens in
+// the --tokens-file file if one was given.
+func newClient() (patina.GitHubClient, error) {
+	if tokensFile != "" {
+		tokens, err := patina.ReadTokens(tokensFile)
+		if err != nil {
+			return nil, err
+		}
+		if api == patina.BackendGraphQL {
+			return patina.NewGraphQLClientWithTokens(tokens)
+		}
+		return patina.NewGitHubClientWithTokens(tokens), nil
+	}
+
+	if api == patina.BackendGraphQL {
+		return patina.NewGraphQLClient()
+	}
+	return patina.NewGitHubClient(), nil
+}
+
+// newScanner creates a Scanner for the --api backend, using the --cache-dir directory if one was given.
+func newScanner() (*patina.Scanner, error) {
+	client, err := newClient()
+	if err != nil {
+		return nil, err
+	}
+
+	cache, err := newCache()
+	if err != nil {
+		return nil, err
+	}
+	return patina.NewScannerWithDeps(client, cache), nil
+}
+
+// readOnlyFile reads the repository list named by --only-file, returning nil
+// if no file was given.
+func readOnlyFile(path string) ([]string, error) {
+	if path == "" {
+		return nil, nil
+	}
+	return patina.ReadRepositoryList(path)
+}
+
+// readOwnersFile reads the ownership file named by --owners, returning nil if
+// no file was given.
+func readOwnersFile(path string) (patina.Ownership, error) {
+	if path == "" {
+		return nil, nil
+	}
+	return patina.ReadOwnership(path)
+}
+
+// readExemptionsFile reads the exemptions file named by --exemptions,
+// returning nil if no file was given.
+func readExemptionsFile(path string) (patina.Exemptions, error) {
+	if path == "" {
+		return nil, nil
+	}
+	return patina.ReadExemptions(path)
+}
+
+// annotatedViews builds repository views, with their teams set if ownership is loaded.
+func annotatedViews(repos []patina.Repository, now time.Time, ownership patina.Ownership) []patina.RepositoryView {
+	views := freshnessOptions.RepositoryViews(repos, now)
+	if ownership != nil {
+		ownership.Annotate(views)
+	}
+	return views
+}
+
+// resolveOrgs combines the organizations given as arguments with those read
+// from an orgs file, or from stdin when the path is "-", requiring at least min.
+func resolveOrgs(cmd *cobra.Command, args []string, path string, min int) ([]string, error) {
+	orgs := args
+	if path != "" {
+		var r io.Reader = cmd.InOrStdin()
+		if path != "-" {
+			f, err := os.Open(path)
+			if err != nil {
+				return nil, fmt.Errorf("failed to open orgs file: %w", err)
+			}
+			defer f.Close()
+			r = f
+		}
+
+		listed, err := patina.ReadOrganizationList(r)
+		if err != nil {
+			return nil, err
+		}
+		orgs = append(append([]string{}, args...), listed...)
+	}
+
+	if len(orgs) < min {
+		return nil, fmt.Errorf("requires at least %d organization(s) as arguments or in --orgs-file, got %d", min, len(orgs))
+	}
+	return orgs, nil
+}
+
+// filterOnly narrows repos to the named repositories when a list was given,
+// noting any names that were not found in the organization.
+func filterOnly(status io.Writer, org string, repos []patina.Repository, names []string) []patina.Repository {
+	if names == nil {
+		return repos
+	}
+
+	filtered, missing := patina.FilterByNames(repos, names)
+	if len(missing) > 0 {
+		fmt.Fprintf(status, "Not found in %s: %s\n\n", org, strings.Join(missing, ", "))
+	}
+	return filtered
+}
+
+// statusWriter returns w for incidental output such as progress messages and
+// notes, or a writer that discards it when --quiet is set.
+func statusWriter(w io.Writer) io.Writer {
+	if quiet {
+		return io.Discard
+	}
+	return w
+}
+
+// warnCacheWrite logs a scan's non-fatal cache write failure, if any.
+func warnCacheWrite(result *patina.ScanResult) {
+	if result.CacheWriteErr != nil {
+		slog.Warn("scan results were not fully cached", "org", result.Organization, "error", result.CacheWriteErr)
+	}
+	if result.HistoryErr != nil {
+		slog.Warn("failed to record freshness history", "org", result.Organization, "error", result.HistoryErr)
+	}
+}
+
+// printScanSummary writes a one-line health summary of a scan, such as
+// "patina: org=x total=100 green=60 yellow=20 red=20 (from-cache)". It goes
+// to stderr so it never mixes with data written to stdout.
+func printScanSummary(w io.Writer, result *patina.ScanResult) {
+	if quiet {
+		return
+	}
+
+	source := "fetched"
+	if result.FromCache {
+		source = "from-cache"
+	}
+	printSummaryLine(w, result.Organization, freshnessOptions.Summary(result.Repositories, time.Now()), source)
+}
+
+// printSummaryLine writes the health summary line of an organization's counts.
+func printSummaryLine(w io.Writer, org string, summary patina.FreshnessSummary, source string) {
+	if quiet {
+		return
+	}
+	fmt.Fprintf(w, "patina: org=%s total=%d green=%d yellow=%d red=%d (%s)\n",
+		org, summary.Total, summary.Green, summary.Yellow, summary.Red, source)
+}
+
+// symbol returns the terminal indicator for a freshness level.
+func symbol(f patina.Freshness) string {
+	return colourPalette.Symbol(f, useEmoji())
+}
+
+// colour returns the terminal colour code for a freshness level.
+func colour(f patina.Freshness) string {
+	return colourPalette.Colour(f)
+}
+
+// freshnessLabel returns a repository's freshness indicator and its name in
+// the freshness colour.
+func freshnessLabel(view patina.RepositoryView) string {
+	return symbol(view.Freshness) + " " + colour(view.Freshness) + truncateName(view.Name, maxNameWidth) + patina.ColourReset()
+}
+
+const (
+	defaultTerminalWidth = 80 // Assumed when COLUMNS is unset
+	nameWidthReserve     = 40 // Columns left for the indicator, age, and details
+	minNameWidth         = 20
+)
+
+// fitNameWidth returns a name width that keeps repository lines within the
+// terminal when w is one, sized from COLUMNS or a standard 80-column
+// terminal, or 0 for no limit when output is redirected.
+func fitNameWidth(w io.Writer) int {
+	f, ok := w.(*os.File)
+	if !ok {
+		return 0
+	}
+	if info, err := f.Stat(); err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return 0
+	}
+
+	width := defaultTerminalWidth
+	if n, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && n > 0 {
+		width = n
+	}
+	return max(width-nameWidthReserve, minNameWidth)
 }
 
 func main() {
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scottbrown/patina"
+	"github.com/spf13/cobra"
+)
+
+func TestPrintScanSummary(t *testing.T) {
+	now := time.Now()
+	repos := []patina.Repository{
+		{Name: "active", LastUpdated: now.AddDate(0, 0, -5)},
+		{Name: "aging", LastUpdated: now.AddDate(0, -3, 0)},
+		{Name: "stale", LastUpdated: now.AddDate(-1, 0, 0)},
+	}
+
+	tests := []struct {
+		name   string
+		result patina.ScanResult
+		quiet  bool
+		want   string
+	}{
+		{
+			name:   "from cache",
+			result: patina.ScanResult{Organization: "my-org", Repositories: repos, FromCache: true},
+			want:   "patina: org=my-org total=3 green=1 yellow=1 red=1 (from-cache)\n",
+		},
+		{
+			name:   "fetched",
+			result: patina.ScanResult{Organization: "my-org", Repositories: repos},
+			want:   "patina: org=my-org total=3 green=1 yellow=1 red=1 (fetched)\n",
+		},
+		{
+			name:   "quiet",
+			result: patina.ScanResult{Organization: "my-org", Repositories: repos},
+			quiet:  true,
+			want:   "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prev := quiet
+			quiet = tt.quiet
+			t.Cleanup(func() { quiet = prev })
+
+			var b strings.Builder
+			printScanSummary(&b, &tt.result)
+
+			if b.String() != tt.want {
+				t.Errorf("printScanSummary() = %q, want %q", b.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestStatusWriter(t *testing.T) {
+	prev := quiet
+	t.Cleanup(func() { quiet = prev })
+
+	var b strings.Builder
+
+	quiet = false
+	if w := statusWriter(&b); w != &b {
+		t.Error("statusWriter() did not return the given writer when not quiet")
+	}
+
+	quiet = true
+	if w := statusWriter(&b); w != io.Discard {
+		t.Error("statusWriter() did not discard output when quiet")
+	}
+}
+
+func TestFilterOnly(t *testing.T) {
+	repos := []patina.Repository{
+		{Name: "api", FullName: "my-org/api"},
+		{Name: "web", FullName: "my-org/web"},
+	}
+
+	var b strings.Builder
+	if got := filterOnly(&b, "my-org", repos, nil); len(got) != 2 {
+		t.Errorf("filterOnly() without a list returned %d repos, want 2", len(got))
+	}
+
+	got := filterOnly(&b, "my-org", repos, []string{"web", "gone", "old"})
+	if len(got) != 1 || got[0].Name != "web" {
+		t.Errorf("filterOnly() = %v, want [web]", got)
+	}
+	if want := "Not found in my-org: gone, old\n\n"; b.String() != want {
+		t.Errorf("filterOnly() note = %q, want %q", b.String(), want)
+	}
+}
+
+func TestResolveOrgs(t *testing.T) {
+	cmd := &cobra.Command{}
+	cmd.SetIn(strings.NewReader("# from stdin\norg-b\n\norg-c\n"))
+
+	orgs, err := resolveOrgs(cmd, []string{"org-a"}, "-", 1)
+	if err != nil {
+		t.Fatalf("resolveOrgs() error = %v", err)
+	}
+	if strings.Join(orgs, ",") != "org-a,org-b,org-c" {
+		t.Errorf("orgs = %v, want [org-a org-b org-c]", orgs)
+	}
+
+	if _, err := resolveOrgs(cmd, []string{"org-a"}, "", 2); err == nil {
+		t.Error("resolveOrgs() error = nil with too few organizations, want error")
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/scottbrown/patina"
+	"github.com/spf13/cobra"
+)
+
+var (
+	metricsOutput  string
+	metricsRefresh bool
+	metricsForks   bool
+	metricsMirrors bool
+	metricsOrgs    string
+)
+
+var metricsCmd = &cobra.Command{
+	Use:   "metrics <organization>...",
+	Short: "Print freshness counts as Prometheus metrics",
+	Long: `Metrics prints the freshness summary of one or more GitHub organizations
+in the Prometheus text exposition format:
+
+  patina_repositories_total{org="my-org"} 42
+  patina_repositories_by_freshness{org="my-org",freshness="red"} 12
+  patina_scan_timestamp_seconds{org="my-org"} 1718452800
+
+Use -o to write the metrics to a file, for example in the directory read by
+node_exporter's textfile collector. The file is replaced atomically so the
+collector never reads a partial write.
+
+Use --orgs-file to read organizations from a file, one per line (- reads
+stdin), in addition to any given as arguments.
+
+Forked repositories are excluded by default. Use --include-forks to count them.
+Mirrors are also excluded; use --include-mirrors to count them.
+
+Example:
+  patina metrics my-org -o /var/lib/node_exporter/textfile/patina.prom`,
+	Args: cobra.ArbitraryArgs,
+	RunE: runMetrics,
+}
+
+func init() {
+	metricsCmd.Flags().StringVarP(&metricsOutput, "output", "o", "", "Output file path (default: stdout)")
+	metricsCmd.Flags().BoolVarP(&metricsRefresh, "refresh", "r", false, "Force refresh from GitHub API")
+	metricsCmd.Flags().StringVar(&metricsOrgs, "orgs-file", "", "Read organizations from this file, one per line (- for stdin)")
+	metricsCmd.Flags().BoolVar(&metricsForks, "include-forks", false, "Include forked repositories")
+	metricsCmd.Flags().BoolVar(&metricsMirrors, "include-mirrors", false, "Include mirrors of repositories maintained elsewhere")
+}
+
+func runMetrics(cmd *cobra.Command, args []string) error {
+	orgs, err := resolveOrgs(cmd, args, metricsOrgs, 1)
+	if err != nil {
+		return err
+	}
+
+	scanner, err := newScanner()
+	if err != nil {
+		return fmt.Errorf("failed to initialize scanner: %w", err)
+	}
+
+	now := time.Now()
+
+	var snapshots []patina.MetricsSnapshot
+	for _, org := range orgs {
+		result, err := scanner.Scan(org, patina.ScanOptions{Refresh: metricsRefresh, IncludeForks: metricsForks, IncludeMirrors: metricsMirrors, ActivityMetric: activityMetric, Freshness: freshnessOptions})
+		if err != nil {
+			return fmt.Errorf("failed to scan organization %s: %w", org, err)
+		}
+		warnCacheWrite(result)
+		printScanSummary(cmd.ErrOrStderr(), result)
+		snapshots = append(snapshots, patina.MetricsSnapshot{
+			Organization: org,
+			Summary:      freshnessOptions.Summary(result.Repositories, now),
+			FetchedAt:    result.FetchedAt,
+		})
+	}
+
+	return writeMetrics(cmd.OutOrStdout(), metricsOutput, snapshots)
+}
+
+// writeMetrics writes the metrics to w, or replaces the file at path if one
+// is given so a collector never reads a partial file.
+func writeMetrics(w io.Writer, path string, snapshots []patina.MetricsSnapshot) error {
+	if path == "" {
+		return patina.WritePrometheusMetrics(w, snapshots)
+	}
+
+	// Write to a temporary file in the same directory and rename it into place
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".patina-metrics-*")
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := patina.WritePrometheusMetrics(tmp, snapshots); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write metrics: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write metrics: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return fmt.Errorf("failed to write metrics: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write metrics: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scottbrown/patina"
+)
+
+func TestWriteMetrics(t *testing.T) {
+	snapshots := []patina.MetricsSnapshot{{
+		Organization: "my-org",
+		Summary:      patina.FreshnessSummary{Total: 3, Green: 1, Yellow: 1, Red: 1},
+		FetchedAt:    time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC),
+	}}
+
+	var b strings.Builder
+	if err := writeMetrics(&b, "", snapshots); err != nil {
+		t.Fatalf("writeMetrics() error = %v", err)
+	}
+	if !strings.Contains(b.String(), `patina_repositories_total{org="my-org"} 3`) {
+		t.Errorf("writeMetrics() wrote %q, want the metrics", b.String())
+	}
+
+	// With a path the metrics go to the file, not the writer
+	path := filepath.Join(t.TempDir(), "patina.prom")
+	var out strings.Builder
+	if err := writeMetrics(&out, path, snapshots); err != nil {
+		t.Fatalf("writeMetrics() error = %v", err)
+	}
+	if out.String() != "" {
+		t.Errorf("writeMetrics() wrote %q to the writer, want nothing", out.String())
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if string(data) != b.String() {
+		t.Errorf("file = %q, want %q", data, b.String())
+	}
+}
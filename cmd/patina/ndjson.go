@@ -0,0 +1,99 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/scottbrown/patina"
+	"github.com/spf13/cobra"
+)
+
+// defaultNDJSONOutput is the output file for --format ndjson when -o is not given.
+const defaultNDJSONOutput = "patina-report.ndjson"
+
+// runNDJSONReport writes every organization's repositories, one JSON record
+// per line, each organization followed by its summary line.
+func runNDJSONReport(cmd *cobra.Command, scanner *patina.Scanner, orgs []string, only []string, ownership patina.Ownership, exemptions patina.Exemptions) error {
+	path := reportOutput
+	if !cmd.Flags().Changed("output") {
+		path = defaultNDJSONOutput
+	}
+
+	write := func(w, status io.Writer) error {
+		for _, org := range orgs {
+			result, err := scanForReport(cmd, scanner, status, org, only)
+			if err != nil {
+				return err
+			}
+			if err := writeReportNDJSON(w, result, ownership, exemptions, time.Now()); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	// Keep stdout clean for the records when writing to it
+	if path == "-" {
+		status := statusWriter(cmd.ErrOrStderr())
+		if err := write(cmd.OutOrStdout(), status); err != nil {
+			return err
+		}
+		fmt.Fprintln(status, "Report generated: stdout")
+		return nil
+	}
+
+	status := statusWriter(cmd.OutOrStdout())
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	defer f.Close()
+
+	if err := write(f, status); err != nil {
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(status, "Report generated: %s\n", path)
+	return nil
+}
+
+// writeReportNDJSON writes a scanned organization's repositories, oldest
+// first, followed by its summary, as one line of JSON each.
+func writeReportNDJSON(w io.Writer, result *patina.ScanResult, ownership patina.Ownership, exemptions patina.Exemptions, now time.Time) error {
+	repos := result.Repositories
+	patina.SortByAge(repos)
+
+	views := annotatedViews(repos, now, ownership)
+	exemptions.Annotate(views)
+
+	enc := json.NewEncoder(w)
+	for _, view := range views {
+		record := ndjsonRepository{Type: ndjsonTypeRepository, Organization: result.Organization, RepositoryView: view}
+		if err := enc.Encode(record); err != nil {
+			return fmt.Errorf("failed to encode output: %w", err)
+		}
+	}
+
+	summary := ndjsonSummary{
+		Type:            ndjsonTypeSummary,
+		Organization:    result.Organization,
+		GeneratedAt:     now.Truncate(time.Second),
+		FetchedAt:       result.FetchedAt,
+		FromCache:       result.FromCache,
+		Summary:         freshnessOptions.SummaryWithExemptions(repos, exemptions, now),
+		Repositories:    len(views),
+		ExcludedForks:   result.ExcludedForks,
+		ExcludedMirrors: result.ExcludedMirrors,
+		NeverPushed:     result.NeverPushed,
+	}
+	if err := enc.Encode(summary); err != nil {
+		return fmt.Errorf("failed to encode output: %w", err)
+	}
+	return nil
+}
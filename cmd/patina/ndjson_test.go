@@ -0,0 +1,67 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scottbrown/patina"
+)
+
+func TestWriteReportNDJSON(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	result := &patina.ScanResult{
+		Organization: "my-org",
+		FetchedAt:    now.Add(-time.Hour),
+		Repositories: []patina.Repository{
+			{Name: "api", FullName: "my-org/api", Stars: 12, Language: "Go", Owner: "my-org", LastUpdated: now.AddDate(0, 0, -3)},
+			{Name: "spec", FullName: "my-org/spec", LastUpdated: now.AddDate(-2, 0, 0)},
+		},
+		ExcludedForks: 1,
+	}
+	ownership := patina.Ownership{"api": "platform"}
+	exemptions := patina.Exemptions{"spec": "frozen"}
+
+	var b strings.Builder
+	if err := writeReportNDJSON(&b, result, ownership, exemptions, now); err != nil {
+		t.Fatalf("writeReportNDJSON() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want 2 repositories and a summary:\n%s", len(lines), b.String())
+	}
+
+	var first map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &first); err != nil {
+		t.Fatalf("line 1 is not JSON: %v", err)
+	}
+	// Oldest first, with the exemption from the file
+	if first["type"] != "repository" || first["organization"] != "my-org" || first["full_name"] != "my-org/spec" || first["exemption"] != "frozen" {
+		t.Errorf("line 1 = %s", lines[0])
+	}
+	if first["last_updated"] != "2022-06-15T12:00:00Z" {
+		t.Errorf("last_updated = %v, want RFC 3339", first["last_updated"])
+	}
+
+	var second map[string]any
+	if err := json.Unmarshal([]byte(lines[1]), &second); err != nil {
+		t.Fatalf("line 2 is not JSON: %v", err)
+	}
+	if second["stars"] != float64(12) || second["language"] != "Go" || second["owner"] != "my-org" || second["team"] != "platform" || second["freshness"] != "green" {
+		t.Errorf("line 2 = %s", lines[1])
+	}
+
+	var summary ndjsonSummary
+	if err := json.Unmarshal([]byte(lines[2]), &summary); err != nil {
+		t.Fatalf("line 3 is not a summary: %v", err)
+	}
+	want := patina.FreshnessSummary{Green: 1, Exempted: 1, Total: 2}
+	if summary.Type != "summary" || summary.Summary != want || summary.Repositories != 2 || summary.ExcludedForks != 1 {
+		t.Errorf("summary = %+v", summary)
+	}
+	if !strings.Contains(lines[2], `"generated_at":"2024-06-15T12:00:00Z"`) {
+		t.Errorf("summary line = %s, want an RFC 3339 generated_at", lines[2])
+	}
+}
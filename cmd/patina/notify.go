@@ -0,0 +1,54 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// defaultNotifyTimeout bounds how long a --notify-url POST may take.
+const defaultNotifyTimeout = 10 * time.Second
+
+// notification is the JSON body posted to --notify-url after a scan. The
+// organization and red count are repeated at the top level, alongside a
+// one-line text summary, so simple endpoints can alert without digging
+// into the nested summary.
+type notification struct {
+	scanOutput
+	Red  int    `json:"red"`
+	Text string `json:"text"`
+}
+
+// newNotification builds the notification for a scan's structured output.
+func newNotification(output scanOutput) notification {
+	s := output.Summary
+	return notification{
+		scanOutput: output,
+		Red:        s.Red,
+		Text: fmt.Sprintf("patina: %s has %d stale repositories (%d total, %d green, %d yellow)",
+			output.Organization, s.Red, s.Total, s.Green, s.Yellow),
+	}
+}
+
+// sendNotification posts v as JSON to url, failing if the endpoint does not
+// answer with a 2xx status within timeout.
+func sendNotification(url string, timeout time.Duration, v any) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+
+	client := &http.Client{Timeout: timeout}
+	resp, err := client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to send notification: %w", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("notification endpoint returned status %d", resp.StatusCode)
+	}
+	return nil
+}
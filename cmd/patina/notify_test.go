@@ -0,0 +1,68 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/scottbrown/patina"
+)
+
+func TestSendNotification(t *testing.T) {
+	var got map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("method = %s, want POST", r.Method)
+		}
+		if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Content-Type = %q, want application/json", ct)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("failed to decode body: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	output := scanOutput{
+		Organization: "my-org",
+		Summary:      patina.FreshnessSummary{Green: 5, Yellow: 3, Red: 2, Total: 10},
+	}
+	if err := sendNotification(server.URL, time.Second, newNotification(output)); err != nil {
+		t.Fatalf("sendNotification() error = %v", err)
+	}
+
+	if got["organization"] != "my-org" {
+		t.Errorf("organization = %v, want my-org", got["organization"])
+	}
+	if got["red"] != float64(2) {
+		t.Errorf("red = %v, want 2", got["red"])
+	}
+	if want := "patina: my-org has 2 stale repositories (10 total, 5 green, 3 yellow)"; got["text"] != want {
+		t.Errorf("text = %v, want %q", got["text"], want)
+	}
+	if _, ok := got["summary"]; !ok {
+		t.Error("body missing summary")
+	}
+}
+
+func TestSendNotificationFailures(t *testing.T) {
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+
+	if err := sendNotification(failing.URL, time.Second, notification{}); err == nil {
+		t.Error("sendNotification() error = nil for status 500, want error")
+	}
+
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	}))
+	defer slow.Close()
+
+	if err := sendNotification(slow.URL, 10*time.Millisecond, notification{}); err == nil {
+		t.Error("sendNotification() error = nil after timeout, want error")
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats accepted by --output on scan, list, and compare.
+const (
+	outputText = "text"
+	outputJSON = "json"
+	outputYAML = "yaml"
+
+	// outputTable is accepted by list only: a plain tab-separated table
+	outputTable = "table"
+
+	// outputSummaryJSON is accepted by scan only: one line of counts per organization
+	outputSummaryJSON = "summary-json"
+
+	// outputCompact is accepted by scan only: a single line of counts for shell prompts
+	outputCompact = "compact"
+)
+
+// validateOutputFormat checks an --output value.
+func validateOutputFormat(format string) error {
+	switch format {
+	case outputText, outputJSON, outputYAML:
+		return nil
+	}
+	return fmt.Errorf("invalid output value: %q (must be text, json, or yaml)", format)
+}
+
+// writeSummaryJSON writes each summary as a single line of JSON.
+func writeSummaryJSON(w io.Writer, outputs []scanOutput) error {
+	enc := json.NewEncoder(w)
+	for _, output := range outputs {
+		if err := enc.Encode(newSummaryOutput(output)); err != nil {
+			return fmt.Errorf("failed to encode output: %w", err)
+		}
+	}
+	return nil
+}
+
+// writeStructured writes v as JSON or YAML. The YAML is converted from the
+// JSON encoding, so both formats always carry the same fields and values and
+// timestamps are RFC 3339 strings in each.
+func writeStructured(w io.Writer, format string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode output: %w", err)
+	}
+
+	if format == outputJSON {
+		_, err := fmt.Fprintf(w, "%s\n", data)
+		return err
+	}
+
+	// JSON is valid YAML; decoding it into a node keeps the field order
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("failed to encode output: %w", err)
+	}
+	resetYAMLStyle(&doc)
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return fmt.Errorf("failed to encode output: %w", err)
+	}
+	return enc.Close()
+}
+
+// resetYAMLStyle clears the flow and quoting styles carried over from JSON so
+// the document is written in block style, quoting only where YAML requires it.
+func resetYAMLStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		resetYAMLStyle(child)
+	}
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scottbrown/patina"
+	"gopkg.in/yaml.v3"
+)
+
+func TestValidateOutputFormat(t *testing.T) {
+	for _, format := range []string{"text", "json", "yaml"} {
+		if err := validateOutputFormat(format); err != nil {
+			t.Errorf("validateOutputFormat(%q) error = %v", format, err)
+		}
+	}
+	if err := validateOutputFormat("xml"); err == nil {
+		t.Error("validateOutputFormat(\"xml\") error = nil, want error")
+	}
+}
+
+func TestWriteStructuredYAMLMatchesJSON(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	out := listOutput{
+		Organization: "my-org",
+		FetchedAt:    now,
+		Repositories: patina.NewRepositoryViews([]patina.Repository{
+			{Name: "api", FullName: "my-org/api", LastUpdated: now.AddDate(-1, 0, 0), Stars: 3},
+			{Name: "123", FullName: "my-org/123", LastUpdated: now.AddDate(0, 0, -5)},
+		}, now),
+	}
+
+	var jsonOut, yamlOut strings.Builder
+	if err := writeStructured(&jsonOut, outputJSON, out); err != nil {
+		t.Fatalf("writeStructured(json) error = %v", err)
+	}
+	if err := writeStructured(&yamlOut, outputYAML, out); err != nil {
+		t.Fatalf("writeStructured(yaml) error = %v", err)
+	}
+
+	var fromJSON, fromYAML map[string]any
+	if err := json.Unmarshal([]byte(jsonOut.String()), &fromJSON); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if err := yaml.Unmarshal([]byte(yamlOut.String()), &fromYAML); err != nil {
+		t.Fatalf("yaml.Unmarshal() error = %v", err)
+	}
+
+	// Normalise YAML integers to the float64 that JSON decodes numbers into
+	normalised, _ := json.Marshal(fromYAML)
+	fromYAML = nil
+	if err := json.Unmarshal(normalised, &fromYAML); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(fromJSON, fromYAML) {
+		t.Errorf("YAML content differs from JSON\njson: %v\nyaml: %v", fromJSON, fromYAML)
+	}
+
+	for _, want := range []string{
+		"organization: my-org\n",
+		"fetched_at: \"2024-06-15T12:00:00Z\"\n",
+		"  - name: api\n",
+		"  - name: \"123\"\n",
+	} {
+		if !strings.Contains(yamlOut.String(), want) {
+			t.Errorf("YAML output missing %q\n%s", want, yamlOut.String())
+		}
+	}
+	if strings.Contains(yamlOut.String(), "---") || strings.Contains(yamlOut.String(), "{") {
+		t.Errorf("YAML output is not a single block-style document\n%s", yamlOut.String())
+	}
+}
+
+func TestWriteSummaryJSON(t *testing.T) {
+	fetched := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	outputs := []scanOutput{
+		{
+			Organization: "org-a",
+			FetchedAt:    fetched,
+			FromCache:    true,
+			Summary:      patina.FreshnessSummary{Green: 6, Yellow: 2, Red: 2, Total: 10},
+			TopStale:     patina.NewRepositoryViews([]patina.Repository{{Name: "legacy"}}, fetched),
+		},
+		{Organization: "org-b", FetchedAt: fetched},
+	}
+
+	var b strings.Builder
+	if err := writeSummaryJSON(&b, outputs); err != nil {
+		t.Fatalf("writeSummaryJSON() error = %v", err)
+	}
+
+	want := `{"org":"org-a","total":10,"green":6,"yellow":2,"red":2,"fetched_at":"2024-06-15T12:00:00Z","from_cache":true}` + "\n" +
+		`{"org":"org-b","total":0,"green":0,"yellow":0,"red":0,"fetched_at":"2024-06-15T12:00:00Z","from_cache":false}` + "\n"
+	if b.String() != want {
+		t.Errorf("writeSummaryJSON() =\n%s\nwant\n%s", b.String(), want)
+	}
+}
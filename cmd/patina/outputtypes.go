@@ -0,0 +1,108 @@
+package main
+
+import (
+	"time"
+
+	"github.com/scottbrown/patina"
+)
+
+// The types in this file define the JSON written by --output json and yaml,
+// scan --output summary-json, and report --format ndjson. Consumers depend on
+// their field names, so renaming or removing a field is a breaking change.
+// The schemas generated from them are shipped in the schema directory, and
+// TestShippedSchemas fails until those are regenerated with "task schema".
+
+// scanOutput is the structured form of the scan command's output.
+type scanOutput struct {
+	Organization        string                  `json:"organization"`
+	FetchedAt           time.Time               `json:"fetched_at"`
+	FromCache           bool                    `json:"from_cache"`
+	Summary             patina.FreshnessSummary `json:"summary"`
+	Percentages         percentagesOutput       `json:"percentages"`
+	Unlicensed          int                     `json:"unlicensed"`
+	StaleIssuesDisabled int                     `json:"stale_issues_disabled"`
+	StaleSizeKB         int64                   `json:"stale_size_kb"`
+	ExcludedForks       int                     `json:"excluded_forks"`
+	ExcludedMirrors     int                     `json:"excluded_mirrors"`
+	NeverPushed         int                     `json:"never_pushed"`
+	Renamed             []patina.Rename         `json:"renamed,omitempty"`
+	Changes             *patina.Changes         `json:"changes,omitempty"`
+	TopStale            []patina.RepositoryView `json:"top_stale"`
+}
+
+// percentagesOutput is the percentage of repositories at each freshness level.
+type percentagesOutput struct {
+	Green  float64 `json:"green"`
+	Yellow float64 `json:"yellow"`
+	Red    float64 `json:"red"`
+}
+
+// newPercentagesOutput calculates the percentages for a summary.
+func newPercentagesOutput(summary patina.FreshnessSummary) percentagesOutput {
+	green, yellow, red := patina.CalculatePercentages(summary)
+	return percentagesOutput{Green: green, Yellow: yellow, Red: red}
+}
+
+// summaryOutput is the compact form of the scan command's output, for health
+// checks and alerting. Its fields are kept stable.
+type summaryOutput struct {
+	Organization string    `json:"org"`
+	Total        int       `json:"total"`
+	Green        int       `json:"green"`
+	Yellow       int       `json:"yellow"`
+	Red          int       `json:"red"`
+	FetchedAt    time.Time `json:"fetched_at"`
+	FromCache    bool      `json:"from_cache"`
+}
+
+// newSummaryOutput reduces a scan's structured output to its counts.
+func newSummaryOutput(output scanOutput) summaryOutput {
+	return summaryOutput{
+		Organization: output.Organization,
+		Total:        output.Summary.Total,
+		Green:        output.Summary.Green,
+		Yellow:       output.Summary.Yellow,
+		Red:          output.Summary.Red,
+		FetchedAt:    output.FetchedAt,
+		FromCache:    output.FromCache,
+	}
+}
+
+// listOutput is the structured form of the list command's output.
+type listOutput struct {
+	Organization    string                  `json:"organization"`
+	FetchedAt       time.Time               `json:"fetched_at"`
+	FromCache       bool                    `json:"from_cache"`
+	ExcludedForks   int                     `json:"excluded_forks"`
+	ExcludedMirrors int                     `json:"excluded_mirrors"`
+	NeverPushed     int                     `json:"never_pushed"`
+	Repositories    []patina.RepositoryView `json:"repositories"`
+}
+
+// Record types of the report's NDJSON lines.
+const (
+	ndjsonTypeRepository = "repository"
+	ndjsonTypeSummary    = "summary"
+)
+
+// ndjsonRepository is one repository line of the NDJSON report: the full
+// repository view, with any team and exemption, tagged with its organization.
+type ndjsonRepository struct {
+	Type         string `json:"type"`
+	Organization string `json:"organization"`
+	patina.RepositoryView
+}
+
+// ndjsonSummary is the metadata line that follows an organization's repositories.
+type ndjsonSummary struct {
+	Type            string                  `json:"type"`
+	Organization    string                  `json:"organization"`
+	GeneratedAt     time.Time               `json:"generated_at"`
+	FetchedAt       time.Time               `json:"fetched_at"`
+	FromCache       bool                    `json:"from_cache"`
+	Summary         patina.FreshnessSummary `json:"summary"`
+	Repositories    int                     `json:"repositories"`
+	ExcludedForks   int                     `json:"excluded_forks"`
+	ExcludedMirrors int                     `json:"excluded_mirrors"`
+	NeverPushed     int                     `json:"never_pushed"`
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/scottbrown/patina"
+	"github.com/spf13/cobra"
+)
+
+var rateLimitCmd = &cobra.Command{
+	Use:   "ratelimit",
+	Short: "Show the remaining GitHub API quota",
+	Long: `Ratelimit shows how many GitHub API requests remain for the core,
+search, and GraphQL resources, and when each quota resets. It uses the same
+credentials as scan, so it confirms that authentication works and that
+enough quota is left before a large run. Checking does not use any quota.
+
+Example:
+  patina ratelimit`,
+	Args: cobra.NoArgs,
+	RunE: runRateLimit,
+}
+
+func runRateLimit(cmd *cobra.Command, args []string) error {
+	client, err := newClient()
+	if err != nil {
+		return fmt.Errorf("failed to initialize client: %w", err)
+	}
+
+	limits, err := patina.FetchRateLimits(client)
+	if err != nil {
+		return fmt.Errorf("failed to fetch rate limits: %w", err)
+	}
+
+	printRateLimits(cmd.OutOrStdout(), limits, time.Now())
+	return nil
+}
+
+// printRateLimits writes the remaining quota and reset time of each resource.
+func printRateLimits(w io.Writer, limits patina.RateLimits, now time.Time) {
+	title := "GitHub API Rate Limits"
+	fmt.Fprintln(w, title)
+	fmt.Fprintln(w, strings.Repeat("=", len(title)))
+	fmt.Fprintln(w)
+
+	t := &table{borders: borders}
+	t.addRow("Resource", "Remaining", "Resets")
+	for _, resource := range []struct {
+		name  string
+		limit patina.RateLimit
+	}{
+		{"core", limits.Core},
+		{"search", limits.Search},
+		{"graphql", limits.GraphQL},
+	} {
+		t.addRow(resource.name, fmt.Sprintf("%d/%d", resource.limit.Remaining, resource.limit.Limit), formatReset(resource.limit.Reset, now))
+	}
+	t.render(w)
+}
+
+// formatReset describes when a quota resets, in local time and relative to now.
+func formatReset(reset, now time.Time) string {
+	in := reset.Sub(now).Round(time.Minute)
+	if in <= 0 {
+		return reset.Local().Format("15:04") + " (now)"
+	}
+	return fmt.Sprintf("%s (in %s)", reset.Local().Format("15:04"), strings.TrimSuffix(in.String(), "0s"))
+}
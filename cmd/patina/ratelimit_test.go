@@ -0,0 +1,35 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scottbrown/patina"
+)
+
+func TestPrintRateLimits(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	limits := patina.RateLimits{
+		Core:    patina.RateLimit{Limit: 5000, Remaining: 4990, Reset: now.Add(42 * time.Minute)},
+		Search:  patina.RateLimit{Limit: 30, Remaining: 30, Reset: now.Add(time.Minute)},
+		GraphQL: patina.RateLimit{Limit: 5000, Remaining: 0, Reset: now.Add(90 * time.Minute)},
+	}
+
+	var b strings.Builder
+	printRateLimits(&b, limits, now)
+	out := b.String()
+
+	for _, want := range []string{"4990/5000", "30/30", "0/5000", "(in 42m)", "(in 1h30m)"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestFormatResetPast(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	if got := formatReset(now.Add(-time.Minute), now); !strings.HasSuffix(got, "(now)") {
+		t.Errorf("formatReset() = %q, want it to reset now", got)
+	}
+}
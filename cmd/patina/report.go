@@ -3,20 +3,55 @@ package main
 import (
 	"fmt"
 	"html/template"
+	"io"
+	"log/slog"
+	"math"
 	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/scottbrown/patina"
 	"github.com/spf13/cobra"
 )
 
+// defaultReportDir is the output directory when reporting on several organizations.
+const defaultReportDir = "patina-reports"
+
+// Report formats accepted by --format.
+const (
+	reportFormatHTML   = "html"
+	reportFormatSlack  = "slack"
+	reportFormatNDJSON = "ndjson"
+)
+
+// defaultSlackOutput is the output file for --format slack when -o is not given.
+const defaultSlackOutput = "patina-report.json"
+
+// gradientMaxDays is the age at which a --gradient status badge reaches full red.
+const gradientMaxDays = 730
+
+// descriptionLength is the number of characters of a description shown in the report table.
+const descriptionLength = 60
+
 var (
-	reportOutput  string
-	reportRefresh bool
+	reportOutput     string
+	reportRefresh    bool
+	reportLastCommit bool
+	reportForks      bool
+	reportMirrors    bool
+	reportOnly       string
+	reportOwners     string
+	reportExempt     string
+	reportOrgs       string
+	reportGradient   bool
+	reportArchived   bool
+	reportProtection bool
+	reportFormat     string
 )
 
 var reportCmd = &cobra.Command{
-	Use:   "report <organization>",
+	Use:   "report <organization>...",
 	Short: "Generate an HTML report of repository freshness",
 	Long: `Report generates a standalone HTML file containing a visual summary
 of repository freshness for a GitHub organization.
@@ -24,140 +59,494 @@ of repository freshness for a GitHub organization.
 The report includes:
   - Freshness summary with colour-coded counts
   - Visual pie chart of the distribution
-  - Complete table of all repositories with links
+  - Bar chart of repositories by month of last activity
+  - Sparkline of the stale repository count over recent scans
+  - Table of all repositories with links, grouped into collapsible
+    red, yellow, and green sections
+  - Stale repositories grouped by owner
+
+Forked repositories are excluded by default. Use --include-forks to include them.
+Mirrors of repositories maintained elsewhere are also excluded; use
+--include-mirrors to include them.
+Archived repositories are also excluded; use --include-archived to show them
+as a separate grey section and slice of the chart.
+Use --only-file to include only the repositories named in a file, one per line.
+
+Use --owners to read repository ownership from a CSV file of repository and
+team, for organizations that track owners outside GitHub. The report then
+shows each repo's team and groups stale repos by team instead of by GitHub
+owner; repos missing from the file are grouped as unowned.
+
+Use --exemptions to read known-stale repositories, such as frozen reference
+implementations, from a CSV file of repository and reason. Exempt stale repos
+stay in the table, marked exempt with the reason as a tooltip, but are
+counted as exempted rather than stale.
+
+Use --with-protection to check whether each repo's default branch is
+protected and show it for stale repos, where an unprotected branch is a risk.
+This makes one extra API call per repo; results are cached. Reading branch
+protection needs admin access, so repos the token cannot check are shown
+as unknown.
+
+Use --gradient to colour each status badge on a continuous green to red
+scale by exact age, reaching full red at two years, instead of by bucket.
+
+Use -o - to write the report to stdout; progress messages then go to stderr.
+
+Use --format slack to write a Slack Block Kit message instead of HTML (to
+patina-report.json unless -o is given), summarizing the freshness counts and
+linking the 5 most stale repos. Post it to an incoming webhook with, e.g.:
+  patina report my-org --format slack -o - |
+    curl -H 'Content-Type: application/json' -d @- "$SLACK_WEBHOOK_URL"
+
+Use --format ndjson to write one JSON record per line for loading into a
+data warehouse (to patina-report.ndjson unless -o is given). Each repository
+is a "type":"repository" record with every cached field, its freshness, age,
+and any team or exemption, followed by a "type":"summary" record with the
+organization's counts. Timestamps are RFC 3339. Several organizations are
+written one after another to the same stream.
+
+When several organizations are given for an HTML report, -o names a
+directory (default patina-reports) that receives one report-<org>.html file
+per organization and an index.html listing them by stale percentage. Use --orgs-file to read
+organizations from a file, one per line (- reads stdin).
 
 Example:
-  patina report my-org -o report.html`,
-	Args: cobra.ExactArgs(1),
+  patina report my-org -o report.html
+  patina report my-org -o - > report.html
+  patina report org-a org-b -o reports`,
+	Args: cobra.ArbitraryArgs,
 	RunE: runReport,
 }
 
 func init() {
-	reportCmd.Flags().StringVarP(&reportOutput, "output", "o", "patina-report.html", "Output file path")
+	reportCmd.Flags().StringVarP(&reportOutput, "output", "o", "patina-report.html", "Output file path (- for stdout), or directory for several organizations")
+	reportCmd.Flags().StringVar(&reportFormat, "format", reportFormatHTML, "Report format (html, slack, ndjson)")
 	reportCmd.Flags().BoolVarP(&reportRefresh, "refresh", "r", false, "Force refresh from GitHub API")
+	reportCmd.Flags().StringVar(&reportOrgs, "orgs-file", "", "Read organizations from this file, one per line (- for stdin)")
+	reportCmd.Flags().StringVar(&reportOnly, "only-file", "", "Only include repos named in this file (one per line)")
+	reportCmd.Flags().StringVar(&reportOwners, "owners", "", "Annotate repos with teams from this CSV file and group stale repos by team")
+	reportCmd.Flags().StringVar(&reportExempt, "exemptions", "", "CSV file of repo,reason rows; stale repos listed are counted as exempted, not red")
+	reportCmd.Flags().BoolVar(&reportForks, "include-forks", false, "Include forked repositories")
+	reportCmd.Flags().BoolVar(&reportMirrors, "include-mirrors", false, "Include mirrors of repositories maintained elsewhere")
+	reportCmd.Flags().BoolVar(&reportProtection, "with-protection", false, "Check default branch protection and show it for stale repos")
+	reportCmd.Flags().BoolVar(&reportArchived, "include-archived", false, "Include archived repositories as their own state")
+	reportCmd.Flags().BoolVar(&reportGradient, "gradient", false, "Colour status badges on a green to red gradient by exact age")
+	reportCmd.Flags().BoolVar(&reportLastCommit, "with-last-commit", false, "Look up the last commit author and date for each repo")
 }
 
-type reportData struct {
-	Organization string
-	GeneratedAt  string
-	Summary      patina.FreshnessSummary
-	Repositories []repoData
-	GreenPct     float64
-	YellowPct    float64
-	RedPct       float64
+type indexData struct {
+	GeneratedAt   string
+	Organizations []indexEntry
 }
 
-type repoData struct {
-	Name        string
-	FullName    string
-	URL         string
-	Age         string
-	Freshness   string
-	ColourClass string
+type indexEntry struct {
+	patina.OrgComparison
+	File string
+}
+
+// newRepoData converts a repository view into its report row.
+func newRepoData(view patina.RepositoryView, now time.Time) patina.ReportRepository {
+	var lastCommit string
+	if !view.LastCommitDate.IsZero() {
+		lastCommit = fmt.Sprintf("%s by %s", view.LastCommitDate.Format("2006-01-02"), view.LastCommitAuthor)
+	}
+
+	var style template.CSS
+	if reportGradient && view.Freshness != patina.FreshnessArchived {
+		style = gradientStyle(view.AgeDays, colourPalette)
+	}
+
+	return patina.ReportRepository{
+		Name:        view.Name,
+		FullName:    view.FullName,
+		URL:         view.HTMLURL,
+		Description: view.Description,
+		ShortDesc:   truncate(view.Description, descriptionLength),
+		Search:      strings.ToLower(view.FullName + " " + view.Description),
+		Age:         view.Age,
+		Date:        view.LastUpdated.Format("2006-01-02"),
+		LastCommit:  lastCommit,
+		License:     view.License,
+		Protection:  string(view.Protection),
+		Team:        view.Team,
+		Abandoned:   view.Freshness == patina.FreshnessRed && view.IssuesDisabled,
+		Exemption:   view.Exemption,
+		Countdown:   freshnessOptions.Countdown(view.Repository, now),
+		Freshness:   string(view.Freshness),
+		ColourClass: string(view.Freshness),
+		Style:       style,
+	}
+}
+
+// truncate shortens s to at most n characters, ending it with an ellipsis if it was cut.
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return strings.TrimSpace(string(runes[:n-1])) + "…"
+}
+
+// gradientStyle returns a badge style whose hue moves from green to red as
+// the age in days approaches gradientMaxDays. The colour-blind palette moves
+// from blue to purple instead.
+func gradientStyle(ageDays int, palette patina.Palette) template.CSS {
+	fraction := math.Max(0, math.Min(float64(ageDays)/gradientMaxDays, 1))
+	hue := 120 * (1 - fraction)
+	if palette == patina.PaletteColourBlind {
+		hue = 210 + 80*fraction
+	}
+	return template.CSS(fmt.Sprintf("background: hsl(%.0f, 70%%, 88%%); color: hsl(%.0f, 80%%, 25%%);", hue, hue))
 }
 
 func runReport(cmd *cobra.Command, args []string) error {
-	org := args[0]
+	orgs, err := resolveOrgs(cmd, args, reportOrgs, 1)
+	if err != nil {
+		return err
+	}
 
-	scanner, err := patina.NewScanner()
+	if reportFormat != reportFormatHTML && reportFormat != reportFormatSlack && reportFormat != reportFormatNDJSON {
+		return fmt.Errorf("invalid format value: %q (must be html, slack, or ndjson)", reportFormat)
+	}
+	if reportFormat == reportFormatSlack && len(orgs) > 1 {
+		return fmt.Errorf("the slack format supports a single organization")
+	}
+
+	only, err := readOnlyFile(reportOnly)
+	if err != nil {
+		return err
+	}
+
+	ownership, err := readOwnersFile(reportOwners)
+	if err != nil {
+		return err
+	}
+
+	exemptions, err := readExemptionsFile(reportExempt)
+	if err != nil {
+		return err
+	}
+
+	scanner, err := newScanner()
 	if err != nil {
 		return fmt.Errorf("failed to initialize scanner: %w", err)
 	}
 
-	fmt.Printf("Scanning organization: %s\n", org)
+	if reportFormat == reportFormatSlack {
+		return runSlackReport(cmd, scanner, orgs[0], only)
+	}
+	if reportFormat == reportFormatNDJSON {
+		return runNDJSONReport(cmd, scanner, orgs, only, ownership, exemptions)
+	}
+
+	if len(orgs) > 1 {
+		return runMultiOrgReport(cmd, scanner, orgs, only, ownership, exemptions)
+	}
+
+	org := orgs[0]
+
+	// Keep stdout clean for the report when writing to it
+	status := statusWriter(cmd.OutOrStdout())
+	if reportOutput == "-" {
+		status = statusWriter(cmd.ErrOrStderr())
+	}
 
-	result, err := scanner.Scan(org, patina.ScanOptions{Refresh: reportRefresh})
+	data, err := buildReportData(cmd, scanner, status, org, only, ownership, exemptions)
 	if err != nil {
-		return fmt.Errorf("failed to scan organization: %w", err)
+		return err
 	}
 
-	now := time.Now()
+	if reportOutput == "-" {
+		if err := patina.RenderHTMLReport(cmd.OutOrStdout(), data); err != nil {
+			return err
+		}
+		fmt.Fprintln(status, "Report generated: stdout")
+		return nil
+	}
+
+	if err := writeReportFile(reportOutput, htmlReport(data)); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(status, "Report generated: %s\n", reportOutput)
+	return nil
+}
+
+// runSlackReport writes an organization's freshness as a Slack message.
+func runSlackReport(cmd *cobra.Command, scanner *patina.Scanner, org string, only []string) error {
+	path := reportOutput
+	if !cmd.Flags().Changed("output") {
+		path = defaultSlackOutput
+	}
+
+	// Keep stdout clean for the message when writing to it
+	status := statusWriter(cmd.OutOrStdout())
+	if path == "-" {
+		status = statusWriter(cmd.ErrOrStderr())
+	}
+
+	result, err := scanForReport(cmd, scanner, status, org, only)
+	if err != nil {
+		return err
+	}
+	msg := newSlackMessage(org, result.Repositories, time.Now())
+
+	if path == "-" {
+		if err := writeStructured(cmd.OutOrStdout(), outputJSON, msg); err != nil {
+			return err
+		}
+		fmt.Fprintln(status, "Report generated: stdout")
+		return nil
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	defer f.Close()
+
+	if err := writeStructured(f, outputJSON, msg); err != nil {
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(status, "Report generated: %s\n", path)
+	return nil
+}
+
+// runMultiOrgReport writes one report per organization into the output
+// directory, plus an index page linking them.
+func runMultiOrgReport(cmd *cobra.Command, scanner *patina.Scanner, orgs []string, only []string, ownership patina.Ownership, exemptions patina.Exemptions) error {
+	dir := reportOutput
+	if !cmd.Flags().Changed("output") {
+		dir = defaultReportDir
+	}
+	if dir == "-" {
+		return fmt.Errorf("cannot write multiple reports to stdout; use -o <directory>")
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	status := statusWriter(cmd.OutOrStdout())
+	summaries := make(map[string]patina.FreshnessSummary, len(orgs))
+	for _, org := range orgs {
+		data, err := buildReportData(cmd, scanner, status, org, only, ownership, exemptions)
+		if err != nil {
+			return err
+		}
+
+		path := filepath.Join(dir, reportFileName(org))
+		if err := writeReportFile(path, htmlReport(data)); err != nil {
+			return err
+		}
+		fmt.Fprintf(status, "Report generated: %s\n", path)
+
+		summaries[org] = data.Summary
+	}
+
+	indexTmpl, err := template.New("index").Parse(indexTemplate)
+	if err != nil {
+		return fmt.Errorf("failed to parse template: %w", err)
+	}
+
+	index := indexData{GeneratedAt: time.Now().Format("2006-01-02 15:04:05")}
+	for _, row := range patina.CompareSummaries(summaries) {
+		index.Organizations = append(index.Organizations, indexEntry{
+			OrgComparison: row,
+			File:          reportFileName(row.Organization),
+		})
+	}
+
+	path := filepath.Join(dir, "index.html")
+	renderIndex := func(w io.Writer) error {
+		if err := indexTmpl.Execute(w, index); err != nil {
+			return fmt.Errorf("failed to generate index: %w", err)
+		}
+		return nil
+	}
+	if err := writeReportFile(path, renderIndex); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(status, "Index generated: %s\n", path)
+	return nil
+}
+
+// reportFileName returns the file name of an organization's report in multi-org mode.
+func reportFileName(org string) string {
+	return "report-" + org + ".html"
+}
+
+// htmlReport returns a renderer for an organization's HTML report.
+func htmlReport(data patina.ReportInput) func(io.Writer) error {
+	return func(w io.Writer) error {
+		return patina.RenderHTMLReport(w, data)
+	}
+}
+
+// writeReportFile renders to a new file at path.
+func writeReportFile(path string, render func(io.Writer) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	defer f.Close()
+
+	if err := render(f); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// scanForReport scans an organization with the report's options, narrowing
+// the result to the repositories named in the only-file, if any.
+func scanForReport(cmd *cobra.Command, scanner *patina.Scanner, status io.Writer, org string, only []string) (*patina.ScanResult, error) {
+	fmt.Fprintf(status, "Scanning organization: %s\n", org)
+
+	result, err := scanner.Scan(org, patina.ScanOptions{Refresh: reportRefresh, WithLastCommit: reportLastCommit, WithProtection: reportProtection, IncludeForks: reportForks, IncludeMirrors: reportMirrors, IncludeArchived: reportArchived, ActivityMetric: activityMetric, Freshness: freshnessOptions})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan organization: %w", err)
+	}
+	warnCacheWrite(result)
+	printScanSummary(cmd.ErrOrStderr(), result)
 
 	if result.FromCache {
-		fmt.Printf("Using cached data from %s\n", result.FetchedAt.Format("2006-01-02 15:04:05"))
+		fmt.Fprintf(status, "Using cached data from %s\n", result.FetchedAt.Format("2006-01-02 15:04:05"))
+	}
+
+	result.Repositories = filterOnly(status, org, result.Repositories, only)
+	return result, nil
+}
+
+// buildReportData scans an organization and prepares the data for its report.
+func buildReportData(cmd *cobra.Command, scanner *patina.Scanner, status io.Writer, org string, only []string, ownership patina.Ownership, exemptions patina.Exemptions) (patina.ReportInput, error) {
+	result, err := scanForReport(cmd, scanner, status, org, only)
+	if err != nil {
+		return patina.ReportInput{}, err
 	}
 
+	now := time.Now()
+
 	// Prepare report data
-	summary := patina.CalculateSummary(result.Repositories, now)
+	summary := freshnessOptions.SummaryWithExemptions(result.Repositories, exemptions, now)
 
 	// Sort by age (oldest first)
 	patina.SortByAge(result.Repositories)
 
-	var repos []repoData
-	for _, repo := range result.Repositories {
-		freshness := patina.CalculateFreshness(repo.LastUpdated, now)
-		repos = append(repos, repoData{
-			Name:        repo.Name,
-			FullName:    repo.FullName,
-			URL:         repo.HTMLURL,
-			Age:         patina.Age(repo.LastUpdated, now),
-			Freshness:   string(freshness),
-			ColourClass: string(freshness),
+	// Group repositories by freshness, with only the red section expanded
+	labels := patina.NewReportLabels(patina.FreshnessThresholds())
+	groups := []patina.ReportGroup{
+		{Label: "Stale (" + labels.Red + ")", ColourClass: string(patina.FreshnessRed), Open: true},
+		{Label: "Aging (" + labels.Yellow + ")", ColourClass: string(patina.FreshnessYellow)},
+		{Label: "Active (" + labels.Green + ")", ColourClass: string(patina.FreshnessGreen)},
+	}
+	if reportArchived {
+		groups = append(groups, patina.ReportGroup{Label: "Archived", ColourClass: string(patina.FreshnessArchived)})
+	}
+	views := annotatedViews(result.Repositories, now, ownership)
+	exemptions.Annotate(views)
+	for _, view := range views {
+		for i := range groups {
+			if groups[i].ColourClass == string(view.Freshness) {
+				groups[i].Repositories = append(groups[i].Repositories, newRepoData(view, now))
+			}
+		}
+	}
+
+	// Group stale repositories by owner, or by team when ownership is loaded
+	ownerSummaries := freshnessOptions.SummaryByOwner(result.Repositories, now)
+	if ownership != nil {
+		ownerSummaries = freshnessOptions.SummaryByTeam(result.Repositories, ownership, now)
+	}
+	var owners []patina.ReportOwner
+	for _, owner := range ownerSummaries {
+		var stale []patina.ReportRepository
+		red := freshnessOptions.FilterByFreshness(owner.Repositories, patina.FreshnessRed, now)
+		views := annotatedViews(red, now, ownership)
+		exemptions.Annotate(views)
+		for _, view := range views {
+			stale = append(stale, newRepoData(view, now))
+		}
+		owners = append(owners, patina.ReportOwner{
+			Owner:   owner.Owner,
+			Summary: freshnessOptions.SummaryWithExemptions(owner.Repositories, exemptions, now),
+			Stale:   stale,
 		})
 	}
 
-	// Calculate percentages for pie chart
-	var greenPct, yellowPct, redPct float64
-	if summary.Total > 0 {
-		greenPct = float64(summary.Green) / float64(summary.Total) * 100
-		yellowPct = float64(summary.Yellow) / float64(summary.Total) * 100
-		redPct = float64(summary.Red) / float64(summary.Total) * 100
-	}
-
-	data := reportData{
-		Organization: org,
-		GeneratedAt:  now.Format("2006-01-02 15:04:05"),
-		Summary:      summary,
-		Repositories: repos,
-		GreenPct:     greenPct,
-		YellowPct:    yellowPct,
-		RedPct:       redPct,
-	}
-
-	// Generate HTML
-	funcMap := template.FuncMap{
-		"add": func(a, b interface{}) float64 {
-			var af, bf float64
-			switch v := a.(type) {
-			case int:
-				af = float64(v)
-			case float64:
-				af = v
-			}
-			switch v := b.(type) {
-			case int:
-				bf = float64(v)
-			case float64:
-				bf = v
-			}
-			return af + bf
-		},
+	// Build monthly activity histogram, scaling bars to the busiest month
+	buckets := patina.MonthlyActivityHistogram(result.Repositories, now)
+	maxCount := 0
+	for _, b := range buckets {
+		if b.Count > maxCount {
+			maxCount = b.Count
+		}
 	}
-	tmpl, err := template.New("report").Funcs(funcMap).Parse(htmlTemplate)
-	if err != nil {
-		return fmt.Errorf("failed to parse template: %w", err)
+	var histogram []patina.ReportHistogramBar
+	for _, b := range buckets {
+		var height float64
+		if maxCount > 0 {
+			height = float64(b.Count) / float64(maxCount) * 100
+		}
+		histogram = append(histogram, patina.ReportHistogramBar{
+			Label:     b.Label,
+			Count:     b.Count,
+			HeightPct: height,
+		})
 	}
 
-	f, err := os.Create(reportOutput)
+	history, err := scanner.History(org)
 	if err != nil {
-		return fmt.Errorf("failed to create output file: %w", err)
+		slog.Warn("failed to load freshness history", "org", org, "error", err)
+	}
+
+	// Calculate percentages for pie chart
+	greenPct, yellowPct, redPct := patina.CalculatePercentages(summary)
+	var archivedPct, exemptedPct float64
+	if summary.Total > 0 {
+		archivedPct = float64(summary.Archived) / float64(summary.Total) * 100
+		exemptedPct = float64(summary.Exempted) / float64(summary.Total) * 100
 	}
-	defer f.Close()
 
-	if err := tmpl.Execute(f, data); err != nil {
-		return fmt.Errorf("failed to generate report: %w", err)
+	data := patina.ReportInput{
+		Organization:    org,
+		GeneratedAt:     now.Format("2006-01-02 15:04:05"),
+		LastCommit:      reportLastCommit,
+		Protection:      reportProtection,
+		ExcludedForks:   result.ExcludedForks,
+		ExcludedMirrors: result.ExcludedMirrors,
+		NeverPushed:     result.NeverPushed,
+		Ownership:       ownership != nil,
+
+		StaleIssuesDisabled: len(patina.FilterIssuesDisabled(staleRepositories(result.Repositories, exemptions, now))),
+		Labels:              labels,
+		Summary:             summary,
+		Groups:              groups,
+		Owners:              owners,
+		Histogram:           histogram,
+		Trend:               patina.NewReportTrend(history),
+		GreenPct:            greenPct,
+		YellowPct:           yellowPct,
+		RedPct:              redPct,
+		ArchivedPct:         archivedPct,
+		ExemptedPct:         exemptedPct,
+		ColourBlind:         colourPalette == patina.PaletteColourBlind,
 	}
 
-	fmt.Printf("Report generated: %s\n", reportOutput)
-	return nil
+	return data, nil
 }
 
-const htmlTemplate = `<!DOCTYPE html>
+const indexTemplate = `<!DOCTYPE html>
 <html lang="en">
 <head>
     <meta charset="UTF-8">
     <meta name="viewport" content="width=device-width, initial-scale=1.0">
-    <title>Repository Freshness Report - {{.Organization}}</title>
+    <title>Repository Freshness Reports</title>
     <style>
         * {
             box-sizing: border-box;
@@ -183,86 +572,12 @@ const htmlTemplate = `<!DOCTYPE html>
             color: #586069;
             margin-bottom: 2rem;
         }
-        .summary-grid {
-            display: grid;
-            grid-template-columns: repeat(auto-fit, minmax(200px, 1fr));
-            gap: 1rem;
-            margin-bottom: 2rem;
-        }
-        .summary-card {
-            background: white;
-            border-radius: 8px;
-            padding: 1.5rem;
-            box-shadow: 0 1px 3px rgba(0,0,0,0.1);
-            text-align: center;
-        }
-        .summary-card.green { border-left: 4px solid #28a745; }
-        .summary-card.yellow { border-left: 4px solid #ffc107; }
-        .summary-card.red { border-left: 4px solid #dc3545; }
-        .summary-card.total { border-left: 4px solid #6c757d; }
-        .summary-number {
-            font-size: 2.5rem;
-            font-weight: bold;
-        }
-        .summary-card.green .summary-number { color: #28a745; }
-        .summary-card.yellow .summary-number { color: #b8860b; }
-        .summary-card.red .summary-number { color: #dc3545; }
-        .summary-label {
-            color: #586069;
-            font-size: 0.9rem;
-        }
-        .chart-section {
-            background: white;
-            border-radius: 8px;
-            padding: 1.5rem;
-            box-shadow: 0 1px 3px rgba(0,0,0,0.1);
-            margin-bottom: 2rem;
-        }
-        .chart-title {
-            font-size: 1.1rem;
-            margin-bottom: 1rem;
-            color: #24292e;
-        }
-        .pie-chart {
-            width: 200px;
-            height: 200px;
-            border-radius: 50%;
-            margin: 0 auto;
-            background: conic-gradient(
-                #28a745 0deg {{printf "%.1f" .GreenPct}}%,
-                #ffc107 {{printf "%.1f" .GreenPct}}% {{printf "%.1f" (add .GreenPct .YellowPct)}}%,
-                #dc3545 {{printf "%.1f" (add .GreenPct .YellowPct)}}% 100%
-            );
-        }
-        .legend {
-            display: flex;
-            justify-content: center;
-            gap: 2rem;
-            margin-top: 1rem;
-        }
-        .legend-item {
-            display: flex;
-            align-items: center;
-            gap: 0.5rem;
-        }
-        .legend-colour {
-            width: 16px;
-            height: 16px;
-            border-radius: 3px;
-        }
-        .legend-colour.green { background: #28a745; }
-        .legend-colour.yellow { background: #ffc107; }
-        .legend-colour.red { background: #dc3545; }
         .table-section {
             background: white;
             border-radius: 8px;
             box-shadow: 0 1px 3px rgba(0,0,0,0.1);
             overflow: hidden;
         }
-        .table-header {
-            padding: 1rem 1.5rem;
-            border-bottom: 1px solid #e1e4e8;
-        }
         table {
             width: 100%;
             border-collapse: collapse;
@@ -282,25 +597,6 @@ const htmlTemplate = `<!DOCTYPE html>
         tr:hover {
             background: #f6f8fa;
         }
-        .status-badge {
-            display: inline-block;
-            padding: 0.25rem 0.75rem;
-            border-radius: 12px;
-            font-size: 0.85rem;
-            font-weight: 500;
-        }
-        .status-badge.green {
-            background: #dcffe4;
-            color: #22863a;
-        }
-        .status-badge.yellow {
-            background: #fff3cd;
-            color: #856404;
-        }
-        .status-badge.red {
-            background: #ffeef0;
-            color: #cb2431;
-        }
         a {
             color: #0366d6;
             text-decoration: none;
@@ -308,160 +604,39 @@ const htmlTemplate = `<!DOCTYPE html>
         a:hover {
             text-decoration: underline;
         }
-        .footer {
-            text-align: center;
-            margin-top: 2rem;
-            color: #586069;
-            font-size: 0.85rem;
-        }
-        .table-header {
-            display: flex;
-            justify-content: space-between;
-            align-items: center;
-        }
-        .filter-buttons {
-            display: flex;
-            gap: 0.5rem;
-        }
-        .filter-btn {
-            padding: 0.4rem 0.8rem;
-            border: 1px solid #e1e4e8;
-            border-radius: 6px;
-            background: white;
-            cursor: pointer;
-            font-size: 0.85rem;
-            transition: all 0.15s ease;
-        }
-        .filter-btn:hover {
-            background: #f6f8fa;
-        }
-        .filter-btn.active {
-            border-color: #0366d6;
-            background: #f1f8ff;
-            color: #0366d6;
-        }
-        .filter-btn.green.active {
-            border-color: #28a745;
-            background: #dcffe4;
-            color: #22863a;
-        }
-        .filter-btn.yellow.active {
-            border-color: #b8860b;
-            background: #fff3cd;
-            color: #856404;
-        }
-        .filter-btn.red.active {
-            border-color: #dc3545;
-            background: #ffeef0;
-            color: #cb2431;
-        }
-        tr.hidden {
-            display: none;
-        }
     </style>
 </head>
 <body>
     <div class="container">
-        <h1>Repository Freshness Report</h1>
-        <p class="subtitle">Organisation: <strong>{{.Organization}}</strong> | Generated: {{.GeneratedAt}}</p>
-
-        <div class="summary-grid">
-            <div class="summary-card total">
-                <div class="summary-number">{{.Summary.Total}}</div>
-                <div class="summary-label">Total Repositories</div>
-            </div>
-            <div class="summary-card green">
-                <div class="summary-number">{{.Summary.Green}}</div>
-                <div class="summary-label">Active (≤2 months)</div>
-            </div>
-            <div class="summary-card yellow">
-                <div class="summary-number">{{.Summary.Yellow}}</div>
-                <div class="summary-label">Aging (2-6 months)</div>
-            </div>
-            <div class="summary-card red">
-                <div class="summary-number">{{.Summary.Red}}</div>
-                <div class="summary-label">Stale (>6 months)</div>
-            </div>
-        </div>
-
-        {{if gt .Summary.Total 0}}
-        <div class="chart-section">
-            <div class="chart-title">Distribution</div>
-            <div class="pie-chart"></div>
-            <div class="legend">
-                <div class="legend-item">
-                    <div class="legend-colour green"></div>
-                    <span>Active ({{printf "%.1f" .GreenPct}}%)</span>
-                </div>
-                <div class="legend-item">
-                    <div class="legend-colour yellow"></div>
-                    <span>Aging ({{printf "%.1f" .YellowPct}}%)</span>
-                </div>
-                <div class="legend-item">
-                    <div class="legend-colour red"></div>
-                    <span>Stale ({{printf "%.1f" .RedPct}}%)</span>
-                </div>
-            </div>
-        </div>
-        {{end}}
+        <h1>Repository Freshness Reports</h1>
+        <p class="subtitle">Organisations: <strong>{{len .Organizations}}</strong> | Generated: {{.GeneratedAt}}</p>
 
         <div class="table-section">
-            <div class="table-header">
-                <div><strong>All Repositories</strong> (sorted by age, oldest first)</div>
-                <div class="filter-buttons">
-                    <button class="filter-btn active" data-filter="all" onclick="filterTable('all')">All</button>
-                    <button class="filter-btn red" data-filter="red" onclick="filterTable('red')">Red</button>
-                    <button class="filter-btn yellow" data-filter="yellow" onclick="filterTable('yellow')">Yellow</button>
-                    <button class="filter-btn green" data-filter="green" onclick="filterTable('green')">Green</button>
-                </div>
-            </div>
-            <table id="repo-table">
+            <table>
                 <thead>
                     <tr>
-                        <th>#</th>
-                        <th>Repository</th>
-                        <th>Last Updated</th>
-                        <th>Status</th>
+                        <th>Organisation</th>
+                        <th>Total</th>
+                        <th>Green</th>
+                        <th>Yellow</th>
+                        <th>Red</th>
+                        <th>Stale</th>
                     </tr>
                 </thead>
                 <tbody>
-                    {{range $i, $repo := .Repositories}}
-                    <tr data-status="{{$repo.ColourClass}}">
-                        <td>{{add $i 1}}</td>
-                        <td><a href="{{$repo.URL}}" target="_blank">{{$repo.FullName}}</a></td>
-                        <td>{{$repo.Age}}</td>
-                        <td><span class="status-badge {{$repo.ColourClass}}">{{$repo.Freshness}}</span></td>
+                    {{range .Organizations}}
+                    <tr>
+                        <td><a href="{{.File}}">{{.Organization}}</a></td>
+                        <td>{{.Total}}</td>
+                        <td>{{.Green}}</td>
+                        <td>{{.Yellow}}</td>
+                        <td>{{.Red}}</td>
+                        <td>{{printf "%.1f" .StalePercent}}%</td>
                     </tr>
                     {{end}}
                 </tbody>
             </table>
         </div>
-
-        <div class="footer">
-            Generated by <strong>patina</strong>
-        </div>
     </div>
-
-    <script>
-        function filterTable(status) {
-            const rows = document.querySelectorAll('#repo-table tbody tr');
-            const buttons = document.querySelectorAll('.filter-btn');
-
-            buttons.forEach(btn => {
-                btn.classList.remove('active');
-                if (btn.dataset.filter === status) {
-                    btn.classList.add('active');
-                }
-            });
-
-            rows.forEach(row => {
-                if (status === 'all' || row.dataset.status === status) {
-                    row.classList.remove('hidden');
-                } else {
-                    row.classList.add('hidden');
-                }
-            });
-        }
-    </script>
 </body>
 </html>`
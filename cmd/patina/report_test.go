@@ -0,0 +1,129 @@
+package main
+
+import (
+	"html/template"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scottbrown/patina"
+)
+
+func TestIndexTemplate(t *testing.T) {
+	tmpl, err := template.New("index").Parse(indexTemplate)
+	if err != nil {
+		t.Fatalf("failed to parse index template: %v", err)
+	}
+
+	data := indexData{
+		GeneratedAt: "2024-06-15 12:00:00",
+		Organizations: []indexEntry{
+			{
+				OrgComparison: patina.OrgComparison{Organization: "legacy-org", Total: 40, Green: 8, Yellow: 7, Red: 25, StalePercent: 62.5},
+				File:          reportFileName("legacy-org"),
+			},
+		},
+	}
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		t.Fatalf("failed to execute index template: %v", err)
+	}
+
+	for _, want := range []string{`<a href="report-legacy-org.html">legacy-org</a>`, "62.5%"} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("index output missing %q", want)
+		}
+	}
+}
+
+func TestGradientStyle(t *testing.T) {
+	tests := []struct {
+		ageDays int
+		want    template.CSS
+	}{
+		{0, "background: hsl(120, 70%, 88%); color: hsl(120, 80%, 25%);"},
+		{365, "background: hsl(60, 70%, 88%); color: hsl(60, 80%, 25%);"},
+		{730, "background: hsl(0, 70%, 88%); color: hsl(0, 80%, 25%);"},
+		{2000, "background: hsl(0, 70%, 88%); color: hsl(0, 80%, 25%);"},
+	}
+
+	for _, tt := range tests {
+		if got := gradientStyle(tt.ageDays, patina.PaletteDefault); got != tt.want {
+			t.Errorf("gradientStyle(%d) = %q, want %q", tt.ageDays, got, tt.want)
+		}
+	}
+
+	// A 7-month repository is visibly less red than a 13-month one
+	if gradientStyle(210, patina.PaletteDefault) == gradientStyle(395, patina.PaletteDefault) {
+		t.Error("gradientStyle() gave the same colour for 7 and 13 months")
+	}
+}
+
+func TestGradientStyleColourBlind(t *testing.T) {
+	if got, want := gradientStyle(0, patina.PaletteColourBlind), template.CSS("background: hsl(210, 70%, 88%); color: hsl(210, 80%, 25%);"); got != want {
+		t.Errorf("gradientStyle(0) = %q, want %q", got, want)
+	}
+	if got, want := gradientStyle(730, patina.PaletteColourBlind), template.CSS("background: hsl(290, 70%, 88%); color: hsl(290, 80%, 25%);"); got != want {
+		t.Errorf("gradientStyle(730) = %q, want %q", got, want)
+	}
+}
+
+func TestNewRepoDataDate(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	views := patina.NewRepositoryViews([]patina.Repository{{Name: "api", LastUpdated: now.AddDate(-1, -2, 0)}}, now)
+	data := newRepoData(views[0], now)
+
+	if data.Date != "2023-04-15" || data.Age != "1 year, 2 months ago" {
+		t.Errorf("Date, Age = %q, %q, want 2023-04-15, 1 year, 2 months ago", data.Date, data.Age)
+	}
+}
+
+func TestNewRepoDataCountdown(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	views := patina.NewRepositoryViews([]patina.Repository{
+		{Name: "api", LastUpdated: now.AddDate(0, 0, -168)},
+		{Name: "legacy", LastUpdated: now.AddDate(-1, 0, 0)},
+	}, now)
+
+	if got := newRepoData(views[0], now).Countdown; got != "12 days until red" {
+		t.Errorf("Countdown = %q, want 12 days until red", got)
+	}
+	if got := newRepoData(views[1], now).Countdown; got != "" {
+		t.Errorf("Countdown = %q for a red repo, want empty", got)
+	}
+}
+
+func TestNewRepoDataAbandoned(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	views := patina.NewRepositoryViews([]patina.Repository{
+		{Name: "legacy", LastUpdated: now.AddDate(-1, 0, 0), IssuesDisabled: true},
+		{Name: "api", LastUpdated: now.AddDate(0, 0, -1), IssuesDisabled: true},
+	}, now)
+
+	if !newRepoData(views[0], now).Abandoned {
+		t.Error("Abandoned = false for a stale repo with issues disabled, want true")
+	}
+	if newRepoData(views[1], now).Abandoned {
+		t.Error("Abandoned = true for an active repo, want false")
+	}
+}
+
+func TestNewRepoDataDescription(t *testing.T) {
+	long := strings.Repeat("x", descriptionLength+10)
+	data := newRepoData(patina.RepositoryView{Repository: patina.Repository{FullName: "my-org/API", Description: long}}, time.Now())
+
+	if data.Description != long {
+		t.Errorf("Description = %q, want the full description", data.Description)
+	}
+	if got := []rune(data.ShortDesc); len(got) != descriptionLength || got[len(got)-1] != '…' {
+		t.Errorf("ShortDesc = %q, want %d characters ending in an ellipsis", data.ShortDesc, descriptionLength)
+	}
+	if data.Search != "my-org/api "+long {
+		t.Errorf("Search = %q, want lowercased name and description", data.Search)
+	}
+
+	if got := truncate("short", descriptionLength); got != "short" {
+		t.Errorf("truncate() = %q, want short", got)
+	}
+}
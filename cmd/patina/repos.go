@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/scottbrown/patina"
+	"github.com/spf13/cobra"
+)
+
+var (
+	reposRefresh bool
+	reposTop     int
+)
+
+var reposCmd = &cobra.Command{
+	Use:   "repos <owner/name>...",
+	Short: "Scan a list of repositories across organizations",
+	Long: `Repos fetches each named repository individually and shows the same
+freshness summary and most stale list as scan. The repositories may belong
+to different organizations or users, which suits a set of watched
+repositories that no single organization scan covers.
+
+Forks, mirrors, and archived repositories are always included, since they
+were named.
+The list is cached under a key derived from the names, in any order, so
+repeating it is cheap. Use --refresh to force a fresh fetch.
+
+Example:
+  patina repos my-org/api other-org/sdk someone/tool`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runRepos,
+}
+
+func init() {
+	reposCmd.Flags().BoolVarP(&reposRefresh, "refresh", "r", false, "Force refresh from GitHub API")
+	reposCmd.Flags().IntVarP(&reposTop, "top", "n", 0, "Number of most stale repositories to show (0 for all)")
+}
+
+func runRepos(cmd *cobra.Command, args []string) error {
+	w := cmd.OutOrStdout()
+
+	if reposTop < 0 {
+		return fmt.Errorf("invalid top value: %d (must be 0 or greater)", reposTop)
+	}
+
+	scanner, err := newScanner()
+	if err != nil {
+		return fmt.Errorf("failed to initialize scanner: %w", err)
+	}
+
+	status := statusWriter(w)
+	fmt.Fprintf(status, "Scanning %d repositories\n", len(args))
+	if reposRefresh {
+		fmt.Fprintln(status, "(forcing refresh from GitHub API)")
+	}
+	fmt.Fprintln(status)
+
+	result, err := scanner.ScanRepositories(args, patina.ScanOptions{
+		Refresh:         reposRefresh,
+		IncludeForks:    true,
+		IncludeMirrors:  true,
+		IncludeArchived: true,
+		ActivityMetric:  activityMetric,
+		Freshness:       freshnessOptions,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to scan repositories: %w", err)
+	}
+	warnCacheWrite(result)
+
+	if result.FromCache {
+		fmt.Fprintf(status, "Using cached data from %s\n\n", result.FetchedAt.Format("2006-01-02 15:04:05"))
+	}
+
+	printReposResult(w, result, time.Now(), reposTop)
+	return nil
+}
+
+// printReposResult writes the text output for a scanned repository list.
+func printReposResult(w io.Writer, result *patina.ScanResult, now time.Time, top int) {
+	printSummary(w, freshnessOptions.Summary(result.Repositories, now))
+	printStaleSize(w, result.Repositories, nil, now)
+	printNeverPushed(w, result.NeverPushed)
+
+	fmt.Fprintln(w)
+	printTopStale(w, result.Repositories, now, top)
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scottbrown/patina"
+)
+
+func TestPrintReposResult(t *testing.T) {
+	useASCII(t)
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	result := &patina.ScanResult{Repositories: []patina.Repository{
+		{Name: "api", FullName: "org-a/api", LastUpdated: now.AddDate(0, 0, -10)},
+		{Name: "sdk", FullName: "org-b/sdk", LastUpdated: now.AddDate(0, 0, -400)},
+		{Name: "old", FullName: "org-b/old", LastUpdated: now.AddDate(-3, 0, 0), Archived: true},
+	}}
+
+	var b strings.Builder
+	printReposResult(&b, result, now, 0)
+	got := b.String()
+
+	for _, want := range []string{"Total repositories: 3", "Archived", "Top 2 Most Stale Repositories"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("printReposResult() missing %q in:\n%s", want, got)
+		}
+	}
+	if strings.Index(got, "sdk") > strings.Index(got, "api") {
+		t.Errorf("printReposResult() does not list the oldest first:\n%s", got)
+	}
+}
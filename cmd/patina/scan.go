@@ -2,16 +2,36 @@ package main
 
 import (
 	"fmt"
+	"io"
+	"log/slog"
 	"time"
 
 	"github.com/scottbrown/patina"
 	"github.com/spf13/cobra"
 )
 
-var scanRefresh bool
+var (
+	scanRefresh  bool
+	scanTop      int
+	scanDryRun   bool
+	scanForks    bool
+	scanMirrors  bool
+	scanStars    int
+	scanOnly     string
+	scanFormat   string
+	scanOrgs     string
+	scanArchived bool
+	scanExempt   string
+
+	scanNotifyURL     string
+	scanNotifyTimeout time.Duration
+
+	scanDeadline   time.Duration
+	scanOrgTimeout time.Duration
+)
 
 var scanCmd = &cobra.Command{
-	Use:   "scan <organization>",
+	Use:   "scan <organization>...",
 	Short: "Scan a GitHub organization for stale repositories",
 	Long: `Scan retrieves all repositories for a GitHub organization and displays
 a freshness summary showing how many repositories fall into each category:
@@ -20,103 +40,505 @@ a freshness summary showing how many repositories fall into each category:
   🟡 Yellow: Updated between 2-6 months ago (aging)
   🔴 Red:    Not updated in over 6 months (stale)
 
-The scan also lists the top 10 most stale repositories.
+The scan also lists the most stale repositories (10 by default). Use --top
+to change how many are shown, or --top 0 to show them all.
 
 Repository data is cached for 30 days to speed up subsequent commands.
-Use --refresh to force a fresh fetch from GitHub.`,
-	Args: cobra.ExactArgs(1),
+Use --refresh to force a fresh fetch from GitHub.
+
+Use --min-stars to leave out repositories with fewer stars, such as one-off
+experiments, before summarizing.
+
+Use --only-file to scan only the repositories named in a file, one per line.
+Names in the file that are not found in the organization are reported.
+
+Forked repositories are excluded by default. Use --include-forks to count them.
+Mirrors of repositories maintained elsewhere are also excluded; use
+--include-mirrors to count them.
+Archived repositories are also excluded; use --include-archived to count them
+as their own state, separate from green, yellow, and red.
+
+Use --exemptions to read known-stale repositories, such as frozen reference
+implementations, from a CSV file of repository and reason. Listed stale
+repositories are counted as exempted rather than red.
+
+Use --output json or --output yaml to print the summary and most stale
+repositories as structured data; progress messages then go to stderr.
+Use --output summary-json for just the counts, as one line of JSON per
+organization, for health checks and alerting:
+  {"org":"my-org","total":100,"green":60,"yellow":20,"red":20,"fetched_at":"...","from_cache":true}
+Use --output compact for a single line of counts to embed in a shell prompt
+or status bar, such as "🟢60 🟡20 🔴20", with nothing else printed. Several
+organizations are counted together.
+
+Several organizations can be scanned at once, either as arguments or with
+--orgs-file naming a file of organizations, one per line (- reads stdin).
+They are fetched in parallel and reported one after another.
+
+Use --org-timeout to limit the time spent on each organization, and
+--deadline to limit the whole run, so a slow or rate-limited organization
+cannot hold up the others. With either set, the scan reports every
+organization it finished, then lists each organization's status (done,
+timed-out, or errored) and fails if any did not finish.
+
+Use --notify-url to POST each organization's summary as JSON to a URL after
+a successful scan, such as an automation endpoint. The body is the same as
+--output json, plus top-level red and text fields for simple alerting. A
+failed POST is logged as a warning and does not fail the scan.
+
+Use --dry-run to show whether the scan would use the cache or call the
+GitHub API, without doing either.`,
+	Args: cobra.ArbitraryArgs,
 	RunE: runScan,
 }
 
 func init() {
 	scanCmd.Flags().BoolVarP(&scanRefresh, "refresh", "r", false, "Force refresh from GitHub API")
+	scanCmd.Flags().BoolVar(&scanDryRun, "dry-run", false, "Show what would be fetched without calling the API")
+	scanCmd.Flags().BoolVar(&scanForks, "include-forks", false, "Include forked repositories")
+	scanCmd.Flags().BoolVar(&scanMirrors, "include-mirrors", false, "Include mirrors of repositories maintained elsewhere")
+	scanCmd.Flags().BoolVar(&scanArchived, "include-archived", false, "Include archived repositories as their own state")
+	scanCmd.Flags().StringVar(&scanOrgs, "orgs-file", "", "Read organizations from this file, one per line (- for stdin)")
+	scanCmd.Flags().StringVar(&scanExempt, "exemptions", "", "CSV file of repo,reason rows; stale repos listed are counted as exempted, not red")
+	scanCmd.Flags().StringVar(&scanOnly, "only-file", "", "Only include repos named in this file (one per line)")
+	scanCmd.Flags().IntVar(&scanStars, "min-stars", 0, "Ignore repos with fewer than this many stars")
+	scanCmd.Flags().StringVarP(&scanFormat, "output", "o", outputText, "Output format (text, json, yaml, summary-json, compact)")
+	scanCmd.Flags().StringVar(&scanNotifyURL, "notify-url", "", "POST the JSON summary to this URL after scanning")
+	scanCmd.Flags().DurationVar(&scanNotifyTimeout, "notify-timeout", defaultNotifyTimeout, "Timeout for the --notify-url POST")
+	scanCmd.Flags().IntVarP(&scanTop, "top", "n", 10, "Number of most stale repositories to show (0 for all)")
+	scanCmd.Flags().DurationVar(&scanDeadline, "deadline", 0, "Total time allowed for scanning all organizations (0 for no limit)")
+	scanCmd.Flags().DurationVar(&scanOrgTimeout, "org-timeout", 0, "Time allowed for scanning each organization (0 for no limit)")
 }
 
 func runScan(cmd *cobra.Command, args []string) error {
-	org := args[0]
+	w := cmd.OutOrStdout()
+
+	if scanTop < 0 {
+		return fmt.Errorf("invalid top value: %d (must be 0 or greater)", scanTop)
+	}
+	if scanStars < 0 {
+		return fmt.Errorf("invalid min-stars value: %d (must be 0 or greater)", scanStars)
+	}
+	if scanNotifyTimeout <= 0 {
+		return fmt.Errorf("invalid notify-timeout value: %s (must be greater than 0)", scanNotifyTimeout)
+	}
+	if scanDeadline < 0 {
+		return fmt.Errorf("invalid deadline value: %s (must be 0 or greater)", scanDeadline)
+	}
+	if scanOrgTimeout < 0 {
+		return fmt.Errorf("invalid org-timeout value: %s (must be 0 or greater)", scanOrgTimeout)
+	}
 
-	scanner, err := patina.NewScanner()
+	if scanFormat != outputSummaryJSON && scanFormat != outputCompact {
+		if err := validateOutputFormat(scanFormat); err != nil {
+			return fmt.Errorf("invalid output value: %q (must be text, json, yaml, summary-json, or compact)", scanFormat)
+		}
+	}
+
+	orgs, err := resolveOrgs(cmd, args, scanOrgs, 1)
+	if err != nil {
+		return err
+	}
+
+	only, err := readOnlyFile(scanOnly)
+	if err != nil {
+		return err
+	}
+
+	exemptions, err := readExemptionsFile(scanExempt)
+	if err != nil {
+		return err
+	}
+
+	scanner, err := newScanner()
 	if err != nil {
 		return fmt.Errorf("failed to initialize scanner: %w", err)
 	}
 
-	fmt.Printf("Scanning organization: %s\n", org)
+	if scanDryRun {
+		for i, org := range orgs {
+			if i > 0 {
+				fmt.Fprintln(w)
+			}
+			printPlan(w, scanner.Plan(org, patina.ScanOptions{Refresh: scanRefresh}))
+		}
+		return nil
+	}
+
+	if scanFormat == outputCompact {
+		return runCompactScan(w, scanner, orgs, only, exemptions)
+	}
+
+	// Keep stdout clean for structured output
+	status := statusWriter(w)
+	if scanFormat != outputText {
+		status = statusWriter(cmd.ErrOrStderr())
+	}
+	for _, org := range orgs {
+		fmt.Fprintf(status, "Scanning organization: %s\n", org)
+	}
 	if scanRefresh {
-		fmt.Println("(forcing refresh from GitHub API)")
+		fmt.Fprintln(status, "(forcing refresh from GitHub API)")
 	}
-	fmt.Println()
+	fmt.Fprintln(status)
 
-	result, err := scanner.Scan(org, patina.ScanOptions{Refresh: scanRefresh})
-	if err != nil {
-		return fmt.Errorf("failed to scan organization: %w", err)
+	now := time.Now()
+	multi := len(orgs) > 1
+
+	// With a time limit, report the organizations that finished rather than
+	// failing on the first that did not
+	partial := scanDeadline > 0 || scanOrgTimeout > 0
+
+	scans := scanner.ScanAll(orgs, patina.ScanOptions{
+		Refresh:         scanRefresh,
+		IncludeForks:    scanForks,
+		IncludeMirrors:  scanMirrors,
+		IncludeArchived: scanArchived,
+		ActivityMetric:  activityMetric,
+		Freshness:       freshnessOptions,
+		OrgTimeout:      scanOrgTimeout,
+		Deadline:        scanDeadline,
+	})
+
+	var outputs []scanOutput
+	printed := 0
+	for _, scan := range scans {
+		if scan.Err != nil {
+			if partial {
+				continue
+			}
+			return fmt.Errorf("failed to scan organization %s: %w", scan.Organization, scan.Err)
+		}
+		result := scan.Result
+		warnCacheWrite(result)
+		printScanSummary(cmd.ErrOrStderr(), result)
+		if scanNotifyURL != "" {
+			notify(newScanOutput(io.Discard, result, only, exemptions, now))
+		}
+
+		if scanFormat != outputText {
+			outputs = append(outputs, newScanOutput(status, result, only, exemptions, now))
+			continue
+		}
+
+		if multi {
+			if printed > 0 {
+				fmt.Fprintln(w)
+			}
+			fmt.Fprintf(w, "Organization: %s\n\n", scan.Organization)
+		}
+		printScanResult(w, status, result, only, exemptions, now)
+		printed++
+	}
+
+	if err := writeScanOutputs(w, outputs, multi); err != nil {
+		return err
+	}
+
+	if partial {
+		if failed := printScanStatuses(status, scans); failed > 0 {
+			return fmt.Errorf("failed to scan %d of %d organizations", failed, len(scans))
+		}
 	}
+	return nil
+}
 
+// runCompactScan prints the combined counts of the organizations on one line,
+// with no progress messages or notes.
+func runCompactScan(w io.Writer, scanner *patina.Scanner, orgs []string, only []string, exemptions patina.Exemptions) error {
 	now := time.Now()
+	scans := scanner.ScanAll(orgs, patina.ScanOptions{
+		Refresh:         scanRefresh,
+		IncludeForks:    scanForks,
+		IncludeMirrors:  scanMirrors,
+		IncludeArchived: scanArchived,
+		ActivityMetric:  activityMetric,
+		Freshness:       freshnessOptions,
+		OrgTimeout:      scanOrgTimeout,
+		Deadline:        scanDeadline,
+	})
+
+	var summaries []patina.FreshnessSummary
+	for _, scan := range scans {
+		if scan.Err != nil {
+			return fmt.Errorf("failed to scan organization %s: %w", scan.Organization, scan.Err)
+		}
+		repos, _ := scanRepositories(io.Discard, scan.Result, only)
+		summaries = append(summaries, freshnessOptions.SummaryWithExemptions(repos, exemptions, now))
+	}
+
+	fmt.Fprintln(w, compactSummary(patina.MergeSummaries(summaries...)))
+	return nil
+}
+
+// compactSummary formats freshness counts as indicators followed by counts,
+// such as "🟢60 🟡20 🔴20". Archived and exempted repositories are shown only
+// if counted.
+func compactSummary(summary patina.FreshnessSummary) string {
+	line := fmt.Sprintf("%s%d %s%d %s%d",
+		symbol(patina.FreshnessGreen), summary.Green,
+		symbol(patina.FreshnessYellow), summary.Yellow,
+		symbol(patina.FreshnessRed), summary.Red)
+	if summary.Archived > 0 {
+		line += fmt.Sprintf(" %s%d", symbol(patina.FreshnessArchived), summary.Archived)
+	}
+	if summary.Exempted > 0 {
+		line += fmt.Sprintf(" %d exempt", summary.Exempted)
+	}
+	return line
+}
+
+// writeScanOutputs writes the structured output of the scanned organizations,
+// if a structured format was chosen and any organization finished.
+func writeScanOutputs(w io.Writer, outputs []scanOutput, multi bool) error {
+	switch {
+	case scanFormat == outputText, len(outputs) == 0:
+		return nil
+	case scanFormat == outputSummaryJSON:
+		return writeSummaryJSON(w, outputs)
+	case !multi && len(outputs) == 1:
+		return writeStructured(w, scanFormat, outputs[0])
+	}
+	return writeStructured(w, scanFormat, outputs)
+}
 
+// printScanStatuses writes how scanning each organization ended and returns
+// the number that did not finish.
+func printScanStatuses(w io.Writer, scans []patina.OrgScanResult) int {
+	maxNameLen := 0
+	for _, scan := range scans {
+		if len(scan.Organization) > maxNameLen {
+			maxNameLen = len(scan.Organization)
+		}
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Organization status:")
+	failed := 0
+	for _, scan := range scans {
+		if scan.Err != nil {
+			failed++
+			fmt.Fprintf(w, "  %-*s  %s: %v\n", maxNameLen, scan.Organization, scan.Status, scan.Err)
+			continue
+		}
+		fmt.Fprintf(w, "  %-*s  %s\n", maxNameLen, scan.Organization, scan.Status)
+	}
+	return failed
+}
+
+// notify posts a scan's summary to --notify-url, logging rather than
+// returning any failure so the scan itself still succeeds.
+func notify(output scanOutput) {
+	if err := sendNotification(scanNotifyURL, scanNotifyTimeout, newNotification(output)); err != nil {
+		slog.Warn("failed to send scan notification", "org", output.Organization, "url", scanNotifyURL, "error", err)
+	}
+}
+
+// scanRepositories applies the only-file and star filters to a scan result,
+// returning the remaining repositories and how many the star filter dropped.
+func scanRepositories(status io.Writer, result *patina.ScanResult, only []string) ([]patina.Repository, int) {
 	if result.FromCache {
-		fmt.Printf("Using cached data from %s\n\n", result.FetchedAt.Format("2006-01-02 15:04:05"))
+		fmt.Fprintf(status, "Using cached data from %s\n\n", result.FetchedAt.Format("2006-01-02 15:04:05"))
+	}
+
+	repos := filterOnly(status, result.Organization, result.Repositories, only)
+
+	var excludedByStars int
+	if scanStars > 0 {
+		before := len(repos)
+		repos = patina.FilterByMinStars(repos, scanStars)
+		excludedByStars = before - len(repos)
+	}
+
+	return repos, excludedByStars
+}
+
+// newScanOutput builds the structured scan output for one organization.
+func newScanOutput(status io.Writer, result *patina.ScanResult, only []string, exemptions patina.Exemptions, now time.Time) scanOutput {
+	repos, _ := scanRepositories(status, result, only)
+	summary := freshnessOptions.SummaryWithExemptions(repos, exemptions, now)
+	topStale := freshnessOptions.RepositoryViews(patina.GetTopStale(repos, scanTop), now)
+	exemptions.Annotate(topStale)
+
+	return scanOutput{
+		Organization:        result.Organization,
+		FetchedAt:           result.FetchedAt,
+		FromCache:           result.FromCache,
+		Summary:             summary,
+		Percentages:         newPercentagesOutput(summary),
+		Unlicensed:          len(patina.FilterNoLicense(repos)),
+		StaleIssuesDisabled: len(patina.FilterIssuesDisabled(staleRepositories(repos, exemptions, now))),
+		StaleSizeKB:         patina.TotalSizeKB(staleRepositories(repos, exemptions, now)),
+		ExcludedForks:       result.ExcludedForks,
+		ExcludedMirrors:     result.ExcludedMirrors,
+		NeverPushed:         result.NeverPushed,
+		Renamed:             result.Renamed,
+		Changes:             result.Changes,
+		TopStale:            topStale,
 	}
+}
 
-	// Calculate and display summary
-	summary := patina.CalculateSummary(result.Repositories, now)
-	printSummary(summary)
+// printScanResult writes the text scan output for one organization.
+func printScanResult(w, status io.Writer, result *patina.ScanResult, only []string, exemptions patina.Exemptions, now time.Time) {
+	repos, excludedByStars := scanRepositories(status, result, only)
+
+	// Display summary
+	printSummary(w, freshnessOptions.SummaryWithExemptions(repos, exemptions, now))
+
+	if unlicensed := len(patina.FilterNoLicense(repos)); unlicensed > 0 {
+		fmt.Fprintf(w, "\n%d repositories have no license\n", unlicensed)
+	}
+	printIssuesDisabled(w, repos, exemptions, now)
+	printStaleSize(w, repos, exemptions, now)
+	printNeverPushed(w, result.NeverPushed)
+
+	printExcludedForks(status, result.ExcludedForks)
+	printExcludedMirrors(status, result.ExcludedMirrors)
+	printExcludedByStars(status, excludedByStars, scanStars)
+	printRenames(status, result.Renamed)
+	printChanges(status, result.Changes)
 
 	// Display top stale repositories
-	fmt.Println()
-	printTopStale(result.Repositories, now, 10)
+	fmt.Fprintln(w)
+	printTopStale(w, repos, now, scanTop)
+}
 
-	return nil
+// staleRepositories returns the red repositories the exemptions file does not list.
+func staleRepositories(repos []patina.Repository, exemptions patina.Exemptions, now time.Time) []patina.Repository {
+	return exemptions.Exclude(freshnessOptions.FilterByFreshness(repos, patina.FreshnessRed, now))
+}
+
+// printIssuesDisabled notes how many unexempted stale repositories have issues
+// disabled, which often means they were meant to be archived.
+func printIssuesDisabled(w io.Writer, repos []patina.Repository, exemptions patina.Exemptions, now time.Time) {
+	if n := len(patina.FilterIssuesDisabled(staleRepositories(repos, exemptions, now))); n > 0 {
+		fmt.Fprintf(w, "\n%d stale repositories have issues disabled\n", n)
+	}
+}
+
+// printExcludedForks notes how many forks were left out of the results.
+func printExcludedForks(w io.Writer, n int) {
+	if n > 0 {
+		fmt.Fprintf(w, "\n%d forks excluded (use --include-forks to include them)\n", n)
+	}
+}
+
+// printExcludedMirrors notes how many mirrors were left out of the results.
+func printExcludedMirrors(w io.Writer, n int) {
+	if n > 0 {
+		fmt.Fprintf(w, "\n%d mirrors excluded (use --include-mirrors to include them)\n", n)
+	}
+}
+
+// printStaleSize notes the combined disk size of the unexempted red repositories.
+func printStaleSize(w io.Writer, repos []patina.Repository, exemptions patina.Exemptions, now time.Time) {
+	if kb := patina.TotalSizeKB(staleRepositories(repos, exemptions, now)); kb > 0 {
+		fmt.Fprintf(w, "\nStale repos total %s\n", patina.FormatSize(kb))
+	}
+}
+
+// printNeverPushed notes how many empty repositories were left out of the age math.
+func printNeverPushed(w io.Writer, n int) {
+	if n > 0 {
+		fmt.Fprintf(w, "\n%d empty repositories (never pushed)\n", n)
+	}
+}
+
+// printExcludedByStars notes how many repositories fell below the star threshold.
+func printExcludedByStars(w io.Writer, n, minStars int) {
+	if n > 0 {
+		fmt.Fprintf(w, "\n%d repositories with fewer than %d stars excluded\n", n, minStars)
+	}
+}
+
+// printRenames lists repositories renamed since the previous fetch.
+func printRenames(w io.Writer, renames []patina.Rename) {
+	if len(renames) == 0 {
+		return
+	}
+
+	fmt.Fprintln(w, "\nRenamed since the last fetch:")
+	for _, r := range renames {
+		fmt.Fprintf(w, "  %s → %s (renamed)\n", r.OldName, r.NewName)
+	}
+}
+
+// printChanges summarizes how the repositories changed since the last fetch,
+// when a refresh replaced a cached one.
+func printChanges(w io.Writer, changes *patina.Changes) {
+	if changes == nil {
+		return
+	}
+	fmt.Fprintf(w, "\n%d new, %d removed, %d repos changed freshness since last scan.\n",
+		changes.Added, changes.Removed, changes.FreshnessChanged)
+}
+
+func printPlan(w io.Writer, plan patina.ScanPlan) {
+	fmt.Fprintf(w, "Dry run for organization: %s\n\n", plan.Organization)
+
+	switch plan.CacheState {
+	case patina.CacheStateMissing:
+		fmt.Fprintln(w, "Cache:   missing")
+	case patina.CacheStateCorrupt:
+		fmt.Fprintln(w, "Cache:   corrupt (would be removed)")
+	default:
+		fmt.Fprintf(w, "Cache:   %s (fetched %s)\n", plan.CacheState, plan.FetchedAt.Format("2006-01-02 15:04:05"))
+	}
+	fmt.Fprintf(w, "Refresh: %t\n", plan.Refresh)
+
+	if plan.WillFetch {
+		fmt.Fprintln(w, "Action:  would fetch from GitHub API and update cache")
+	} else {
+		fmt.Fprintln(w, "Action:  would use cached data")
+	}
 }
 
-func printSummary(summary patina.FreshnessSummary) {
-	fmt.Println("Repository Freshness Summary")
-	fmt.Println("============================")
-	fmt.Println()
-	fmt.Printf("Total repositories: %d\n\n", summary.Total)
+// printSummary writes the freshness counts, each with its share of the total.
+func printSummary(w io.Writer, summary patina.FreshnessSummary) {
+	fmt.Fprintln(w, "Repository Freshness Summary")
+	fmt.Fprintln(w, "============================")
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "Total repositories: %d\n\n", summary.Total)
 
 	green := patina.FreshnessGreen
 	yellow := patina.FreshnessYellow
 	red := patina.FreshnessRed
+	greenPct, yellowPct, redPct := patina.CalculatePercentages(summary)
 
-	fmt.Printf("%s %sGreen%s  (≤2 months):  %d\n",
-		green.Emoji(), green.Colour(), patina.ColourReset(), summary.Green)
-	fmt.Printf("%s %sYellow%s (2-6 months): %d\n",
-		yellow.Emoji(), yellow.Colour(), patina.ColourReset(), summary.Yellow)
-	fmt.Printf("%s %sRed%s    (>6 months):  %d\n",
-		red.Emoji(), red.Colour(), patina.ColourReset(), summary.Red)
+	fmt.Fprintf(w, "%s %sGreen%s  (≤2 months):  %d (%.1f%%)\n",
+		symbol(green), colour(green), patina.ColourReset(), summary.Green, greenPct)
+	fmt.Fprintf(w, "%s %sYellow%s (2-6 months): %d (%.1f%%)\n",
+		symbol(yellow), colour(yellow), patina.ColourReset(), summary.Yellow, yellowPct)
+	fmt.Fprintf(w, "%s %sRed%s    (>6 months):  %d (%.1f%%)\n",
+		symbol(red), colour(red), patina.ColourReset(), summary.Red, redPct)
+
+	if summary.Archived > 0 {
+		archived := patina.FreshnessArchived
+		fmt.Fprintf(w, "%s %sArchived%s:            %d (%.1f%%)\n",
+			symbol(archived), colour(archived), patina.ColourReset(), summary.Archived,
+			float64(summary.Archived)/float64(summary.Total)*100)
+	}
+	if summary.Exempted > 0 {
+		fmt.Fprintf(w, "    Exempted:            %d (%.1f%%)\n", summary.Exempted,
+			float64(summary.Exempted)/float64(summary.Total)*100)
+	}
 }
 
-func printTopStale(repos []patina.Repository, now time.Time, n int) {
+func printTopStale(w io.Writer, repos []patina.Repository, now time.Time, n int) {
 	topStale := patina.GetTopStale(repos, n)
 
 	if len(topStale) == 0 {
-		fmt.Println("No repositories found.")
+		fmt.Fprintln(w, "No repositories found.")
 		return
 	}
 
-	fmt.Printf("Top %d Most Stale Repositories\n", len(topStale))
-	fmt.Println("==============================")
-	fmt.Println()
-
-	maxNameLen := 0
-	for _, repo := range topStale {
-		if len(repo.Name) > maxNameLen {
-			maxNameLen = len(repo.Name)
-		}
-	}
-
-	for i, repo := range topStale {
-		freshness := patina.CalculateFreshness(repo.LastUpdated, now)
-		age := patina.Age(repo.LastUpdated, now)
+	fmt.Fprintf(w, "Top %d Most Stale Repositories\n", len(topStale))
+	fmt.Fprintln(w, "==============================")
+	fmt.Fprintln(w)
 
-		fmt.Printf("%2d. %s %s%-*s%s  %s\n",
-			i+1,
-			freshness.Emoji(),
-			freshness.Colour(),
-			maxNameLen,
-			repo.Name,
-			patina.ColourReset(),
-			age,
-		)
+	t := &table{borders: borders}
+	for i, view := range freshnessOptions.RepositoryViews(topStale, now) {
+		t.addRow(fmt.Sprintf("%2d. %s", i+1, freshnessLabel(view)), view.Age)
 	}
+	t.render(w)
 }
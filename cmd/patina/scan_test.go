@@ -0,0 +1,291 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scottbrown/patina"
+)
+
+// useASCII renders freshness indicators as ASCII for the duration of a test.
+func useASCII(t *testing.T) {
+	t.Helper()
+	prevEmoji, prevNoEmoji := emoji, noEmoji
+	emoji, noEmoji = false, true
+	t.Cleanup(func() { emoji, noEmoji = prevEmoji, prevNoEmoji })
+}
+
+const (
+	green  = "\033[32m"
+	yellow = "\033[33m"
+	red    = "\033[31m"
+	reset  = "\033[0m"
+)
+
+func TestPrintSummary(t *testing.T) {
+	useASCII(t)
+
+	var b strings.Builder
+	printSummary(&b, patina.FreshnessSummary{Green: 5, Yellow: 3, Red: 2, Total: 10})
+
+	want := "Repository Freshness Summary\n" +
+		"============================\n" +
+		"\n" +
+		"Total repositories: 10\n\n" +
+		"[G] " + green + "Green" + reset + "  (≤2 months):  5 (50.0%)\n" +
+		"[Y] " + yellow + "Yellow" + reset + " (2-6 months): 3 (30.0%)\n" +
+		"[R] " + red + "Red" + reset + "    (>6 months):  2 (20.0%)\n"
+	if b.String() != want {
+		t.Errorf("printSummary() =\n%q\nwant\n%q", b.String(), want)
+	}
+
+	b.Reset()
+	printSummary(&b, patina.FreshnessSummary{Green: 5, Yellow: 3, Red: 2, Archived: 4, Total: 14})
+	archived := "[A] \033[90mArchived" + reset + ":            4 (28.6%)\n"
+	if !strings.HasSuffix(b.String(), "(>6 months):  2 (14.3%)\n"+archived) {
+		t.Errorf("printSummary() with archived =\n%q\nwant suffix\n%q", b.String(), archived)
+	}
+
+	// Exempted stale repositories are shown apart from red
+	b.Reset()
+	printSummary(&b, patina.FreshnessSummary{Green: 5, Yellow: 3, Red: 1, Exempted: 1, Total: 10})
+	exempted := "    Exempted:            1 (10.0%)\n"
+	if !strings.HasSuffix(b.String(), "(>6 months):  1 (10.0%)\n"+exempted) {
+		t.Errorf("printSummary() with exempted =\n%q\nwant suffix\n%q", b.String(), exempted)
+	}
+
+	// An empty organization shows zero percentages rather than NaN
+	b.Reset()
+	printSummary(&b, patina.FreshnessSummary{})
+	if strings.Contains(b.String(), "NaN") || !strings.Contains(b.String(), "(>6 months):  0 (0.0%)") {
+		t.Errorf("printSummary() with no repositories =\n%q", b.String())
+	}
+}
+
+func TestPrintTopStale(t *testing.T) {
+	useASCII(t)
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+
+	repos := []patina.Repository{
+		{Name: "fresh", LastUpdated: now.AddDate(0, 0, -3)},
+		{Name: "ancient-api", LastUpdated: now.AddDate(-2, 0, 0)},
+		{Name: "aging", LastUpdated: now.AddDate(0, -3, 0)},
+	}
+
+	tests := []struct {
+		name  string
+		repos []patina.Repository
+		n     int
+		want  string
+	}{
+		{
+			name:  "top two",
+			repos: repos,
+			n:     2,
+			want: "Top 2 Most Stale Repositories\n" +
+				"==============================\n" +
+				"\n" +
+				" 1. [R] " + red + "ancient-api" + reset + "  2 years ago\n" +
+				" 2. [Y] " + yellow + "aging" + reset + "        3 months ago\n",
+		},
+		{
+			name: "no repositories",
+			n:    10,
+			want: "No repositories found.\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b strings.Builder
+			printTopStale(&b, append([]patina.Repository(nil), tt.repos...), now, tt.n)
+
+			if b.String() != tt.want {
+				t.Errorf("printTopStale() =\n%q\nwant\n%q", b.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestPrintPlan(t *testing.T) {
+	fetchedAt := time.Date(2024, 6, 1, 9, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		plan patina.ScanPlan
+		want string
+	}{
+		{
+			name: "valid cache",
+			plan: patina.ScanPlan{Organization: "my-org", CacheState: patina.CacheStateValid, FetchedAt: fetchedAt},
+			want: "Dry run for organization: my-org\n\n" +
+				"Cache:   valid (fetched 2024-06-01 09:30:00)\n" +
+				"Refresh: false\n" +
+				"Action:  would use cached data\n",
+		},
+		{
+			name: "missing cache",
+			plan: patina.ScanPlan{Organization: "my-org", CacheState: patina.CacheStateMissing, WillFetch: true},
+			want: "Dry run for organization: my-org\n\n" +
+				"Cache:   missing\n" +
+				"Refresh: false\n" +
+				"Action:  would fetch from GitHub API and update cache\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b strings.Builder
+			printPlan(&b, tt.plan)
+
+			if b.String() != tt.want {
+				t.Errorf("printPlan() =\n%q\nwant\n%q", b.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestPrintScanStatuses(t *testing.T) {
+	scans := []patina.OrgScanResult{
+		{Organization: "org-a", Status: patina.ScanDone, Result: &patina.ScanResult{}},
+		{Organization: "slow", Status: patina.ScanTimedOut, Err: fmt.Errorf("%w after 30s", patina.ErrScanTimeout)},
+		{Organization: "gone", Status: patina.ScanErrored, Err: errors.New("not found")},
+	}
+
+	var b strings.Builder
+	if failed := printScanStatuses(&b, scans); failed != 2 {
+		t.Errorf("printScanStatuses() = %d, want 2", failed)
+	}
+
+	want := "\nOrganization status:\n" +
+		"  org-a  done\n" +
+		"  slow   timed-out: scan timed out after 30s\n" +
+		"  gone   errored: not found\n"
+	if b.String() != want {
+		t.Errorf("printScanStatuses() =\n%q\nwant\n%q", b.String(), want)
+	}
+}
+
+func TestPrintExcludedNotes(t *testing.T) {
+	var b strings.Builder
+	printExcludedForks(&b, 0)
+	printNeverPushed(&b, 0)
+	printExcludedByStars(&b, 0, 5)
+	if b.String() != "" {
+		t.Errorf("notes for zero exclusions = %q, want empty", b.String())
+	}
+
+	printExcludedForks(&b, 3)
+	printNeverPushed(&b, 2)
+	printExcludedByStars(&b, 4, 5)
+	want := "\n3 forks excluded (use --include-forks to include them)\n" +
+		"\n2 empty repositories (never pushed)\n" +
+		"\n4 repositories with fewer than 5 stars excluded\n"
+	if b.String() != want {
+		t.Errorf("notes =\n%q\nwant\n%q", b.String(), want)
+	}
+}
+
+func TestPrintStaleSize(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	repos := []patina.Repository{
+		{Name: "active", LastUpdated: now.AddDate(0, 0, -1), SizeKB: 4096},
+		{Name: "stale1", LastUpdated: now.AddDate(-1, 0, 0), SizeKB: 10 * 1024 * 1024},
+		{Name: "stale2", LastUpdated: now.AddDate(-2, 0, 0), SizeKB: 2360 * 1024},
+	}
+
+	var b strings.Builder
+	printStaleSize(&b, repos[:1], nil, now)
+	if b.String() != "" {
+		t.Errorf("note with no stale repos = %q, want empty", b.String())
+	}
+
+	printStaleSize(&b, repos, nil, now)
+	if want := "\nStale repos total 12.3 GB\n"; b.String() != want {
+		t.Errorf("note = %q, want %q", b.String(), want)
+	}
+
+	// Exempt repositories are not counted as stale
+	b.Reset()
+	printStaleSize(&b, repos, patina.Exemptions{"stale1": "reference"}, now)
+	if want := "\nStale repos total 2.3 GB\n"; b.String() != want {
+		t.Errorf("note with exemptions = %q, want %q", b.String(), want)
+	}
+}
+
+func TestPrintIssuesDisabled(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	repos := []patina.Repository{
+		{Name: "active", LastUpdated: now.AddDate(0, 0, -1), IssuesDisabled: true},
+		{Name: "legacy", LastUpdated: now.AddDate(-1, 0, 0), IssuesDisabled: true},
+		{Name: "old", LastUpdated: now.AddDate(-2, 0, 0)},
+	}
+
+	var b strings.Builder
+	printIssuesDisabled(&b, repos, nil, now)
+	if want := "\n1 stale repositories have issues disabled\n"; b.String() != want {
+		t.Errorf("note = %q, want %q", b.String(), want)
+	}
+
+	b.Reset()
+	printIssuesDisabled(&b, repos, patina.Exemptions{"legacy": "frozen"}, now)
+	if b.String() != "" {
+		t.Errorf("note with the repo exempt = %q, want empty", b.String())
+	}
+}
+
+func TestPrintChanges(t *testing.T) {
+	var b strings.Builder
+	printChanges(&b, nil)
+	if b.String() != "" {
+		t.Errorf("note without a previous fetch = %q, want empty", b.String())
+	}
+
+	printChanges(&b, &patina.Changes{Added: 3, Removed: 1, FreshnessChanged: 12})
+	if want := "\n3 new, 1 removed, 12 repos changed freshness since last scan.\n"; b.String() != want {
+		t.Errorf("note = %q, want %q", b.String(), want)
+	}
+}
+
+func TestPrintRenames(t *testing.T) {
+	var b strings.Builder
+	printRenames(&b, nil)
+	if b.String() != "" {
+		t.Errorf("printRenames(nil) = %q, want empty", b.String())
+	}
+
+	printRenames(&b, []patina.Rename{{ID: 1, OldName: "repo-old", NewName: "repo-new"}})
+	want := "\nRenamed since the last fetch:\n  repo-old → repo-new (renamed)\n"
+	if b.String() != want {
+		t.Errorf("printRenames() = %q, want %q", b.String(), want)
+	}
+}
+
+func TestCompactSummary(t *testing.T) {
+	summary := patina.FreshnessSummary{Green: 60, Yellow: 20, Red: 20, Total: 100}
+
+	prevEmoji, prevNoEmoji := emoji, noEmoji
+	emoji, noEmoji = true, false
+	t.Cleanup(func() { emoji, noEmoji = prevEmoji, prevNoEmoji })
+	if got, want := compactSummary(summary), "🟢60 🟡20 🔴20"; got != want {
+		t.Errorf("compactSummary() = %q, want %q", got, want)
+	}
+
+	useASCII(t)
+	if got, want := compactSummary(summary), "[G]60 [Y]20 [R]20"; got != want {
+		t.Errorf("compactSummary() with --no-emoji = %q, want %q", got, want)
+	}
+
+	summary.Archived = 3
+	if got, want := compactSummary(summary), "[G]60 [Y]20 [R]20 [A]3"; got != want {
+		t.Errorf("compactSummary() with archived = %q, want %q", got, want)
+	}
+
+	summary.Exempted = 2
+	if got, want := compactSummary(summary), "[G]60 [Y]20 [R]20 [A]3 2 exempt"; got != want {
+		t.Errorf("compactSummary() with exempted = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/scottbrown/patina"
+	"github.com/spf13/cobra"
+)
+
+// schemaDialect is the JSON Schema version the generated schemas follow.
+const schemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// schemaOutput is a structured output the schema command describes.
+type schemaOutput struct {
+	name  string
+	title string
+
+	// records are the types of the document, or of each line for line-delimited
+	// outputs; a document matching any one of them is valid
+	records []reflect.Type
+
+	// multi is set when several organizations are written as an array
+	multi bool
+}
+
+var schemaOutputs = []schemaOutput{
+	{name: "scan", title: "patina scan --output json", records: []reflect.Type{reflect.TypeFor[scanOutput]()}, multi: true},
+	{name: "summary", title: "patina scan --output summary-json", records: []reflect.Type{reflect.TypeFor[summaryOutput]()}},
+	{name: "list", title: "patina list --output json", records: []reflect.Type{reflect.TypeFor[listOutput]()}},
+	{name: "ndjson", title: "patina report --format ndjson", records: []reflect.Type{reflect.TypeFor[ndjsonRepository](), reflect.TypeFor[ndjsonSummary]()}},
+}
+
+// schemaEnums lists the values of string types with a fixed set of values.
+var schemaEnums = map[reflect.Type][]string{
+	reflect.TypeFor[patina.Freshness](): {
+		string(patina.FreshnessGreen), string(patina.FreshnessYellow), string(patina.FreshnessRed), string(patina.FreshnessArchived),
+	},
+	reflect.TypeFor[patina.ProtectionStatus](): {
+		string(patina.ProtectionProtected), string(patina.ProtectionUnprotected), string(patina.ProtectionUnknown),
+	},
+}
+
+var schemaCmd = &cobra.Command{
+	Use:   "schema <output>",
+	Short: "Print the JSON Schema of a structured output",
+	Long: `Schema prints the JSON Schema of one of patina's structured outputs, so
+consumers can validate what they read or generate types from it:
+
+  scan     scan --output json (and yaml)
+  summary  scan --output summary-json, one line per organization
+  list     list --output json (and yaml)
+  ndjson   report --format ndjson, one repository or summary record per line
+
+Fields are only added between releases; the schemas of each release are also
+in the schema directory of the source repository.
+
+Example:
+  patina schema scan > scan.schema.json`,
+	Args:      cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+	ValidArgs: schemaNames(),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return writeSchema(cmd.OutOrStdout(), args[0])
+	},
+}
+
+// schemaNames returns the outputs the schema command describes.
+func schemaNames() []string {
+	names := make([]string, 0, len(schemaOutputs))
+	for _, output := range schemaOutputs {
+		names = append(names, output.name)
+	}
+	return names
+}
+
+// writeSchema writes the indented JSON Schema of the named output.
+func writeSchema(w io.Writer, name string) error {
+	schema, err := outputSchema(name)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode schema: %w", err)
+	}
+	_, err = fmt.Fprintf(w, "%s\n", data)
+	return err
+}
+
+// outputSchema generates the JSON Schema of the named output from its types.
+func outputSchema(name string) (map[string]any, error) {
+	for _, output := range schemaOutputs {
+		if output.name != name {
+			continue
+		}
+
+		var alternatives []any
+		for _, record := range output.records {
+			alternatives = append(alternatives, typeSchema(record))
+		}
+		if output.multi {
+			alternatives = append(alternatives, map[string]any{"type": "array", "items": alternatives[0]})
+		}
+
+		schema := map[string]any{"$schema": schemaDialect, "title": output.title}
+		if len(alternatives) == 1 {
+			for key, value := range alternatives[0].(map[string]any) {
+				schema[key] = value
+			}
+		} else {
+			schema["oneOf"] = alternatives
+		}
+		return schema, nil
+	}
+	return nil, fmt.Errorf("unknown output: %q (must be %s)", name, strings.Join(schemaNames(), ", "))
+}
+
+// typeSchema describes how encoding/json writes a value of type t.
+func typeSchema(t reflect.Type) map[string]any {
+	if values, ok := schemaEnums[t]; ok {
+		return map[string]any{"type": "string", "enum": values}
+	}
+	if t == reflect.TypeFor[time.Time]() {
+		return map[string]any{"type": "string", "format": "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return typeSchema(t.Elem())
+	case reflect.Struct:
+		properties := make(map[string]any)
+		var required []string
+		addFieldSchemas(t, properties, &required)
+		schema := map[string]any{"type": "object", "properties": properties, "additionalProperties": false}
+		if len(required) > 0 {
+			schema["required"] = required
+		}
+		return schema
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": typeSchema(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": typeSchema(t.Elem())}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	}
+	return map[string]any{}
+}
+
+// addFieldSchemas adds the schema of each field of struct type t, flattening
+// embedded structs as encoding/json does. Fields that are always written are
+// required.
+func addFieldSchemas(t reflect.Type, properties map[string]any, required *[]string) {
+	for i := range t.NumField() {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			addFieldSchemas(field.Type, properties, required)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		properties[name] = typeSchema(field.Type)
+		omitted := false
+		for _, option := range strings.Split(options, ",") {
+			omitted = omitted || option == "omitempty" || option == "omitzero"
+		}
+		if !omitted {
+			*required = append(*required, name)
+		}
+	}
+}
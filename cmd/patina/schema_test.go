@@ -0,0 +1,249 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scottbrown/patina"
+)
+
+func TestShippedSchemas(t *testing.T) {
+	for _, name := range schemaNames() {
+		var b strings.Builder
+		if err := writeSchema(&b, name); err != nil {
+			t.Fatalf("writeSchema(%q) error = %v", name, err)
+		}
+
+		shipped, err := os.ReadFile(filepath.Join("..", "..", "schema", name+".schema.json"))
+		if err != nil {
+			t.Fatalf("failed to read shipped schema: %v", err)
+		}
+		if string(shipped) != b.String() {
+			t.Errorf("schema/%s.schema.json is out of date with the output types; regenerate it with \"task schema\"", name)
+		}
+	}
+}
+
+func TestWriteSchemaUnknownOutput(t *testing.T) {
+	var b strings.Builder
+	if err := writeSchema(&b, "xml"); err == nil {
+		t.Error("writeSchema(\"xml\") error = nil, want error")
+	}
+}
+
+// schemaSampleRepository returns a repository with every field set, so the
+// samples validated against the shipped schemas carry every field name.
+func schemaSampleRepository(now time.Time) patina.Repository {
+	return patina.Repository{
+		ID:               42,
+		Name:             "api",
+		FullName:         "my-org/api",
+		LastUpdated:      now.AddDate(-1, 0, 0),
+		PushedAt:         now.AddDate(-1, 0, 0),
+		UpdatedAt:        now.AddDate(0, -1, 0),
+		HTMLURL:          "https://github.com/my-org/api",
+		CloneURL:         "https://github.com/my-org/api.git",
+		SSHURL:           "git@github.com:my-org/api.git",
+		Description:      "The API",
+		Owner:            "my-org",
+		License:          "MIT",
+		Stars:            3,
+		OpenIssues:       2,
+		SizeKB:           512,
+		MirrorURL:        "https://example.com/api.git",
+		Archived:         true,
+		Topics:           []string{"go"},
+		Language:         "Go",
+		IssuesDisabled:   true,
+		WikiDisabled:     true,
+		ProjectsDisabled: true,
+		DefaultBranch:    "main",
+		Visibility:       "private",
+		Protection:       patina.ProtectionProtected,
+		NeverPushed:      true,
+		LastCommitAuthor: "Ada",
+		LastCommitEmail:  "ada@example.com",
+		LastCommitDate:   now.AddDate(-1, 0, 0),
+	}
+}
+
+func TestOutputMatchesSchema(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	repo := schemaSampleRepository(now)
+	view := patina.NewRepositoryView(repo, now)
+	view.Team = "platform"
+	view.Exemption = "frozen"
+
+	scan := scanOutput{
+		Organization: "my-org",
+		FetchedAt:    now,
+		Summary:      patina.FreshnessSummary{Red: 1, Total: 3, Archived: 1, Exempted: 1},
+		Renamed:      []patina.Rename{{ID: 42, OldName: "old-api", NewName: "api"}},
+		Changes:      &patina.Changes{Added: 1},
+		TopStale:     []patina.RepositoryView{view},
+	}
+	list := listOutput{Organization: "my-org", FetchedAt: now, Repositories: []patina.RepositoryView{view}}
+
+	var summaryJSON strings.Builder
+	if err := writeSummaryJSON(&summaryJSON, []scanOutput{scan}); err != nil {
+		t.Fatalf("writeSummaryJSON() error = %v", err)
+	}
+
+	var ndjson strings.Builder
+	result := &patina.ScanResult{Organization: "my-org", FetchedAt: now, Repositories: []patina.Repository{repo}}
+	if err := writeReportNDJSON(&ndjson, result, patina.Ownership{"api": "platform"}, patina.Exemptions{"api": "frozen"}, now); err != nil {
+		t.Fatalf("writeReportNDJSON() error = %v", err)
+	}
+
+	marshal := func(v any) string {
+		data, err := json.Marshal(v)
+		if err != nil {
+			t.Fatalf("json.Marshal() error = %v", err)
+		}
+		return string(data)
+	}
+
+	tests := []struct {
+		name     string
+		schema   string
+		document string
+	}{
+		{"scan", "scan", marshal(scan)},
+		{"scan of several organizations", "scan", marshal([]scanOutput{scan, scan})},
+		{"summary", "summary", summaryJSON.String()},
+		{"list", "list", marshal(list)},
+		{"ndjson", "ndjson", ndjson.String()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema := readShippedSchema(t, tt.schema)
+			for _, line := range strings.Split(strings.TrimSpace(tt.document), "\n") {
+				var doc any
+				if err := json.Unmarshal([]byte(line), &doc); err != nil {
+					t.Fatalf("json.Unmarshal() error = %v", err)
+				}
+				if errs := validateSchema(schema, doc, "$"); len(errs) > 0 {
+					t.Errorf("output does not match schema/%s.schema.json:\n%s", tt.schema, strings.Join(errs, "\n"))
+				}
+			}
+		})
+	}
+}
+
+func TestValidateSchemaRejectsRenamedField(t *testing.T) {
+	schema := readShippedSchema(t, "summary")
+	doc := map[string]any{
+		"organization": "my-org", "total": 1.0, "green": 1.0, "yellow": 0.0, "red": 0.0,
+		"fetched_at": "2024-06-15T12:00:00Z", "from_cache": false,
+	}
+
+	errs := validateSchema(schema, doc, "$")
+	want := []string{"$: missing required property \"org\"", "$.organization: unexpected property"}
+	for _, w := range want {
+		if !slices.Contains(errs, w) {
+			t.Errorf("validateSchema() errors = %q, want %q", errs, w)
+		}
+	}
+}
+
+func readShippedSchema(t *testing.T, name string) map[string]any {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("..", "..", "schema", name+".schema.json"))
+	if err != nil {
+		t.Fatalf("failed to read shipped schema: %v", err)
+	}
+	var schema map[string]any
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatalf("failed to parse shipped schema: %v", err)
+	}
+	return schema
+}
+
+// validateSchema checks a decoded JSON document against the subset of JSON
+// Schema the generated schemas use, returning a description of each mismatch.
+func validateSchema(schema map[string]any, doc any, path string) []string {
+	if alternatives, ok := schema["oneOf"].([]any); ok {
+		matches := 0
+		for _, alternative := range alternatives {
+			if len(validateSchema(alternative.(map[string]any), doc, path)) == 0 {
+				matches++
+			}
+		}
+		if matches != 1 {
+			return []string{fmt.Sprintf("%s: matches %d of the oneOf schemas, want 1", path, matches)}
+		}
+		return nil
+	}
+
+	var errs []string
+	switch schema["type"] {
+	case "object":
+		obj, ok := doc.(map[string]any)
+		if !ok {
+			return []string{path + ": want an object"}
+		}
+		required, _ := schema["required"].([]any)
+		for _, name := range required {
+			if _, ok := obj[name.(string)]; !ok {
+				errs = append(errs, fmt.Sprintf("%s: missing required property %q", path, name))
+			}
+		}
+		properties, _ := schema["properties"].(map[string]any)
+		for name, value := range obj {
+			if property, ok := properties[name]; ok {
+				errs = append(errs, validateSchema(property.(map[string]any), value, path+"."+name)...)
+				continue
+			}
+			switch additional := schema["additionalProperties"].(type) {
+			case bool:
+				if !additional {
+					errs = append(errs, path+"."+name+": unexpected property")
+				}
+			case map[string]any:
+				errs = append(errs, validateSchema(additional, value, path+"."+name)...)
+			}
+		}
+	case "array":
+		arr, ok := doc.([]any)
+		if !ok {
+			return []string{path + ": want an array"}
+		}
+		for i, item := range arr {
+			errs = append(errs, validateSchema(schema["items"].(map[string]any), item, fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	case "string":
+		s, ok := doc.(string)
+		if !ok {
+			return []string{path + ": want a string"}
+		}
+		if enum, ok := schema["enum"].([]any); ok && !slices.Contains(enum, any(s)) {
+			errs = append(errs, fmt.Sprintf("%s: %q is not one of %v", path, s, enum))
+		}
+		if schema["format"] == "date-time" {
+			if _, err := time.Parse(time.RFC3339, s); err != nil {
+				errs = append(errs, fmt.Sprintf("%s: %q is not a date-time", path, s))
+			}
+		}
+	case "integer":
+		if n, ok := doc.(float64); !ok || n != math.Trunc(n) {
+			errs = append(errs, path+": want an integer")
+		}
+	case "number":
+		if _, ok := doc.(float64); !ok {
+			errs = append(errs, path+": want a number")
+		}
+	case "boolean":
+		if _, ok := doc.(bool); !ok {
+			errs = append(errs, path+": want a boolean")
+		}
+	}
+	return errs
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/scottbrown/patina"
+)
+
+// slackTopStale is the number of most stale repositories listed in a Slack message.
+const slackTopStale = 5
+
+// slackMessage is a Slack Block Kit message, as accepted by chat.postMessage
+// and incoming webhooks. Text is the fallback shown in notifications.
+type slackMessage struct {
+	Text   string       `json:"text"`
+	Blocks []slackBlock `json:"blocks"`
+}
+
+type slackBlock struct {
+	Type     string      `json:"type"`
+	Text     *slackText  `json:"text,omitempty"`
+	Elements []slackText `json:"elements,omitempty"`
+}
+
+type slackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// slackEscape escapes the characters Slack treats as control sequences in
+// mrkdwn, so repository names and descriptions cannot form links or mentions.
+func slackEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
+
+// newSlackMessage summarizes an organization's freshness and lists its most
+// stale repositories as links, using Slack mrkdwn: *bold* and <url|text>.
+func newSlackMessage(org string, repos []patina.Repository, now time.Time) slackMessage {
+	summary := freshnessOptions.Summary(repos, now)
+	labels := patina.NewReportLabels(patina.FreshnessThresholds())
+
+	var counts strings.Builder
+	fmt.Fprintf(&counts, "*%d* repositories\n", summary.Total)
+	fmt.Fprintf(&counts, ":large_green_circle: *%d* active (%s)\n", summary.Green, labels.Green)
+	fmt.Fprintf(&counts, ":large_yellow_circle: *%d* aging (%s)\n", summary.Yellow, labels.Yellow)
+	fmt.Fprintf(&counts, ":red_circle: *%d* stale (%s)", summary.Red, labels.Red)
+	if summary.Archived > 0 {
+		fmt.Fprintf(&counts, "\n:lock: *%d* archived", summary.Archived)
+	}
+
+	blocks := []slackBlock{
+		{Type: "header", Text: &slackText{Type: "plain_text", Text: "Repository freshness: " + org}},
+		{Type: "section", Text: &slackText{Type: "mrkdwn", Text: counts.String()}},
+	}
+
+	if topStale := patina.GetTopStale(repos, slackTopStale); len(topStale) > 0 {
+		var list strings.Builder
+		list.WriteString("*Most stale repositories*")
+		for i, view := range freshnessOptions.RepositoryViews(topStale, now) {
+			name := slackEscape(view.Name)
+			if view.HTMLURL != "" {
+				name = fmt.Sprintf("<%s|%s>", view.HTMLURL, name)
+			}
+			fmt.Fprintf(&list, "\n%d. %s – %s", i+1, name, view.Age)
+		}
+		blocks = append(blocks, slackBlock{Type: "section", Text: &slackText{Type: "mrkdwn", Text: list.String()}})
+	}
+
+	blocks = append(blocks, slackBlock{
+		Type:     "context",
+		Elements: []slackText{{Type: "mrkdwn", Text: "Generated by patina on " + now.Format("2006-01-02 15:04")}},
+	})
+
+	return slackMessage{
+		Text:   fmt.Sprintf("%s: %d of %d repositories are stale", org, summary.Red, summary.Total),
+		Blocks: blocks,
+	}
+}
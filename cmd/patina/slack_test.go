@@ -0,0 +1,58 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scottbrown/patina"
+)
+
+func TestSlackEscape(t *testing.T) {
+	if got := slackEscape("a <b> & c"); got != "a &lt;b&gt; &amp; c" {
+		t.Errorf("slackEscape() = %q", got)
+	}
+}
+
+func TestNewSlackMessage(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	repos := []patina.Repository{
+		{Name: "active", LastUpdated: now.AddDate(0, 0, -5)},
+		{Name: "aging", LastUpdated: now.AddDate(0, -3, 0)},
+		{Name: "legacy", HTMLURL: "https://github.com/my-org/legacy", LastUpdated: now.AddDate(-2, 0, 0)},
+		{Name: "<odd>", LastUpdated: now.AddDate(-1, 0, 0)},
+	}
+
+	msg := newSlackMessage("my-org", repos, now)
+
+	if msg.Text != "my-org: 2 of 4 repositories are stale" {
+		t.Errorf("Text = %q", msg.Text)
+	}
+	if len(msg.Blocks) != 4 {
+		t.Fatalf("len(Blocks) = %d, want 4", len(msg.Blocks))
+	}
+	if msg.Blocks[0].Type != "header" || msg.Blocks[0].Text.Text != "Repository freshness: my-org" {
+		t.Errorf("header = %+v", msg.Blocks[0].Text)
+	}
+
+	counts := msg.Blocks[1].Text.Text
+	for _, want := range []string{"*4* repositories", "*1* active (≤2 months)", "*1* aging (2-6 months)", "*2* stale (>6 months)"} {
+		if !strings.Contains(counts, want) {
+			t.Errorf("counts missing %q in %q", want, counts)
+		}
+	}
+
+	list := msg.Blocks[2].Text.Text
+	if msg.Blocks[2].Text.Type != "mrkdwn" {
+		t.Errorf("list type = %s, want mrkdwn", msg.Blocks[2].Text.Type)
+	}
+	for _, want := range []string{"1. <https://github.com/my-org/legacy|legacy> – 2 years ago", "2. &lt;odd&gt; – 1 year ago"} {
+		if !strings.Contains(list, want) {
+			t.Errorf("list missing %q in %q", want, list)
+		}
+	}
+
+	if msg.Blocks[3].Type != "context" {
+		t.Errorf("last block = %s, want context", msg.Blocks[3].Type)
+	}
+}
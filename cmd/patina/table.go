@@ -0,0 +1,169 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// table renders rows of cells as aligned columns. Cells are measured by their
+// terminal display width, so wide characters and colour codes stay aligned.
+type table struct {
+	rows    [][]string
+	borders bool
+}
+
+// addRow appends a row of cells to the table.
+func (t *table) addRow(cells ...string) {
+	t.rows = append(t.rows, cells)
+}
+
+// render writes the table to w. Without borders, columns are separated by two
+// spaces and the last column is not padded.
+func (t *table) render(w io.Writer) {
+	var widths []int
+	for _, row := range t.rows {
+		for i, cell := range row {
+			if i >= len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[i] = max(widths[i], displayWidth(cell))
+		}
+	}
+
+	if !t.borders {
+		for _, row := range t.rows {
+			var b strings.Builder
+			for i, cell := range row {
+				if i > 0 {
+					b.WriteString("  ")
+				}
+				b.WriteString(cell)
+				if i < len(row)-1 {
+					b.WriteString(strings.Repeat(" ", widths[i]-displayWidth(cell)))
+				}
+			}
+			fmt.Fprintln(w, b.String())
+		}
+		return
+	}
+
+	var rule strings.Builder
+	rule.WriteString("+")
+	for _, width := range widths {
+		rule.WriteString(strings.Repeat("-", width+2))
+		rule.WriteString("+")
+	}
+
+	fmt.Fprintln(w, rule.String())
+	for _, row := range t.rows {
+		var b strings.Builder
+		b.WriteString("|")
+		for i, width := range widths {
+			var cell string
+			if i < len(row) {
+				cell = row[i]
+			}
+			b.WriteString(" ")
+			b.WriteString(cell)
+			b.WriteString(strings.Repeat(" ", width-displayWidth(cell)))
+			b.WriteString(" |")
+		}
+		fmt.Fprintln(w, b.String())
+	}
+	fmt.Fprintln(w, rule.String())
+}
+
+// displayWidth returns the number of terminal columns s occupies, ignoring
+// ANSI escape sequences and counting wide characters as two columns.
+func displayWidth(s string) int {
+	width := 0
+	for i := 0; i < len(s); {
+		if s[i] == '\033' {
+			i += escapeLength(s[i:])
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		width += runeWidth(r)
+		i += size
+	}
+	return width
+}
+
+// truncateName shortens name to at most limit terminal columns, ending it with
+// an ellipsis if it was cut. A limit of 0 means no limit.
+func truncateName(name string, limit int) string {
+	if limit <= 0 || displayWidth(name) <= limit {
+		return name
+	}
+
+	var b strings.Builder
+	width := 0
+	for _, r := range name {
+		if width+runeWidth(r) > limit-1 {
+			break
+		}
+		b.WriteRune(r)
+		width += runeWidth(r)
+	}
+	return b.String() + "…"
+}
+
+// escapeLength returns the length of the ANSI CSI sequence at the start of s,
+// or 1 to skip a lone escape character.
+func escapeLength(s string) int {
+	if len(s) < 2 || s[1] != '[' {
+		return 1
+	}
+	for j := 2; j < len(s); j++ {
+		if s[j] >= 0x40 && s[j] <= 0x7e {
+			return j + 1
+		}
+	}
+	return len(s)
+}
+
+// runeWidth returns the number of terminal columns a rune occupies.
+func runeWidth(r rune) int {
+	switch {
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf):
+		return 0
+	case isWide(r):
+		return 2
+	default:
+		return 1
+	}
+}
+
+// wideRanges lists East Asian wide and fullwidth characters and the emoji
+// blocks terminals render in two columns.
+var wideRanges = []struct{ lo, hi rune }{
+	{0x1100, 0x115F},   // Hangul Jamo
+	{0x2E80, 0x303E},   // CJK radicals and punctuation
+	{0x3041, 0x33FF},   // Kana and CJK symbols
+	{0x3400, 0x4DBF},   // CJK extension A
+	{0x4E00, 0x9FFF},   // CJK unified ideographs
+	{0xA000, 0xA4CF},   // Yi
+	{0xAC00, 0xD7A3},   // Hangul syllables
+	{0xF900, 0xFAFF},   // CJK compatibility ideographs
+	{0xFE30, 0xFE4F},   // CJK compatibility forms
+	{0xFF00, 0xFF60},   // Fullwidth forms
+	{0xFFE0, 0xFFE6},   // Fullwidth signs
+	{0x1F300, 0x1F64F}, // Miscellaneous symbols, pictographs, and emoticons
+	{0x1F680, 0x1F6FF}, // Transport and map symbols
+	{0x1F7E0, 0x1F7EB}, // Coloured circles and squares
+	{0x1F900, 0x1F9FF}, // Supplemental symbols and pictographs
+	{0x20000, 0x3FFFD}, // CJK extensions B and beyond
+}
+
+// isWide reports whether a rune is rendered two columns wide.
+func isWide(r rune) bool {
+	for _, rng := range wideRanges {
+		if r >= rng.lo && r <= rng.hi {
+			return true
+		}
+	}
+	return false
+}
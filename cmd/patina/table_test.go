@@ -0,0 +1,94 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDisplayWidth(t *testing.T) {
+	tests := []struct {
+		s    string
+		want int
+	}{
+		{"", 0},
+		{"legacy-api", 10},
+		{"\033[31mred\033[0m", 3},
+		{"café", 4},
+		{"café", 4},
+		{"日本語", 6},
+		{"🔴 stale", 8},
+		{"[R] stale", 9},
+	}
+
+	for _, tt := range tests {
+		if got := displayWidth(tt.s); got != tt.want {
+			t.Errorf("displayWidth(%q) = %d, want %d", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestTableRender(t *testing.T) {
+	tests := []struct {
+		name    string
+		borders bool
+		want    string
+	}{
+		{
+			name: "plain",
+			want: "api     1 year ago\n" +
+				"日本語  3 days ago\n" +
+				"\033[31mred\033[0m     today\n",
+		},
+		{
+			name:    "borders",
+			borders: true,
+			want: "+--------+------------+\n" +
+				"| api    | 1 year ago |\n" +
+				"| 日本語 | 3 days ago |\n" +
+				"| \033[31mred\033[0m    | today      |\n" +
+				"+--------+------------+\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tbl := &table{borders: tt.borders}
+			tbl.addRow("api", "1 year ago")
+			tbl.addRow("日本語", "3 days ago")
+			tbl.addRow("\033[31mred\033[0m", "today")
+
+			var b strings.Builder
+			tbl.render(&b)
+
+			if b.String() != tt.want {
+				t.Errorf("render() =\n%s\nwant\n%s", b.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestTruncateName(t *testing.T) {
+	tests := []struct {
+		name  string
+		limit int
+		want  string
+	}{
+		{"payments-api", 0, "payments-api"},
+		{"payments-api", 12, "payments-api"},
+		{"payments-api", 8, "payment…"},
+		{"日本語リポジトリ", 7, "日本語…"},
+	}
+
+	for _, tt := range tests {
+		if got := truncateName(tt.name, tt.limit); got != tt.want {
+			t.Errorf("truncateName(%q, %d) = %q, want %q", tt.name, tt.limit, got, tt.want)
+		}
+	}
+}
+
+func TestFitNameWidth(t *testing.T) {
+	var b strings.Builder
+	if got := fitNameWidth(&b); got != 0 {
+		t.Errorf("fitNameWidth() = %d for redirected output, want 0", got)
+	}
+}
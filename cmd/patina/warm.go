@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/scottbrown/patina"
+	"github.com/spf13/cobra"
+)
+
+var (
+	warmRefresh    bool
+	warmLastCommit bool
+	warmOrgs       string
+	warmDeadline   time.Duration
+	warmOrgTimeout time.Duration
+)
+
+var warmCmd = &cobra.Command{
+	Use:   "warm <organization>...",
+	Short: "Fetch and cache several organizations in parallel",
+	Long: `Warm fetches repository data for one or more GitHub organizations and
+stores it in the cache, without printing scan output. Organizations are
+fetched in parallel while sharing a single rate-limited API client.
+
+Organizations with a valid cache are skipped unless --refresh is given.
+A summary line is printed for each organization; the command fails if any
+organization could not be fetched.
+
+Use --org-timeout to limit the time spent on each organization, and
+--deadline to limit the whole run; organizations that run out of time are
+reported as failed while the others are still cached.
+
+Use --orgs-file to read organizations from a file, one per line (- reads
+stdin), in addition to any given as arguments.
+
+Example:
+  patina warm org-a org-b org-c && patina report org-a`,
+	Args: cobra.ArbitraryArgs,
+	RunE: runWarm,
+}
+
+func init() {
+	warmCmd.Flags().BoolVarP(&warmRefresh, "refresh", "r", false, "Force refresh from GitHub API")
+	warmCmd.Flags().StringVar(&warmOrgs, "orgs-file", "", "Read organizations from this file, one per line (- for stdin)")
+	warmCmd.Flags().BoolVar(&warmLastCommit, "with-last-commit", false, "Also look up the last commit author and date for each repo")
+	warmCmd.Flags().DurationVar(&warmDeadline, "deadline", 0, "Total time allowed for fetching all organizations (0 for no limit)")
+	warmCmd.Flags().DurationVar(&warmOrgTimeout, "org-timeout", 0, "Time allowed for fetching each organization (0 for no limit)")
+}
+
+func runWarm(cmd *cobra.Command, args []string) error {
+	w := cmd.OutOrStdout()
+	if warmDeadline < 0 {
+		return fmt.Errorf("invalid deadline value: %s (must be 0 or greater)", warmDeadline)
+	}
+	if warmOrgTimeout < 0 {
+		return fmt.Errorf("invalid org-timeout value: %s (must be 0 or greater)", warmOrgTimeout)
+	}
+
+	orgs, err := resolveOrgs(cmd, args, warmOrgs, 1)
+	if err != nil {
+		return err
+	}
+
+	scanner, err := newScanner()
+	if err != nil {
+		return fmt.Errorf("failed to initialize scanner: %w", err)
+	}
+
+	results := scanner.Warm(orgs, patina.ScanOptions{
+		Refresh:        warmRefresh,
+		WithLastCommit: warmLastCommit,
+		Freshness:      freshnessOptions,
+		OrgTimeout:     warmOrgTimeout,
+		Deadline:       warmDeadline,
+	})
+
+	if failed := printWarmResults(w, results); failed > 0 {
+		return fmt.Errorf("failed to warm %d of %d organizations", failed, len(results))
+	}
+	return nil
+}
+
+// printWarmResults writes one summary line per organization and returns the
+// number that failed.
+func printWarmResults(w io.Writer, results []patina.WarmResult) int {
+	maxNameLen := 0
+	for _, r := range results {
+		if len(r.Organization) > maxNameLen {
+			maxNameLen = len(r.Organization)
+		}
+	}
+
+	failed := 0
+	for _, r := range results {
+		switch {
+		case r.Err != nil:
+			failed++
+			fmt.Fprintf(w, "%-*s  failed: %v\n", maxNameLen, r.Organization, r.Err)
+		case r.FromCache:
+			fmt.Fprintf(w, "%-*s  already cached (%d repositories)\n", maxNameLen, r.Organization, r.Repositories)
+		default:
+			fmt.Fprintf(w, "%-*s  fetched %d repositories\n", maxNameLen, r.Organization, r.Repositories)
+		}
+	}
+
+	return failed
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/scottbrown/patina"
+	"github.com/spf13/cobra"
+)
+
+var whyRefresh bool
+
+var whyCmd = &cobra.Command{
+	Use:   "why <organization> <repository>",
+	Short: "Explain how a repository's freshness was determined",
+	Long: `Why prints the details behind a single repository's freshness status:
+its last-updated timestamp, its age in days, the threshold boundaries it
+falls between, and the resulting freshness.
+
+Cached data is used if available. Use --refresh to force a fresh fetch.
+
+Example:
+  patina why my-org legacy-api`,
+	Args: cobra.ExactArgs(2),
+	RunE: runWhy,
+}
+
+func init() {
+	whyCmd.Flags().BoolVarP(&whyRefresh, "refresh", "r", false, "Force refresh from GitHub API")
+}
+
+func runWhy(cmd *cobra.Command, args []string) error {
+	org, name := args[0], args[1]
+
+	scanner, err := newScanner()
+	if err != nil {
+		return fmt.Errorf("failed to initialize scanner: %w", err)
+	}
+
+	result, err := scanner.Scan(org, patina.ScanOptions{Refresh: whyRefresh, IncludeForks: true, IncludeMirrors: true, ActivityMetric: activityMetric, Freshness: freshnessOptions})
+	if err != nil {
+		return fmt.Errorf("failed to scan organization: %w", err)
+	}
+	warnCacheWrite(result)
+
+	repo, ok := patina.FindRepository(result.Repositories, name)
+	if !ok {
+		return fmt.Errorf("repository %q not found in %s", name, org)
+	}
+
+	now := time.Now()
+
+	if result.FromCache {
+		fmt.Fprintf(statusWriter(cmd.OutOrStdout()), "Using cached data from %s\n\n", result.FetchedAt.Format("2006-01-02 15:04:05"))
+	}
+
+	printExplanation(cmd.OutOrStdout(), repo, now)
+	return nil
+}
+
+// printExplanation writes how a repository's freshness was determined.
+func printExplanation(w io.Writer, repo patina.Repository, now time.Time) {
+	explanation := freshnessOptions.Explain(repo.LastUpdated, now)
+	freshness := explanation.Freshness
+
+	fmt.Fprintf(w, "Repository:   %s\n", repo.FullName)
+	fmt.Fprintf(w, "Last updated: %s (%s)\n", repo.LastUpdated.Format("2006-01-02 15:04:05"), freshnessOptions.Age(repo.LastUpdated, now))
+	fmt.Fprintf(w, "Age:          %d days\n", int(explanation.AgeDays))
+	fmt.Fprintf(w, "Thresholds:   yellow after %d days, red after %d days\n",
+		int(explanation.YellowThreshold.Hours()/24), int(explanation.RedThreshold.Hours()/24))
+	fmt.Fprintf(w, "Freshness:    %s %s%s%s\n", symbol(freshness), colour(freshness), freshness, patina.ColourReset())
+	fmt.Fprintf(w, "Reason:       %s\n", explanation.Reason)
+}
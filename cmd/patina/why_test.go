@@ -0,0 +1,28 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scottbrown/patina"
+)
+
+func TestPrintExplanation(t *testing.T) {
+	useASCII(t)
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	repo := patina.Repository{FullName: "my-org/legacy-api", LastUpdated: now.AddDate(0, 0, -200)}
+
+	var b strings.Builder
+	printExplanation(&b, repo, now)
+
+	want := "Repository:   my-org/legacy-api\n" +
+		"Last updated: 2023-11-28 12:00:00 (6 months ago)\n" +
+		"Age:          200 days\n" +
+		"Thresholds:   yellow after 60 days, red after 180 days\n" +
+		"Freshness:    [R] " + red + "red" + reset + "\n" +
+		"Reason:       200.0 days > 180-day red threshold\n"
+	if b.String() != want {
+		t.Errorf("printExplanation() =\n%q\nwant\n%q", b.String(), want)
+	}
+}
@@ -0,0 +1,222 @@
+package patina
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LastCommit identifies the most recent commit on a repository's default branch.
+type LastCommit struct {
+	Author string // GitHub login, or the git author name if the commit is not linked to an account
+	Email  string
+	Date   time.Time
+}
+
+// lastCommitFetcher is implemented by clients that can look up a repository's last commit.
+type lastCommitFetcher interface {
+	FetchLastCommit(fullName string) (LastCommit, error)
+}
+
+// ghCommit represents the commit data returned by the GitHub API.
+type ghCommit struct {
+	Commit struct {
+		Author struct {
+			Name  string    `json:"name"`
+			Email string    `json:"email"`
+			Date  time.Time `json:"date"`
+		} `json:"author"`
+	} `json:"commit"`
+	Author *ghOwner `json:"author"`
+}
+
+// parseLastCommit extracts the last commit from a commits list response.
+// An empty list yields a zero LastCommit.
+func parseLastCommit(body []byte) (LastCommit, error) {
+	var commits []ghCommit
+	if err := json.Unmarshal(body, &commits); err != nil {
+		return LastCommit{}, fmt.Errorf("failed to parse response: %w", err)
+	}
+	if len(commits) == 0 {
+		return LastCommit{}, nil
+	}
+
+	c := commits[0]
+	last := LastCommit{
+		Author: c.Commit.Author.Name,
+		Email:  c.Commit.Author.Email,
+		Date:   c.Commit.Author.Date,
+	}
+	if c.Author != nil && c.Author.Login != "" {
+		last.Author = c.Author.Login
+	}
+	return last, nil
+}
+
+// FetchLastCommit retrieves the last commit of a repository using the GitHub API with a token.
+// Empty repositories yield a zero LastCommit.
+func (c *tokenClient) FetchLastCommit(fullName string) (LastCommit, error) {
+	url := fmt.Sprintf("%s/repos/%s/commits?per_page=1", c.baseURL, fullName)
+
+	slog.Debug("requesting last commit", "url", url)
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return LastCommit{}, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+
+	resp, err := c.executor.Do(req)
+	if err != nil {
+		return LastCommit{}, fmt.Errorf("failed to fetch last commit: %w", err)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return LastCommit{}, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	// GitHub answers 409 Conflict for repositories with no commits
+	if resp.StatusCode == http.StatusConflict {
+		return LastCommit{}, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return LastCommit{}, apiError(fullName, resp.StatusCode, body)
+	}
+
+	return parseLastCommit(body)
+}
+
+// FetchLastCommit retrieves the last commit of a repository using an installation token.
+func (c *appClient) FetchLastCommit(fullName string) (LastCommit, error) {
+	token, err := c.installationToken()
+	if err != nil {
+		return LastCommit{}, err
+	}
+
+	client := &tokenClient{token: token, baseURL: c.baseURL, executor: c.executor}
+	return client.FetchLastCommit(fullName)
+}
+
+// FetchLastCommit retrieves the last commit of a repository using the gh CLI.
+func (c *ghCLIClient) FetchLastCommit(fullName string) (LastCommit, error) {
+	args := []string{
+		"api",
+		"--method", "GET",
+		fmt.Sprintf("/repos/%s/commits", fullName),
+		"-f", "per_page=1",
+	}
+
+	slog.Debug("running gh", "args", args)
+
+	stdout, stderr, err := c.run(args...)
+	if err != nil {
+		if strings.Contains(stderr.String(), "HTTP 409") {
+			return LastCommit{}, nil
+		}
+		return LastCommit{}, wrapGHError(err, stderr.String())
+	}
+
+	return parseLastCommit(stdout.Bytes())
+}
+
+// lastCommitLookedUp reports whether a repository's last commit was looked
+// up. Caches written before LastCommitChecked was recorded only have the date.
+func lastCommitLookedUp(repo Repository) bool {
+	return repo.LastCommitChecked || !repo.LastCommitDate.IsZero()
+}
+
+// enrichMissingLastCommits looks up the last commit of only those repositories
+// not yet looked up, reporting whether any were.
+func enrichMissingLastCommits(fetcher lastCommitFetcher, repos []Repository) bool {
+	var missing []Repository
+	var indexes []int
+	for i, repo := range repos {
+		if !lastCommitLookedUp(repo) {
+			missing = append(missing, repo)
+			indexes = append(indexes, i)
+		}
+	}
+	if len(missing) == 0 {
+		return false
+	}
+
+	enrichLastCommits(fetcher, missing)
+	for j, i := range indexes {
+		repos[i] = missing[j]
+	}
+	return true
+}
+
+// mergeLastCommits copies last commits looked up on an earlier fetch into the
+// freshly fetched repositories that have not been pushed to since, so they do
+// not need to be looked up again. Repositories are matched by ID, or by name
+// if either copy lacks an ID.
+func mergeLastCommits(previous, current []Repository) {
+	byID := make(map[int64]Repository, len(previous))
+	byName := make(map[string]Repository, len(previous))
+	for _, repo := range previous {
+		if !lastCommitLookedUp(repo) {
+			continue
+		}
+		if repo.ID != 0 {
+			byID[repo.ID] = repo
+		}
+		byName[repo.Name] = repo
+	}
+
+	for i := range current {
+		repo := &current[i]
+		old, ok := byID[repo.ID]
+		if repo.ID == 0 || !ok {
+			old, ok = byName[repo.Name]
+		}
+		if !ok || lastCommitLookedUp(*repo) || !old.LastUpdated.Equal(repo.LastUpdated) {
+			continue
+		}
+		repo.LastCommitAuthor = old.LastCommitAuthor
+		repo.LastCommitEmail = old.LastCommitEmail
+		repo.LastCommitDate = old.LastCommitDate
+		repo.LastCommitChecked = true
+	}
+}
+
+// enrichLastCommits looks up the last commit of each repository concurrently,
+// filling in LastCommitAuthor, LastCommitEmail, and LastCommitDate and marking
+// it checked. Failures for individual repositories are logged and leave only
+// the mark.
+func enrichLastCommits(fetcher lastCommitFetcher, repos []Repository) {
+	sem := make(chan struct{}, defaultConcurrency)
+	var wg sync.WaitGroup
+
+	for i := range repos {
+		wg.Add(1)
+		go func(repo *Repository) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			repo.LastCommitChecked = true
+			last, err := fetcher.FetchLastCommit(repo.FullName)
+			if err != nil {
+				slog.Warn("failed to fetch last commit", "repo", repo.FullName, "error", err)
+				return
+			}
+
+			repo.LastCommitAuthor = last.Author
+			repo.LastCommitEmail = last.Email
+			repo.LastCommitDate = last.Date
+		}(&repos[i])
+	}
+
+	wg.Wait()
+}
@@ -0,0 +1,224 @@
+package patina
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseLastCommit(t *testing.T) {
+	body := `[{"commit":{"author":{"name":"Alice Smith","email":"alice@example.com","date":"2024-01-02T03:04:05Z"}},"author":{"login":"alice"}}]`
+
+	last, err := parseLastCommit([]byte(body))
+	if err != nil {
+		t.Fatalf("parseLastCommit() error = %v", err)
+	}
+
+	if last.Author != "alice" {
+		t.Errorf("Author = %s, want alice", last.Author)
+	}
+	if last.Email != "alice@example.com" {
+		t.Errorf("Email = %s, want alice@example.com", last.Email)
+	}
+	if !last.Date.Equal(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)) {
+		t.Errorf("Date = %v, want 2024-01-02T03:04:05Z", last.Date)
+	}
+
+	// Commits not linked to a GitHub account fall back to the git author name
+	unlinked, err := parseLastCommit([]byte(`[{"commit":{"author":{"name":"Bob"}},"author":null}]`))
+	if err != nil {
+		t.Fatalf("parseLastCommit() error = %v", err)
+	}
+	if unlinked.Author != "Bob" {
+		t.Errorf("Author = %s, want Bob", unlinked.Author)
+	}
+
+	empty, err := parseLastCommit([]byte(`[]`))
+	if err != nil {
+		t.Fatalf("parseLastCommit() error = %v", err)
+	}
+	if empty != (LastCommit{}) {
+		t.Errorf("parseLastCommit([]) = %+v, want zero", empty)
+	}
+}
+
+func TestTokenClientFetchLastCommit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/org/repo1/commits":
+			if r.URL.Query().Get("per_page") != "1" {
+				t.Errorf("per_page = %q, want 1", r.URL.Query().Get("per_page"))
+			}
+			fmt.Fprint(w, `[{"commit":{"author":{"name":"Alice","date":"2024-01-02T03:04:05Z"}},"author":{"login":"alice"}}]`)
+		case "/repos/org/empty/commits":
+			w.WriteHeader(http.StatusConflict)
+			fmt.Fprint(w, `{"message":"Git Repository is empty."}`)
+		case "/repos/org/private/commits":
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"message":"Resource not accessible by integration"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := &tokenClient{
+		token:    "ghp_token",
+		baseURL:  server.URL,
+		executor: newRequestExecutor(server.Client(), 1),
+	}
+
+	last, err := client.FetchLastCommit("org/repo1")
+	if err != nil {
+		t.Fatalf("FetchLastCommit() error = %v", err)
+	}
+	if last.Author != "alice" {
+		t.Errorf("Author = %s, want alice", last.Author)
+	}
+
+	empty, err := client.FetchLastCommit("org/empty")
+	if err != nil {
+		t.Fatalf("FetchLastCommit() error = %v for empty repo, want nil", err)
+	}
+	if !empty.Date.IsZero() {
+		t.Errorf("Date = %v for empty repo, want zero", empty.Date)
+	}
+
+	if _, err := client.FetchLastCommit("org/missing"); err == nil {
+		t.Error("FetchLastCommit() error = nil for missing repo, want error")
+	}
+
+	_, err = client.FetchLastCommit("org/private")
+	if !errors.Is(err, ErrNoAccess) {
+		t.Fatalf("FetchLastCommit() error = %v for 403, want ErrNoAccess", err)
+	}
+	if !strings.Contains(err.Error(), "no access to repository 'org/private'") {
+		t.Errorf("FetchLastCommit() error = %q, want it to name the repository", err)
+	}
+}
+
+func TestScannerWithLastCommit(t *testing.T) {
+	cache := NewCacheWithDir(t.TempDir())
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	commitDate := now.AddDate(0, -3, 0)
+
+	mockClient := &mockGitHubClient{
+		repos: []Repository{
+			{Name: "repo1", FullName: "org/repo1", LastUpdated: now.AddDate(0, 0, -30)},
+			{Name: "repo2", FullName: "org/repo2", LastUpdated: now.AddDate(-1, 0, 0)},
+		},
+		commits: map[string]LastCommit{
+			"org/repo1": {Author: "alice", Email: "alice@example.com", Date: commitDate},
+		},
+	}
+
+	scanner := NewScannerWithDeps(mockClient, cache)
+
+	// Enrichment is skipped unless requested
+	if _, err := scanner.Scan("org", ScanOptions{}); err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if mockClient.commitLookups != 0 {
+		t.Errorf("commitLookups = %d without WithLastCommit, want 0", mockClient.commitLookups)
+	}
+
+	// Cached repositories are enriched and persisted; failures leave repos unchanged
+	result, err := scanner.Scan("org", ScanOptions{WithLastCommit: true})
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if mockClient.commitLookups != 2 {
+		t.Errorf("commitLookups = %d, want 2", mockClient.commitLookups)
+	}
+
+	repo1, _ := FindRepository(result.Repositories, "repo1")
+	if repo1.LastCommitAuthor != "alice" || !repo1.LastCommitDate.Equal(commitDate) {
+		t.Errorf("repo1 last commit = %s at %v, want alice at %v", repo1.LastCommitAuthor, repo1.LastCommitDate, commitDate)
+	}
+	repo2, _ := FindRepository(result.Repositories, "repo2")
+	if repo2.LastCommitAuthor != "" || !repo2.LastCommitChecked {
+		t.Errorf("repo2 last commit = %q, checked %t; want empty and checked after failed lookup", repo2.LastCommitAuthor, repo2.LastCommitChecked)
+	}
+
+	loaded, err := cache.Load("org")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	cachedRepo1, _ := FindRepository(loaded.Repositories, "repo1")
+	if cachedRepo1.LastCommitAuthor != "alice" {
+		t.Errorf("cached LastCommitAuthor = %s, want alice", cachedRepo1.LastCommitAuthor)
+	}
+	if !loaded.FetchedAt.Equal(result.FetchedAt) {
+		t.Errorf("FetchedAt = %v after enrichment, want unchanged %v", loaded.FetchedAt, result.FetchedAt)
+	}
+
+	// Repositories already looked up, even without finding a commit, are not
+	// looked up again
+	if _, err := scanner.Scan("org", ScanOptions{WithLastCommit: true}); err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if mockClient.commitLookups != 2 {
+		t.Errorf("commitLookups = %d on a cached scan, want 2", mockClient.commitLookups)
+	}
+}
+
+func TestScannerRefreshKeepsLastCommits(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	commitDate := now.AddDate(0, -3, 0)
+
+	mockClient := &mockGitHubClient{
+		repos: []Repository{
+			{ID: 1, Name: "repo1", FullName: "org/repo1", LastUpdated: commitDate},
+			{ID: 2, Name: "repo2", FullName: "org/repo2", LastUpdated: commitDate},
+		},
+		commits: map[string]LastCommit{
+			"org/repo1": {Author: "alice", Date: commitDate},
+			"org/repo2": {Author: "bob", Date: commitDate},
+			"org/repo3": {Author: "carol", Date: now},
+		},
+	}
+	scanner := NewScannerWithDeps(mockClient, NewCacheWithDir(t.TempDir()))
+
+	if _, err := scanner.Scan("org", ScanOptions{WithLastCommit: true}); err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if mockClient.commitLookups != 2 {
+		t.Fatalf("commitLookups = %d, want 2", mockClient.commitLookups)
+	}
+
+	// repo2 has been pushed to and renamed since, and repo3 is new
+	mockClient.repos = []Repository{
+		{ID: 1, Name: "repo1", FullName: "org/repo1", LastUpdated: commitDate},
+		{ID: 2, Name: "renamed", FullName: "org/repo2", LastUpdated: now},
+		{ID: 3, Name: "repo3", FullName: "org/repo3", LastUpdated: now},
+	}
+
+	// Without enrichment, unchanged repositories keep their last commit
+	result, err := scanner.Scan("org", ScanOptions{Refresh: true})
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if mockClient.commitLookups != 2 {
+		t.Errorf("commitLookups = %d after refresh without enrichment, want 2", mockClient.commitLookups)
+	}
+	repo1, _ := FindRepository(result.Repositories, "repo1")
+	if repo1.LastCommitAuthor != "alice" {
+		t.Errorf("repo1.LastCommitAuthor = %q, want alice kept from the previous fetch", repo1.LastCommitAuthor)
+	}
+	renamed, _ := FindRepository(result.Repositories, "renamed")
+	if renamed.LastCommitAuthor != "" {
+		t.Errorf("renamed.LastCommitAuthor = %q, want empty after a new push", renamed.LastCommitAuthor)
+	}
+
+	// With enrichment, only the pushed-to and new repositories are looked up
+	if _, err := scanner.Scan("org", ScanOptions{Refresh: true, WithLastCommit: true}); err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if mockClient.commitLookups != 4 {
+		t.Errorf("commitLookups = %d, want 4", mockClient.commitLookups)
+	}
+}
@@ -0,0 +1,39 @@
+package patina
+
+import "sort"
+
+// OrgComparison is one organization's freshness counts in a multi-organization comparison.
+type OrgComparison struct {
+	Organization string  `json:"organization"`
+	Total        int     `json:"total"`
+	Green        int     `json:"green"`
+	Yellow       int     `json:"yellow"`
+	Red          int     `json:"red"`
+	StalePercent float64 `json:"stale_percent"`
+}
+
+// CompareSummaries builds a comparison row for each organization's summary,
+// sorted by stale percentage descending and then by organization name.
+func CompareSummaries(summaries map[string]FreshnessSummary) []OrgComparison {
+	rows := make([]OrgComparison, 0, len(summaries))
+	for org, summary := range summaries {
+		row := OrgComparison{
+			Organization: org,
+			Total:        summary.Total,
+			Green:        summary.Green,
+			Yellow:       summary.Yellow,
+			Red:          summary.Red,
+		}
+		_, _, row.StalePercent = CalculatePercentages(summary)
+		rows = append(rows, row)
+	}
+
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].StalePercent != rows[j].StalePercent {
+			return rows[i].StalePercent > rows[j].StalePercent
+		}
+		return rows[i].Organization < rows[j].Organization
+	})
+
+	return rows
+}
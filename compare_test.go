@@ -0,0 +1,34 @@
+package patina
+
+import "testing"
+
+func TestCompareSummaries(t *testing.T) {
+	summaries := map[string]FreshnessSummary{
+		"alpha": {Green: 3, Yellow: 0, Red: 1, Total: 4},
+		"beta":  {Green: 1, Yellow: 1, Red: 2, Total: 4},
+		"gamma": {Green: 2, Yellow: 1, Red: 1, Total: 4},
+		"empty": {},
+	}
+
+	rows := CompareSummaries(summaries)
+
+	wantOrder := []string{"beta", "alpha", "gamma", "empty"}
+	if len(rows) != len(wantOrder) {
+		t.Fatalf("len(rows) = %d, want %d", len(rows), len(wantOrder))
+	}
+	for i, org := range wantOrder {
+		if rows[i].Organization != org {
+			t.Errorf("rows[%d].Organization = %q, want %q", i, rows[i].Organization, org)
+		}
+	}
+
+	if rows[0].StalePercent != 50 {
+		t.Errorf("beta StalePercent = %v, want 50", rows[0].StalePercent)
+	}
+	if rows[0].Total != 4 || rows[0].Green != 1 || rows[0].Yellow != 1 || rows[0].Red != 2 {
+		t.Errorf("beta counts = %+v, want total 4, green 1, yellow 1, red 2", rows[0])
+	}
+	if rows[3].StalePercent != 0 {
+		t.Errorf("empty StalePercent = %v, want 0", rows[3].StalePercent)
+	}
+}
@@ -0,0 +1,90 @@
+package patina
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// Exemptions maps known-stale repositories, such as frozen reference
+// implementations, to the reason they are exempt from the red count. Keys are
+// lower-cased repository names or owner/name full names.
+type Exemptions map[string]string
+
+// ReadExemptions reads an exemptions file; see ParseExemptions for its format.
+func ReadExemptions(path string) (Exemptions, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read exemptions file: %w", err)
+	}
+	defer f.Close()
+
+	exemptions, err := ParseExemptions(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse exemptions file %s: %w", path, err)
+	}
+	return exemptions, nil
+}
+
+// ParseExemptions reads CSV rows of repository and reason, where the
+// repository is a name or an owner/name full name. Lines starting with # are
+// ignored, as is a header row whose first field is "repo", "repository", or "name".
+func ParseExemptions(r io.Reader) (Exemptions, error) {
+	return parseRepositoryCSV(r, "reason")
+}
+
+// Reason returns why a repository is exempt, matching its full name before
+// its name, and whether it is exempt at all.
+func (e Exemptions) Reason(repo Repository) (string, bool) {
+	if reason, ok := e[strings.ToLower(repo.FullName)]; ok && repo.FullName != "" {
+		return reason, true
+	}
+	reason, ok := e[strings.ToLower(repo.Name)]
+	return reason, ok
+}
+
+// Exclude returns the repositories the exemptions file does not list.
+func (e Exemptions) Exclude(repos []Repository) []Repository {
+	var kept []Repository
+	for _, repo := range repos {
+		if _, ok := e.Reason(repo); !ok {
+			kept = append(kept, repo)
+		}
+	}
+	return kept
+}
+
+// Annotate sets the exemption reason of each stale view the exemptions file lists.
+func (e Exemptions) Annotate(views []RepositoryView) {
+	for i := range views {
+		if views[i].Freshness != FreshnessRed {
+			continue
+		}
+		if reason, ok := e.Reason(views[i].Repository); ok {
+			views[i].Exemption = reason
+		}
+	}
+}
+
+// CalculateSummaryWithExemptions computes the freshness summary like
+// CalculateSummary, but counts stale repositories the exemptions file lists
+// as Exempted rather than Red. Exempt repositories that are not stale are
+// counted as usual.
+func CalculateSummaryWithExemptions(repos []Repository, exemptions Exemptions, now time.Time) FreshnessSummary {
+	return FreshnessOptions{}.SummaryWithExemptions(repos, exemptions, now)
+}
+
+// SummaryWithExemptions computes the freshness summary like
+// CalculateSummaryWithExemptions, counting ages with these options.
+func (o FreshnessOptions) SummaryWithExemptions(repos []Repository, exemptions Exemptions, now time.Time) FreshnessSummary {
+	summary := o.Summary(repos, now)
+	for _, repo := range repos {
+		if _, ok := exemptions.Reason(repo); ok && o.RepositoryFreshness(repo, now) == FreshnessRed {
+			summary.Red--
+			summary.Exempted++
+		}
+	}
+	return summary
+}
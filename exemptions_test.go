@@ -0,0 +1,112 @@
+package patina
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseExemptions(t *testing.T) {
+	input := "repo,reason\n" +
+		"# Frozen reference implementations\n" +
+		"reference-impl, \"Frozen, kept for reference\"\n" +
+		"Other-Org/Spec,Published spec\n"
+
+	exemptions, err := ParseExemptions(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseExemptions() error = %v", err)
+	}
+
+	tests := []struct {
+		repo   Repository
+		want   string
+		exempt bool
+	}{
+		{Repository{Name: "reference-impl", FullName: "my-org/reference-impl"}, "Frozen, kept for reference", true},
+		{Repository{Name: "Reference-Impl", FullName: "my-org/Reference-Impl"}, "Frozen, kept for reference", true},
+		{Repository{Name: "spec", FullName: "other-org/spec"}, "Published spec", true},
+		{Repository{Name: "spec", FullName: "my-org/spec"}, "", false},
+	}
+	for _, tt := range tests {
+		got, ok := exemptions.Reason(tt.repo)
+		if got != tt.want || ok != tt.exempt {
+			t.Errorf("Reason(%s) = %q, %t; want %q, %t", tt.repo.FullName, got, ok, tt.want, tt.exempt)
+		}
+	}
+
+	for _, bad := range []string{"api\n", "api,\n", "repo,reason\nweb, \n"} {
+		if _, err := ParseExemptions(strings.NewReader(bad)); err == nil {
+			t.Errorf("ParseExemptions(%q) error = nil, want error", bad)
+		}
+	}
+}
+
+func TestReadExemptions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "exemptions.csv")
+	if err := os.WriteFile(path, []byte("spec,frozen\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	exemptions, err := ReadExemptions(path)
+	if err != nil || exemptions["spec"] != "frozen" {
+		t.Errorf("ReadExemptions() = %v, %v; want spec exempt as frozen", exemptions, err)
+	}
+
+	if _, err := ReadExemptions(filepath.Join(t.TempDir(), "missing.csv")); err == nil {
+		t.Error("ReadExemptions() error = nil for a missing file")
+	}
+}
+
+func TestCalculateSummaryWithExemptions(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	repos := []Repository{
+		{Name: "api", LastUpdated: now.AddDate(0, 0, -10)},
+		{Name: "legacy", LastUpdated: now.AddDate(-1, 0, 0)},
+		{Name: "spec", LastUpdated: now.AddDate(-2, 0, 0)},
+		{Name: "active-spec", LastUpdated: now.AddDate(0, 0, -5)},
+	}
+	exemptions := Exemptions{"spec": "frozen", "active-spec": "frozen"}
+
+	got := CalculateSummaryWithExemptions(repos, exemptions, now)
+	want := FreshnessSummary{Green: 2, Red: 1, Exempted: 1, Total: 4}
+	if got != want {
+		t.Errorf("CalculateSummaryWithExemptions() = %+v, want %+v", got, want)
+	}
+
+	if got := CalculateSummaryWithExemptions(repos, nil, now); got != CalculateSummary(repos, now) {
+		t.Errorf("CalculateSummaryWithExemptions(nil) = %+v, want the plain summary", got)
+	}
+}
+
+func TestExemptionsAnnotate(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	views := NewRepositoryViews([]Repository{
+		{Name: "spec", LastUpdated: now.AddDate(-2, 0, 0)},
+		{Name: "active-spec", LastUpdated: now.AddDate(0, 0, -5)},
+		{Name: "legacy", LastUpdated: now.AddDate(-1, 0, 0)},
+	}, now)
+
+	Exemptions{"spec": "frozen", "active-spec": "frozen"}.Annotate(views)
+
+	if views[0].Exemption != "frozen" {
+		t.Errorf("stale exempt repo Exemption = %q, want %q", views[0].Exemption, "frozen")
+	}
+	if views[1].Exemption != "" || views[2].Exemption != "" {
+		t.Errorf("Annotate() marked a repo that is not exempt and stale: %+v", views)
+	}
+}
+
+func TestExemptionsExclude(t *testing.T) {
+	repos := []Repository{
+		{Name: "spec", FullName: "org/spec"},
+		{Name: "api", FullName: "org/api"},
+		{Name: "legacy", FullName: "other/legacy"},
+	}
+
+	got := Exemptions{"spec": "frozen", "other/legacy": "reference"}.Exclude(repos)
+	if len(got) != 1 || got[0].Name != "api" {
+		t.Errorf("Exclude() = %+v, want only api", got)
+	}
+}
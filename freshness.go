@@ -2,6 +2,7 @@ package patina
 
 import (
 	"fmt"
+	"math"
 	"time"
 )
 
@@ -12,28 +13,242 @@ const (
 	FreshnessGreen  Freshness = "green"
 	FreshnessYellow Freshness = "yellow"
 	FreshnessRed    Freshness = "red"
+
+	// FreshnessArchived marks an archived repository, which is neither fresh nor stale
+	FreshnessArchived Freshness = "archived"
 )
 
+// freshnessRank orders the freshness levels from fresh to stale.
+var freshnessRank = map[Freshness]int{
+	FreshnessGreen:  1,
+	FreshnessYellow: 2,
+	FreshnessRed:    3,
+}
+
+// AtLeast reports whether f is at least as stale as other, ordering the levels
+// green < yellow < red. Archived repositories are outside the ordering, so
+// AtLeast is false if either level is archived.
+func (f Freshness) AtLeast(other Freshness) bool {
+	rank, otherRank := freshnessRank[f], freshnessRank[other]
+	return rank > 0 && otherRank > 0 && rank >= otherRank
+}
+
 const (
-	yellowThreshold = 2 * 30 * 24 * time.Hour  // ~2 months
-	redThreshold    = 6 * 30 * 24 * time.Hour  // ~6 months
+	daysPerMonth = 30 // Length of a month unless FreshnessOptions.CalendarMonths is set
+	yellowMonths = 2
+	redMonths    = 6
+)
+
+// FreshnessOptions controls how ages are counted when classifying freshness
+// and describing ages. The zero value treats every month as 30 days and every
+// day as 24 hours; it is what the package-level functions, such as
+// CalculateFreshness and Age, use.
+type FreshnessOptions struct {
+	// CalendarMonths counts calendar months with time.AddDate, so "1 year ago"
+	// falls on the anniversary of the last update
+	CalendarMonths bool
+
+	// CalendarDays makes Age count days by calendar date in now's location,
+	// so "today" means the same date as now and "1 day ago" the date before
+	CalendarDays bool
+}
+
+// Palette selects the colours and emoji used to show freshness.
+type Palette string
+
+const (
+	PaletteDefault Palette = "default" // Green, yellow, and red
+
+	// PaletteColourBlind uses blue, orange, and purple, which remain distinct
+	// with the common forms of colour blindness
+	PaletteColourBlind Palette = "colorblind"
 )
 
+// ParsePalette converts a string to a Palette value.
+func ParsePalette(s string) (Palette, bool) {
+	switch s {
+	case "default":
+		return PaletteDefault, true
+	case "colorblind", "colourblind":
+		return PaletteColourBlind, true
+	default:
+		return "", false
+	}
+}
+
+// monthsAfter returns the time n months after t, using the month length
+// selected by CalendarMonths.
+func (o FreshnessOptions) monthsAfter(t time.Time, n int) time.Time {
+	if o.CalendarMonths {
+		return t.AddDate(0, n, 0)
+	}
+	return t.Add(time.Duration(n*daysPerMonth) * 24 * time.Hour)
+}
+
+// monthsBetween returns the number of whole months from start to end.
+func (o FreshnessOptions) monthsBetween(start, end time.Time) int {
+	if !o.CalendarMonths {
+		return int(end.Sub(start).Hours()/24) / daysPerMonth
+	}
+
+	months := (end.Year()-start.Year())*12 + int(end.Month()-start.Month())
+	for months > 0 && start.AddDate(0, months, 0).After(end) {
+		months--
+	}
+	return max(months, 0)
+}
+
+// FreshnessThresholds returns the ages, in months, after which a repository
+// becomes yellow and red.
+func FreshnessThresholds() (yellow, red int) {
+	return yellowMonths, redMonths
+}
+
 // CalculateFreshness determines the freshness level based on the last update time.
 func CalculateFreshness(lastUpdated time.Time, now time.Time) Freshness {
-	age := now.Sub(lastUpdated)
+	return FreshnessOptions{}.Calculate(lastUpdated, now)
+}
 
-	if age > redThreshold {
+// Calculate determines the freshness level based on the last update time.
+func (o FreshnessOptions) Calculate(lastUpdated time.Time, now time.Time) Freshness {
+	if now.After(o.monthsAfter(lastUpdated, redMonths)) {
 		return FreshnessRed
 	}
-	if age > yellowThreshold {
+	if now.After(o.monthsAfter(lastUpdated, yellowMonths)) {
 		return FreshnessYellow
 	}
 	return FreshnessGreen
 }
 
-// FreshnessColour returns the ANSI colour code for terminal output.
+// RepositoryFreshness classifies a repository, returning FreshnessArchived for
+// archived repositories and the freshness of its last update otherwise.
+func RepositoryFreshness(repo Repository, now time.Time) Freshness {
+	return FreshnessOptions{}.RepositoryFreshness(repo, now)
+}
+
+// RepositoryFreshness classifies a repository like the package-level
+// RepositoryFreshness, counting ages with these options.
+func (o FreshnessOptions) RepositoryFreshness(repo Repository, now time.Time) Freshness {
+	if repo.Archived {
+		return FreshnessArchived
+	}
+	return o.Calculate(repo.LastUpdated, now)
+}
+
+// DaysUntilYellow returns how many days remain before a repository turns
+// yellow, counting part of a day as a whole one, or 0 once it has.
+func DaysUntilYellow(repo Repository, now time.Time) int {
+	return FreshnessOptions{}.DaysUntilYellow(repo, now)
+}
+
+// DaysUntilYellow returns how many days remain before a repository turns yellow.
+func (o FreshnessOptions) DaysUntilYellow(repo Repository, now time.Time) int {
+	return daysUntil(o.monthsAfter(repo.LastUpdated, yellowMonths), now)
+}
+
+// DaysUntilRed returns how many days remain before a repository turns red,
+// counting part of a day as a whole one, or 0 once it has.
+func DaysUntilRed(repo Repository, now time.Time) int {
+	return FreshnessOptions{}.DaysUntilRed(repo, now)
+}
+
+// DaysUntilRed returns how many days remain before a repository turns red.
+func (o FreshnessOptions) DaysUntilRed(repo Repository, now time.Time) int {
+	return daysUntil(o.monthsAfter(repo.LastUpdated, redMonths), now)
+}
+
+// daysUntil returns the days from now until t, rounded up, or 0 if t has passed.
+func daysUntil(t, now time.Time) int {
+	if !t.After(now) {
+		return 0
+	}
+	return int(math.Ceil(t.Sub(now).Hours() / 24))
+}
+
+// Countdown describes how long a repository has before it reaches the next
+// freshness level, such as "12 days until red". It is empty for red and
+// archived repositories.
+func Countdown(repo Repository, now time.Time) string {
+	return FreshnessOptions{}.Countdown(repo, now)
+}
+
+// Countdown describes how long a repository has before it reaches the next
+// freshness level, counting ages with these options.
+func (o FreshnessOptions) Countdown(repo Repository, now time.Time) string {
+	switch o.RepositoryFreshness(repo, now) {
+	case FreshnessGreen:
+		return Pluralize(o.DaysUntilYellow(repo, now), "day") + " until yellow"
+	case FreshnessYellow:
+		return Pluralize(o.DaysUntilRed(repo, now), "day") + " until red"
+	}
+	return ""
+}
+
+// FreshnessExplanation describes how a freshness level was determined.
+type FreshnessExplanation struct {
+	LastUpdated     time.Time
+	AgeDays         float64
+	Freshness       Freshness
+	YellowThreshold time.Duration
+	RedThreshold    time.Duration
+	Reason          string
+}
+
+// ExplainFreshness calculates the freshness level and explains which threshold
+// boundaries the age falls between.
+func ExplainFreshness(lastUpdated time.Time, now time.Time) FreshnessExplanation {
+	return FreshnessOptions{}.Explain(lastUpdated, now)
+}
+
+// Explain calculates the freshness level like ExplainFreshness, counting ages
+// with these options.
+func (o FreshnessOptions) Explain(lastUpdated time.Time, now time.Time) FreshnessExplanation {
+	ageDays := now.Sub(lastUpdated).Hours() / 24
+	yellowThreshold := o.monthsAfter(lastUpdated, yellowMonths).Sub(lastUpdated)
+	redThreshold := o.monthsAfter(lastUpdated, redMonths).Sub(lastUpdated)
+	yellowDays := int(yellowThreshold.Hours() / 24)
+	redDays := int(redThreshold.Hours() / 24)
+
+	freshness := o.Calculate(lastUpdated, now)
+
+	var reason string
+	switch freshness {
+	case FreshnessRed:
+		reason = fmt.Sprintf("%.1f days > %d-day red threshold", ageDays, redDays)
+	case FreshnessYellow:
+		reason = fmt.Sprintf("%.1f days > %d-day yellow threshold and <= %d-day red threshold", ageDays, yellowDays, redDays)
+	default:
+		reason = fmt.Sprintf("%.1f days <= %d-day yellow threshold", ageDays, yellowDays)
+	}
+
+	return FreshnessExplanation{
+		LastUpdated:     lastUpdated,
+		AgeDays:         ageDays,
+		Freshness:       freshness,
+		YellowThreshold: yellowThreshold,
+		RedThreshold:    redThreshold,
+		Reason:          reason,
+	}
+}
+
+// Colour returns the ANSI colour code for terminal output in the default palette.
 func (f Freshness) Colour() string {
+	return PaletteDefault.Colour(f)
+}
+
+// Colour returns the ANSI colour code for a freshness level in the palette.
+func (p Palette) Colour(f Freshness) string {
+	if p == PaletteColourBlind {
+		switch f {
+		case FreshnessGreen:
+			return "\033[34m" // Blue
+		case FreshnessYellow:
+			return "\033[38;5;208m" // Orange
+		case FreshnessRed:
+			return "\033[35m" // Purple
+		}
+	}
+
 	switch f {
 	case FreshnessGreen:
 		return "\033[32m" // Green
@@ -41,6 +256,8 @@ func (f Freshness) Colour() string {
 		return "\033[33m" // Yellow
 	case FreshnessRed:
 		return "\033[31m" // Red
+	case FreshnessArchived:
+		return "\033[90m" // Grey
 	default:
 		return "\033[0m" // Reset
 	}
@@ -51,8 +268,24 @@ func ColourReset() string {
 	return "\033[0m"
 }
 
-// Emoji returns the emoji indicator for the freshness level.
+// Emoji returns the emoji indicator for the freshness level in the default palette.
 func (f Freshness) Emoji() string {
+	return PaletteDefault.Emoji(f)
+}
+
+// Emoji returns the emoji indicator for a freshness level in the palette.
+func (p Palette) Emoji(f Freshness) string {
+	if p == PaletteColourBlind {
+		switch f {
+		case FreshnessGreen:
+			return "🔵"
+		case FreshnessYellow:
+			return "🟠"
+		case FreshnessRed:
+			return "🟣"
+		}
+	}
+
 	switch f {
 	case FreshnessGreen:
 		return "🟢"
@@ -60,11 +293,43 @@ func (f Freshness) Emoji() string {
 		return "🟡"
 	case FreshnessRed:
 		return "🔴"
+	case FreshnessArchived:
+		return "🔒"
 	default:
 		return "⚪"
 	}
 }
 
+// ASCII returns a plain-text marker for terminals that cannot render emoji.
+func (f Freshness) ASCII() string {
+	switch f {
+	case FreshnessGreen:
+		return "[G]"
+	case FreshnessYellow:
+		return "[Y]"
+	case FreshnessRed:
+		return "[R]"
+	case FreshnessArchived:
+		return "[A]"
+	default:
+		return "[?]"
+	}
+}
+
+// Symbol returns the emoji indicator, or the ASCII marker if emoji is false.
+func (f Freshness) Symbol(emoji bool) string {
+	return PaletteDefault.Symbol(f, emoji)
+}
+
+// Symbol returns the palette's emoji indicator for a freshness level, or the
+// ASCII marker if emoji is false.
+func (p Palette) Symbol(f Freshness, emoji bool) string {
+	if emoji {
+		return p.Emoji(f)
+	}
+	return f.ASCII()
+}
+
 // String returns the string representation of freshness.
 func (f Freshness) String() string {
 	return string(f)
@@ -84,35 +349,67 @@ func ParseFreshness(s string) (Freshness, bool) {
 	}
 }
 
-// Age returns a human-readable age string.
+// Age returns a human-readable age string. Months are counted the same way
+// as for CalculateFreshness, so the age never contradicts the freshness level.
 func Age(lastUpdated time.Time, now time.Time) string {
-	duration := now.Sub(lastUpdated)
+	return FreshnessOptions{}.Age(lastUpdated, now)
+}
 
-	days := int(duration.Hours() / 24)
+// Age returns a human-readable age string, counting months the same way as
+// Calculate with these options.
+func (o FreshnessOptions) Age(lastUpdated time.Time, now time.Time) string {
+	days := o.daysBetween(lastUpdated, now)
 	if days < 1 {
 		return "today"
 	}
 	if days == 1 {
 		return "1 day ago"
 	}
-	if days < 30 {
-		return pluralize(days, "day") + " ago"
-	}
 
-	months := days / 30
+	months := o.ageMonths(lastUpdated, now)
+	if months == 0 {
+		return Pluralize(days, "day") + " ago"
+	}
 	if months < 12 {
-		return pluralize(months, "month") + " ago"
+		return Pluralize(months, "month") + " ago"
 	}
 
 	years := months / 12
 	remainingMonths := months % 12
 	if remainingMonths == 0 {
-		return pluralize(years, "year") + " ago"
+		return Pluralize(years, "year") + " ago"
+	}
+	return Pluralize(years, "year") + ", " + Pluralize(remainingMonths, "month") + " ago"
+}
+
+// ageMonths returns the whole months Age reports from start to end. Calculate
+// only moves past a threshold once it is exceeded, so an age exactly on one
+// counts as the month below to match the colour.
+func (o FreshnessOptions) ageMonths(start, end time.Time) int {
+	months := o.monthsBetween(start, end)
+	if (months == yellowMonths || months == redMonths) && !end.After(o.monthsAfter(start, months)) {
+		months--
+	}
+	return months
+}
+
+// daysBetween returns the number of days from start to end, counting whole
+// 24-hour periods or, if CalendarDays is set, changes of calendar date.
+func (o FreshnessOptions) daysBetween(start, end time.Time) int {
+	if !o.CalendarDays {
+		return int(end.Sub(start).Hours() / 24)
 	}
-	return pluralize(years, "year") + ", " + pluralize(remainingMonths, "month") + " ago"
+
+	start = start.In(end.Location())
+	// Compare the dates at UTC midnight so daylight saving changes cannot
+	// make a day shorter or longer than 24 hours
+	from := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+	to := time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, time.UTC)
+	return int(to.Sub(from).Hours() / 24)
 }
 
-func pluralize(n int, unit string) string {
+// Pluralize returns a count followed by a unit, adding an s unless the count is 1.
+func Pluralize(n int, unit string) string {
 	if n == 1 {
 		return "1 " + unit
 	}
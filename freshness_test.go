@@ -75,6 +75,109 @@ func TestCalculateFreshness(t *testing.T) {
 	}
 }
 
+func TestRepositoryFreshness(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	old := now.AddDate(-1, 0, 0)
+
+	if got := RepositoryFreshness(Repository{LastUpdated: old}, now); got != FreshnessRed {
+		t.Errorf("RepositoryFreshness() = %s, want red", got)
+	}
+	if got := RepositoryFreshness(Repository{LastUpdated: old, Archived: true}, now); got != FreshnessArchived {
+		t.Errorf("RepositoryFreshness() for archived = %s, want archived", got)
+	}
+}
+
+func TestDaysUntil(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	tests := []struct {
+		name       string
+		age        time.Duration
+		wantYellow int
+		wantRed    int
+		want       string
+	}{
+		{"green", 10 * day, 50, 170, "50 days until yellow"},
+		{"green on the last day", 59*day + 12*time.Hour, 1, 121, "1 day until yellow"},
+		{"yellow", 168 * day, 0, 12, "12 days until red"},
+		{"yellow with part of a day left", 168*day + 12*time.Hour, 0, 12, "12 days until red"},
+		{"red", 200 * day, 0, 0, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := Repository{LastUpdated: now.Add(-tt.age)}
+			if got := DaysUntilYellow(repo, now); got != tt.wantYellow {
+				t.Errorf("DaysUntilYellow() = %d, want %d", got, tt.wantYellow)
+			}
+			if got := DaysUntilRed(repo, now); got != tt.wantRed {
+				t.Errorf("DaysUntilRed() = %d, want %d", got, tt.wantRed)
+			}
+			if got := Countdown(repo, now); got != tt.want {
+				t.Errorf("Countdown() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if got := Countdown(Repository{LastUpdated: now.AddDate(0, 0, -10), Archived: true}, now); got != "" {
+		t.Errorf("Countdown() = %q for an archived repo, want empty", got)
+	}
+
+	calendar := FreshnessOptions{CalendarMonths: true}
+	if got := calendar.DaysUntilRed(Repository{LastUpdated: now.AddDate(0, -5, 0)}, now); got != 30 {
+		t.Errorf("DaysUntilRed() with calendar months = %d, want 30 (June 15 to July 15)", got)
+	}
+}
+
+func TestFreshnessAtLeast(t *testing.T) {
+	tests := []struct {
+		f, other Freshness
+		want     bool
+	}{
+		{FreshnessRed, FreshnessYellow, true},
+		{FreshnessYellow, FreshnessYellow, true},
+		{FreshnessGreen, FreshnessYellow, false},
+		{FreshnessGreen, FreshnessGreen, true},
+		{FreshnessYellow, FreshnessRed, false},
+		{FreshnessArchived, FreshnessGreen, false},
+		{FreshnessRed, FreshnessArchived, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.f.AtLeast(tt.other); got != tt.want {
+			t.Errorf("%s.AtLeast(%s) = %t, want %t", tt.f, tt.other, got, tt.want)
+		}
+	}
+}
+
+func TestExplainFreshness(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		lastUpdated time.Time
+		want        Freshness
+		wantReason  string
+	}{
+		{"green", now.AddDate(0, 0, -30), FreshnessGreen, "30.0 days <= 60-day yellow threshold"},
+		{"yellow", now.AddDate(0, 0, -90), FreshnessYellow, "90.0 days > 60-day yellow threshold and <= 180-day red threshold"},
+		{"red", now.AddDate(0, 0, -181), FreshnessRed, "181.0 days > 180-day red threshold"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ExplainFreshness(tt.lastUpdated, now)
+			if got.Freshness != tt.want {
+				t.Errorf("Freshness = %v, want %v", got.Freshness, tt.want)
+			}
+			if got.Reason != tt.wantReason {
+				t.Errorf("Reason = %q, want %q", got.Reason, tt.wantReason)
+			}
+		})
+	}
+}
+
 func TestFreshnessColour(t *testing.T) {
 	tests := []struct {
 		freshness Freshness
@@ -83,6 +186,7 @@ func TestFreshnessColour(t *testing.T) {
 		{FreshnessGreen, "\033[32m"},
 		{FreshnessYellow, "\033[33m"},
 		{FreshnessRed, "\033[31m"},
+		{FreshnessArchived, "\033[90m"},
 		{Freshness("unknown"), "\033[0m"},
 	}
 
@@ -104,6 +208,7 @@ func TestFreshnessEmoji(t *testing.T) {
 		{FreshnessGreen, "🟢"},
 		{FreshnessYellow, "🟡"},
 		{FreshnessRed, "🔴"},
+		{FreshnessArchived, "🔒"},
 		{Freshness("unknown"), "⚪"},
 	}
 
@@ -117,6 +222,72 @@ func TestFreshnessEmoji(t *testing.T) {
 	}
 }
 
+func TestColourBlindPalette(t *testing.T) {
+	tests := []struct {
+		freshness Freshness
+		colour    string
+		emoji     string
+	}{
+		{FreshnessGreen, "\033[34m", "🔵"},
+		{FreshnessYellow, "\033[38;5;208m", "🟠"},
+		{FreshnessRed, "\033[35m", "🟣"},
+		{FreshnessArchived, "\033[90m", "🔒"},
+	}
+
+	for _, tt := range tests {
+		if got := PaletteColourBlind.Colour(tt.freshness); got != tt.colour {
+			t.Errorf("Colour(%s) = %q, want %q", tt.freshness, got, tt.colour)
+		}
+		if got := PaletteColourBlind.Emoji(tt.freshness); got != tt.emoji {
+			t.Errorf("Emoji(%s) = %v, want %v", tt.freshness, got, tt.emoji)
+		}
+	}
+}
+
+func TestParsePalette(t *testing.T) {
+	tests := []struct {
+		input  string
+		want   Palette
+		wantOk bool
+	}{
+		{"default", PaletteDefault, true},
+		{"colorblind", PaletteColourBlind, true},
+		{"colourblind", PaletteColourBlind, true},
+		{"rainbow", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := ParsePalette(tt.input)
+		if got != tt.want || ok != tt.wantOk {
+			t.Errorf("ParsePalette(%q) = %q, %t; want %q, %t", tt.input, got, ok, tt.want, tt.wantOk)
+		}
+	}
+}
+
+func TestFreshnessSymbol(t *testing.T) {
+	tests := []struct {
+		freshness Freshness
+		emoji     bool
+		want      string
+	}{
+		{FreshnessGreen, true, "🟢"},
+		{FreshnessGreen, false, "[G]"},
+		{FreshnessYellow, false, "[Y]"},
+		{FreshnessRed, false, "[R]"},
+		{FreshnessArchived, false, "[A]"},
+		{Freshness("unknown"), false, "[?]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			got := tt.freshness.Symbol(tt.emoji)
+			if got != tt.want {
+				t.Errorf("Symbol(%v) = %v, want %v", tt.emoji, got, tt.want)
+			}
+		})
+	}
+}
+
 func TestParseFreshness(t *testing.T) {
 	tests := []struct {
 		input   string
@@ -175,8 +346,13 @@ func TestAge(t *testing.T) {
 			want:        "1 month ago",
 		},
 		{
-			name:        "60 days ago is 2 months",
+			name:        "60 days ago is still 1 month",
 			lastUpdated: now.AddDate(0, 0, -60),
+			want:        "1 month ago",
+		},
+		{
+			name:        "61 days ago is 2 months",
+			lastUpdated: now.AddDate(0, 0, -61),
 			want:        "2 months ago",
 		},
 		{
@@ -223,3 +399,147 @@ func TestColourReset(t *testing.T) {
 		t.Errorf("ColourReset() = %q, want %q", ColourReset(), "\033[0m")
 	}
 }
+
+func TestAgeCalendarDays(t *testing.T) {
+	loc := time.FixedZone("EST", -5*60*60)
+	pushed := time.Date(2024, 6, 14, 23, 0, 0, 0, loc) // 11pm yesterday
+
+	tests := []struct {
+		now      time.Time
+		hours    string
+		calendar string
+	}{
+		{time.Date(2024, 6, 14, 23, 59, 0, 0, loc), "today", "today"},
+		{time.Date(2024, 6, 15, 0, 0, 0, 0, loc), "today", "1 day ago"},
+		{time.Date(2024, 6, 15, 1, 0, 0, 0, loc), "today", "1 day ago"},
+		{time.Date(2024, 6, 15, 22, 59, 0, 0, loc), "today", "1 day ago"},
+		{time.Date(2024, 6, 15, 23, 0, 0, 0, loc), "1 day ago", "1 day ago"},
+		{time.Date(2024, 6, 16, 0, 30, 0, 0, loc), "1 day ago", "2 days ago"},
+	}
+
+	calendar := FreshnessOptions{CalendarDays: true}
+	for _, tt := range tests {
+		if got := Age(pushed, tt.now); got != tt.hours {
+			t.Errorf("Age(at %s) = %q, want %q", tt.now.Format("Jan 2 15:04"), got, tt.hours)
+		}
+		if got := calendar.Age(pushed, tt.now); got != tt.calendar {
+			t.Errorf("calendar days: Age(at %s) = %q, want %q", tt.now.Format("Jan 2 15:04"), got, tt.calendar)
+		}
+	}
+
+	// Dates are those of now's location, not the timestamp's
+	if got := calendar.Age(pushed.UTC(), time.Date(2024, 6, 15, 1, 0, 0, 0, loc)); got != "1 day ago" {
+		t.Errorf("calendar days: Age(UTC timestamp) = %q, want %q", got, "1 day ago")
+	}
+}
+
+func TestCalendarMonths(t *testing.T) {
+	calendar := FreshnessOptions{CalendarMonths: true}
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+
+	ageTests := []struct {
+		lastUpdated time.Time
+		want        string
+	}{
+		{now.AddDate(0, 0, -30), "30 days ago"},
+		{now.AddDate(0, -1, 0), "1 month ago"},
+		{now.AddDate(0, 0, -364), "11 months ago"},
+		{now.AddDate(-1, 0, 0), "1 year ago"},
+		{now.AddDate(-1, -1, 0), "1 year, 1 month ago"},
+		{now.AddDate(-2, 0, 1), "1 year, 11 months ago"},
+	}
+	for _, tt := range ageTests {
+		if got := calendar.Age(tt.lastUpdated, now); got != tt.want {
+			t.Errorf("Age(%s) = %q, want %q", tt.lastUpdated.Format("2006-01-02"), got, tt.want)
+		}
+	}
+
+	freshnessTests := []struct {
+		lastUpdated time.Time
+		want        Freshness
+	}{
+		{now.AddDate(0, -2, 0), FreshnessGreen},
+		{now.AddDate(0, -2, -1), FreshnessYellow},
+		{now.AddDate(0, -6, 0), FreshnessYellow},
+		{now.AddDate(0, -6, -1), FreshnessRed},
+	}
+	for _, tt := range freshnessTests {
+		if got := calendar.Calculate(tt.lastUpdated, now); got != tt.want {
+			t.Errorf("CalculateFreshness(%s) = %v, want %v", tt.lastUpdated.Format("2006-01-02"), got, tt.want)
+		}
+	}
+
+	// Thresholds follow the calendar: 14 December to 14 June is 183 days
+	explanation := calendar.Explain(now.AddDate(0, -6, -1), now)
+	if got := int(explanation.RedThreshold.Hours() / 24); got != 183 {
+		t.Errorf("RedThreshold = %d days, want 183", got)
+	}
+}
+
+func TestAgeMatchesFreshness(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+
+	// The displayed age must never contradict the colour at the boundaries
+	boundaryTests := []struct {
+		days      int
+		age       string
+		freshness Freshness
+	}{
+		{29, "29 days ago", FreshnessGreen},
+		{30, "1 month ago", FreshnessGreen},
+		{59, "1 month ago", FreshnessGreen},
+		{60, "1 month ago", FreshnessGreen},
+		{61, "2 months ago", FreshnessYellow},
+		{179, "5 months ago", FreshnessYellow},
+		{180, "5 months ago", FreshnessYellow},
+		{181, "6 months ago", FreshnessRed},
+	}
+	for _, tt := range boundaryTests {
+		lastUpdated := now.AddDate(0, 0, -tt.days)
+		if got := Age(lastUpdated, now); got != tt.age {
+			t.Errorf("Age(%d days) = %q, want %q", tt.days, got, tt.age)
+		}
+		if got := CalculateFreshness(lastUpdated, now); got != tt.freshness {
+			t.Errorf("CalculateFreshness(%d days) = %v, want %v", tt.days, got, tt.freshness)
+		}
+	}
+
+	// Every age falls within the month range its colour describes, in both month modes
+	for _, calendar := range []bool{false, true} {
+		opts := FreshnessOptions{CalendarMonths: calendar}
+		for days := 0; days <= 800; days++ {
+			lastUpdated := now.AddDate(0, 0, -days)
+			months := opts.ageMonths(lastUpdated, now)
+
+			var ok bool
+			switch opts.Calculate(lastUpdated, now) {
+			case FreshnessGreen:
+				ok = months < yellowMonths
+			case FreshnessYellow:
+				ok = months >= yellowMonths && months < redMonths
+			case FreshnessRed:
+				ok = months >= redMonths
+			}
+			if !ok {
+				t.Errorf("calendar=%t: %d days is %d months (%q) but %v", calendar, days, months, opts.Age(lastUpdated, now), opts.Calculate(lastUpdated, now))
+			}
+		}
+	}
+}
+
+func TestPluralize(t *testing.T) {
+	tests := []struct {
+		n    int
+		want string
+	}{
+		{0, "0 organization caches"},
+		{1, "1 organization cache"},
+		{2, "2 organization caches"},
+	}
+
+	for _, tt := range tests {
+		if got := Pluralize(tt.n, "organization cache"); got != tt.want {
+			t.Errorf("Pluralize(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
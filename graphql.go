@@ -0,0 +1,369 @@
+package patina
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// API backends that list an organization's repositories.
+const (
+	BackendREST    = "rest"
+	BackendGraphQL = "graphql"
+)
+
+// restFallback is implemented by the REST clients a graphqlClient delegates
+// single-repository lookups and changes to, which GraphQL would not make cheaper.
+type restFallback interface {
+	GitHubClient
+	lastCommitFetcher
+	protectionFetcher
+	rateLimitFetcher
+	repositoryFetcher
+	repositoryArchiver
+}
+
+// graphqlClient lists repositories through the GraphQL API, fetching 100
+// repositories per request with cursor pagination, together with each
+// repository's open issue count, topics, and last commit on its default
+// branch, which REST needs an extra request per repository for.
+type graphqlClient struct {
+	url      string
+	token    func() (string, error)
+	executor *requestExecutor
+	rest     restFallback
+}
+
+// NewGraphQLClient creates a client that lists repositories with the GraphQL
+// API. GraphQL requires a token, so a GitHub App or a token for the host must
+// be configured as for NewGitHubClient; the gh CLI is not used.
+func NewGraphQLClient() (GitHubClient, error) {
+	if app := newAppClientFromEnv(); app != nil {
+		return &graphqlClient{
+			url:      graphqlURL(app.baseURL),
+			token:    app.installationToken,
+			executor: app.executor,
+			rest:     app,
+		}, nil
+	}
+
+	tokens := hostTokens(apiHost())
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("the GraphQL API requires %s or a GitHub App to be configured", githubTokenEnv)
+	}
+	return NewGraphQLClientWithTokens(tokens)
+}
+
+// NewGraphQLClientWithTokens creates a GraphQL client for the host named by
+// GH_HOST that sends requests with the given tokens, rotating among them as
+// NewGitHubClientWithTokens does.
+func NewGraphQLClientWithTokens(tokens []string) (GitHubClient, error) {
+	if len(tokens) == 0 {
+		return nil, errors.New("the GraphQL API requires a token")
+	}
+
+	rest := newTokenClient(apiHost(), tokens)
+	return &graphqlClient{
+		url:      graphqlURL(rest.baseURL),
+		token:    func() (string, error) { return rest.token, nil },
+		executor: rest.executor,
+		rest:     rest,
+	}, nil
+}
+
+// graphqlURL returns the GraphQL endpoint for a REST API base URL. GitHub
+// Enterprise Server serves it beside /api/v3 rather than below it.
+func graphqlURL(baseURL string) string {
+	if base, ok := strings.CutSuffix(baseURL, "/api/v3"); ok {
+		return base + "/api/graphql"
+	}
+	return baseURL + "/graphql"
+}
+
+// repositoriesQuery lists one page of an organization's repositories.
+const repositoriesQuery = `query($org: String!, $cursor: String) {
+  organization(login: $org) {
+    repositories(first: 100, after: $cursor) {
+      pageInfo { hasNextPage endCursor }
+      nodes {
+        databaseId
+        name
+        nameWithOwner
+        url
+        sshUrl
+        description
+        pushedAt
+        updatedAt
+        isArchived
+        visibility
+        isFork
+        mirrorUrl
+        diskUsage
+        stargazerCount
+        hasIssuesEnabled
+        hasWikiEnabled
+        hasProjectsEnabled
+        owner { login }
+        licenseInfo { spdxId }
+        primaryLanguage { name }
+        issues(states: OPEN) { totalCount }
+        repositoryTopics(first: 20) { nodes { topic { name } } }
+        defaultBranchRef {
+          name
+          target {
+            ... on Commit {
+              history(first: 1) {
+                nodes { author { name email date user { login } } }
+              }
+            }
+          }
+        }
+      }
+    }
+  }
+}`
+
+// gqlRepo represents the repository data returned by repositoriesQuery.
+type gqlRepo struct {
+	DatabaseID     int64     `json:"databaseId"`
+	Name           string    `json:"name"`
+	NameWithOwner  string    `json:"nameWithOwner"`
+	URL            string    `json:"url"`
+	SSHURL         string    `json:"sshUrl"`
+	Description    string    `json:"description"`
+	PushedAt       time.Time `json:"pushedAt"`
+	UpdatedAt      time.Time `json:"updatedAt"`
+	IsArchived     bool      `json:"isArchived"`
+	Visibility     string    `json:"visibility"` // PUBLIC, PRIVATE, or INTERNAL
+	IsFork         bool      `json:"isFork"`
+	MirrorURL      string    `json:"mirrorUrl"`
+	DiskUsage      int       `json:"diskUsage"` // In kilobytes
+	StargazerCount int       `json:"stargazerCount"`
+	HasIssues      bool      `json:"hasIssuesEnabled"`
+	HasWiki        bool      `json:"hasWikiEnabled"`
+	HasProjects    bool      `json:"hasProjectsEnabled"`
+	Owner          ghOwner   `json:"owner"`
+	LicenseInfo    *struct {
+		SPDXID string `json:"spdxId"`
+	} `json:"licenseInfo"`
+	PrimaryLanguage *struct {
+		Name string `json:"name"`
+	} `json:"primaryLanguage"`
+	Issues struct {
+		TotalCount int `json:"totalCount"`
+	} `json:"issues"`
+	RepositoryTopics struct {
+		Nodes []struct {
+			Topic struct {
+				Name string `json:"name"`
+			} `json:"topic"`
+		} `json:"nodes"`
+	} `json:"repositoryTopics"`
+	DefaultBranchRef *struct {
+		Name   string `json:"name"`
+		Target struct {
+			History struct {
+				Nodes []struct {
+					Author struct {
+						Name  string    `json:"name"`
+						Email string    `json:"email"`
+						Date  time.Time `json:"date"`
+						User  *ghOwner  `json:"user"`
+					} `json:"author"`
+				} `json:"nodes"`
+			} `json:"history"`
+		} `json:"target"`
+	} `json:"defaultBranchRef"`
+}
+
+// toRepository converts the GraphQL representation into a Repository. Unlike
+// REST, GraphQL counts open issues without pull requests.
+func (r gqlRepo) toRepository() Repository {
+	repo := Repository{
+		ID:          r.DatabaseID,
+		Name:        r.Name,
+		FullName:    r.NameWithOwner,
+		LastUpdated: r.PushedAt,
+		PushedAt:    r.PushedAt,
+		UpdatedAt:   r.UpdatedAt,
+		HTMLURL:     r.URL,
+		CloneURL:    r.URL + ".git",
+		SSHURL:      r.SSHURL,
+		Description: r.Description,
+		Owner:       r.Owner.Login,
+		Stars:       r.StargazerCount,
+		OpenIssues:  r.Issues.TotalCount,
+		SizeKB:      r.DiskUsage,
+		Fork:        r.IsFork,
+		MirrorURL:   r.MirrorURL,
+		Archived:    r.IsArchived,
+		Visibility:  strings.ToLower(r.Visibility),
+		NeverPushed: r.PushedAt.IsZero(),
+
+		IssuesDisabled:   !r.HasIssues,
+		WikiDisabled:     !r.HasWiki,
+		ProjectsDisabled: !r.HasProjects,
+	}
+	if r.LicenseInfo != nil {
+		repo.License = r.LicenseInfo.SPDXID
+	}
+	if r.PrimaryLanguage != nil {
+		repo.Language = r.PrimaryLanguage.Name
+	}
+	for _, node := range r.RepositoryTopics.Nodes {
+		repo.Topics = append(repo.Topics, node.Topic.Name)
+	}
+	// The listing asks for the last commit, so a repository without one
+	// needs no separate lookup
+	repo.LastCommitChecked = true
+	if ref := r.DefaultBranchRef; ref != nil {
+		repo.DefaultBranch = ref.Name
+		if commits := ref.Target.History.Nodes; len(commits) > 0 {
+			author := commits[0].Author
+			repo.LastCommitAuthor = author.Name
+			repo.LastCommitEmail = author.Email
+			repo.LastCommitDate = author.Date
+			if author.User != nil && author.User.Login != "" {
+				repo.LastCommitAuthor = author.User.Login
+			}
+		}
+	}
+	return repo
+}
+
+// gqlResponse is the envelope of a repositoriesQuery response.
+type gqlResponse struct {
+	Data struct {
+		Organization *struct {
+			Repositories struct {
+				PageInfo struct {
+					HasNextPage bool   `json:"hasNextPage"`
+					EndCursor   string `json:"endCursor"`
+				} `json:"pageInfo"`
+				Nodes []gqlRepo `json:"nodes"`
+			} `json:"repositories"`
+		} `json:"organization"`
+	} `json:"data"`
+	Errors []struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// FetchRepositories retrieves all repositories using the GraphQL API.
+func (c *graphqlClient) FetchRepositories(org string) ([]Repository, error) {
+	var allRepos []Repository
+	var cursor *string
+	pages := 0
+	start := time.Now()
+
+	for {
+		pages++
+		page, err := c.query(org, cursor)
+		if err != nil {
+			return nil, err
+		}
+
+		repos := page.Data.Organization.Repositories
+		for _, repo := range repos.Nodes {
+			allRepos = append(allRepos, repo.toRepository())
+		}
+
+		if !repos.PageInfo.HasNextPage {
+			break
+		}
+		cursor = &repos.PageInfo.EndCursor
+	}
+
+	slog.Debug("fetched repositories", "org", org, "api", BackendGraphQL, "pages", pages,
+		"repos", len(allRepos), "duration", time.Since(start))
+
+	return allRepos, nil
+}
+
+// query requests one page of repositories after cursor, or the first page if it is nil.
+func (c *graphqlClient) query(org string, cursor *string) (gqlResponse, error) {
+	var page gqlResponse
+
+	token, err := c.token()
+	if err != nil {
+		return page, err
+	}
+
+	payload, err := json.Marshal(map[string]any{
+		"query":     repositoriesQuery,
+		"variables": map[string]any{"org": org, "cursor": cursor},
+	})
+	if err != nil {
+		return page, fmt.Errorf("failed to encode query: %w", err)
+	}
+
+	slog.Debug("requesting repositories", "url", c.url, "org", org, "cursor", cursor)
+
+	req, err := http.NewRequest("POST", c.url, bytes.NewReader(payload))
+	if err != nil {
+		return page, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := c.executor.Do(req)
+	if err != nil {
+		return page, fmt.Errorf("failed to fetch repositories: %w", err)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return page, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return page, apiError(org, resp.StatusCode, body)
+	}
+
+	if err := json.Unmarshal(body, &page); err != nil {
+		return page, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	// GraphQL reports failures in the body with status 200
+	if len(page.Errors) > 0 {
+		var messages []string
+		for _, e := range page.Errors {
+			if e.Type == "FORBIDDEN" {
+				return page, noAccessError(org, e.Message)
+			}
+			messages = append(messages, e.Message)
+		}
+		return page, fmt.Errorf("GitHub GraphQL API error: %s", strings.Join(messages, "; "))
+	}
+	if page.Data.Organization == nil {
+		return page, errors.New("GitHub GraphQL API returned no organization")
+	}
+
+	return page, nil
+}
+
+// FetchLastCommit retrieves the last commit of a repository with the REST API.
+// Listing repositories already includes it, so this is only needed for
+// repositories whose default branch had no commits when listed.
+func (c *graphqlClient) FetchLastCommit(fullName string) (LastCommit, error) {
+	return c.rest.FetchLastCommit(fullName)
+}
+
+// FetchProtection looks up whether a branch is protected with the REST API.
+func (c *graphqlClient) FetchProtection(fullName, branch string) (ProtectionStatus, error) {
+	return c.rest.FetchProtection(fullName, branch)
+}
+
+// FetchRepository retrieves a single repository with the REST API.
+func (c *graphqlClient) FetchRepository(fullName string) (Repository, error) {
+	return c.rest.FetchRepository(fullName)
+}
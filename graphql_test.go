@@ -0,0 +1,159 @@
+package patina
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestGraphqlURL(t *testing.T) {
+	tests := []struct {
+		baseURL string
+		want    string
+	}{
+		{"https://api.github.com", "https://api.github.com/graphql"},
+		{"https://api.acme.ghe.com", "https://api.acme.ghe.com/graphql"},
+		{"https://github.example.com/api/v3", "https://github.example.com/api/graphql"},
+	}
+
+	for _, tt := range tests {
+		if got := graphqlURL(tt.baseURL); got != tt.want {
+			t.Errorf("graphqlURL(%q) = %q, want %q", tt.baseURL, got, tt.want)
+		}
+	}
+}
+
+func TestGraphQLClientFetchRepositories(t *testing.T) {
+	pushed := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Method != "POST" || r.Header.Get("Authorization") != "Bearer ghp_token" {
+			t.Errorf("request = %s with Authorization %q", r.Method, r.Header.Get("Authorization"))
+		}
+
+		var body struct {
+			Variables struct {
+				Org    string  `json:"org"`
+				Cursor *string `json:"cursor"`
+			} `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+
+		if body.Variables.Cursor == nil {
+			fmt.Fprintf(w, `{"data":{"organization":{"repositories":{
+				"pageInfo":{"hasNextPage":true,"endCursor":"c1"},
+				"nodes":[{"databaseId":1,"name":"api","nameWithOwner":"org/api","url":"https://github.com/org/api",
+					"pushedAt":%q,"visibility":"INTERNAL","stargazerCount":5,"hasIssuesEnabled":true,"owner":{"login":"org"},"licenseInfo":{"spdxId":"MIT"},
+					"primaryLanguage":{"name":"Go"},"issues":{"totalCount":3},
+					"repositoryTopics":{"nodes":[{"topic":{"name":"service"}}]},
+					"defaultBranchRef":{"name":"main","target":{"history":{"nodes":[
+						{"author":{"name":"Ada","email":"ada@example.com","date":%q,"user":{"login":"ada"}}}]}}}}]}}}}`,
+				pushed.Format(time.RFC3339), pushed.Format(time.RFC3339))
+			return
+		}
+		if *body.Variables.Cursor != "c1" {
+			t.Errorf("cursor = %q, want c1", *body.Variables.Cursor)
+		}
+		fmt.Fprint(w, `{"data":{"organization":{"repositories":{
+			"pageInfo":{"hasNextPage":false,"endCursor":"c2"},
+			"nodes":[{"databaseId":2,"name":"empty","nameWithOwner":"org/empty","url":"https://github.com/org/empty",
+				"pushedAt":null,"owner":{"login":"org"},"defaultBranchRef":null}]}}}}`)
+	}))
+	defer server.Close()
+
+	client := &graphqlClient{
+		url:      server.URL,
+		token:    func() (string, error) { return "ghp_token", nil },
+		executor: newRequestExecutor(server.Client(), 1),
+	}
+
+	repos, err := client.FetchRepositories("org")
+	if err != nil {
+		t.Fatalf("FetchRepositories() error = %v", err)
+	}
+	if requests != 2 || len(repos) != 2 {
+		t.Fatalf("FetchRepositories() = %d repos in %d requests, want 2 in 2", len(repos), requests)
+	}
+
+	api := repos[0]
+	if api.FullName != "org/api" || !api.LastUpdated.Equal(pushed) || api.CloneURL != "https://github.com/org/api.git" {
+		t.Errorf("repos[0] = %+v", api)
+	}
+	if api.Stars != 5 || api.OpenIssues != 3 || api.License != "MIT" || api.Language != "Go" || api.DefaultBranch != "main" || api.Visibility != "internal" {
+		t.Errorf("repos[0] details = %+v", api)
+	}
+	if api.IssuesDisabled || !api.WikiDisabled {
+		t.Errorf("repos[0] IssuesDisabled, WikiDisabled = %t, %t, want false, true", api.IssuesDisabled, api.WikiDisabled)
+	}
+	if len(api.Topics) != 1 || api.Topics[0] != "service" {
+		t.Errorf("repos[0].Topics = %v, want [service]", api.Topics)
+	}
+	if api.LastCommitAuthor != "ada" || !api.LastCommitDate.Equal(pushed) {
+		t.Errorf("repos[0] last commit = %s at %v, want ada at %v", api.LastCommitAuthor, api.LastCommitDate, pushed)
+	}
+
+	if !repos[1].NeverPushed || repos[1].DefaultBranch != "" || !repos[1].LastCommitChecked {
+		t.Errorf("repos[1] = %+v, want a never-pushed repository with its last commit checked", repos[1])
+	}
+}
+
+func TestGraphQLClientErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		noAuth bool
+	}{
+		{"not found", `{"data":{"organization":null},"errors":[{"type":"NOT_FOUND","message":"Could not resolve to an Organization with the login of 'missing'."}]}`, false},
+		{"forbidden", `{"data":{"organization":null},"errors":[{"type":"FORBIDDEN","message":"Resource protected by organization SAML enforcement."}]}`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, tt.body)
+			}))
+			defer server.Close()
+
+			client := &graphqlClient{
+				url:      server.URL,
+				token:    func() (string, error) { return "ghp_token", nil },
+				executor: newRequestExecutor(server.Client(), 1),
+			}
+
+			_, err := client.FetchRepositories("missing")
+			if err == nil {
+				t.Fatal("FetchRepositories() error = nil, want error")
+			}
+			if got := errors.Is(err, ErrNoAccess); got != tt.noAuth {
+				t.Errorf("errors.Is(err, ErrNoAccess) = %t, want %t (err = %v)", got, tt.noAuth, err)
+			}
+		})
+	}
+}
+
+func TestNewGraphQLClientRequiresToken(t *testing.T) {
+	t.Setenv(githubAppIDEnv, "")
+	t.Setenv(githubTokenEnv, "")
+	t.Setenv(githubHostEnv, "")
+
+	if _, err := NewGraphQLClient(); err == nil {
+		t.Error("NewGraphQLClient() error = nil without a token, want error")
+	}
+
+	t.Setenv(githubTokenEnv, "ghp_token")
+	client, err := NewGraphQLClient()
+	if err != nil {
+		t.Fatalf("NewGraphQLClient() error = %v", err)
+	}
+	if c := client.(*graphqlClient); c.url != "https://api.github.com/graphql" {
+		t.Errorf("url = %q, want https://api.github.com/graphql", c.url)
+	}
+}
@@ -0,0 +1,79 @@
+package patina
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	historyLimit      = 100 // Maximum number of snapshots kept per organization
+	historyFileSuffix = ".history.json"
+)
+
+// HistoryEntry records an organization's freshness counts at the time of a fetch.
+type HistoryEntry struct {
+	Timestamp time.Time `json:"timestamp"`
+	Green     int       `json:"green"`
+	Yellow    int       `json:"yellow"`
+	Red       int       `json:"red"`
+}
+
+// historyFilePath returns the path to the freshness history file for an organization.
+func (c *Cache) historyFilePath(org string) string {
+	return filepath.Join(c.baseDir, cacheKey(org)+historyFileSuffix)
+}
+
+// LoadHistory returns the recorded freshness history for an organization,
+// oldest first. It returns no entries if no history has been recorded.
+func (c *Cache) LoadHistory(org string) ([]HistoryEntry, error) {
+	if err := ValidateOrganization(org); err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(c.historyFilePath(org))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []HistoryEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// AppendHistory adds an entry to an organization's freshness history,
+// dropping the oldest entries beyond the history limit.
+func (c *Cache) AppendHistory(org string, entry HistoryEntry) error {
+	if err := ValidateOrganization(org); err != nil {
+		return err
+	}
+
+	// Hold the lock from read to write so concurrent appends are not lost
+	unlock, err := c.lock(org, true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	entries, err := c.LoadHistory(org)
+	if err != nil {
+		return err
+	}
+
+	entries = append(entries, entry)
+	if len(entries) > historyLimit {
+		entries = entries[len(entries)-historyLimit:]
+	}
+
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(c.historyFilePath(org), data, 0644)
+}
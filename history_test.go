@@ -0,0 +1,96 @@
+package patina
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLoadHistoryMissing(t *testing.T) {
+	cache := NewCacheWithDir(t.TempDir())
+
+	entries, err := cache.LoadHistory("test-org")
+	if err != nil {
+		t.Fatalf("LoadHistory() error = %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("len(entries) = %d, want 0", len(entries))
+	}
+}
+
+func TestAppendHistory(t *testing.T) {
+	cache := NewCacheWithDir(t.TempDir())
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	for i := 0; i < historyLimit+5; i++ {
+		entry := HistoryEntry{Timestamp: now.AddDate(0, 0, i), Red: i}
+		if err := cache.AppendHistory("test-org", entry); err != nil {
+			t.Fatalf("AppendHistory() error = %v", err)
+		}
+	}
+
+	entries, err := cache.LoadHistory("test-org")
+	if err != nil {
+		t.Fatalf("LoadHistory() error = %v", err)
+	}
+	if len(entries) != historyLimit {
+		t.Fatalf("len(entries) = %d, want %d", len(entries), historyLimit)
+	}
+	if entries[0].Red != 5 {
+		t.Errorf("entries[0].Red = %d, want 5 (oldest entries dropped)", entries[0].Red)
+	}
+	if last := entries[len(entries)-1]; last.Red != historyLimit+4 || !last.Timestamp.Equal(now.AddDate(0, 0, historyLimit+4)) {
+		t.Errorf("last entry = %+v, want most recent", last)
+	}
+}
+
+func TestScannerRecordsHistoryOnFetch(t *testing.T) {
+	now := time.Now()
+	cache := NewCacheWithDir(t.TempDir())
+	client := &mockGitHubClient{
+		repos: []Repository{
+			{Name: "active", LastUpdated: now.AddDate(0, 0, -10)},
+			{Name: "stale", LastUpdated: now.AddDate(-1, 0, 0)},
+		},
+	}
+	scanner := NewScannerWithDeps(client, cache)
+
+	// First scan fetches and records; second scan hits the cache and does not
+	for i := 0; i < 2; i++ {
+		if _, err := scanner.Scan("test-org", ScanOptions{}); err != nil {
+			t.Fatalf("Scan() error = %v", err)
+		}
+	}
+
+	entries, err := scanner.History("test-org")
+	if err != nil {
+		t.Fatalf("History() error = %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("len(entries) = %d, want 1", len(entries))
+	}
+	if entries[0].Green != 1 || entries[0].Yellow != 0 || entries[0].Red != 1 {
+		t.Errorf("entry = %+v, want green 1, yellow 0, red 1", entries[0])
+	}
+}
+
+func TestScannerRecordsHistoryWithFreshnessOptions(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	cache := NewCacheWithDir(t.TempDir())
+	cache.SetClock(func() time.Time { return now })
+	// 181 days old: red in 30-day months, but not yet six calendar months
+	client := &mockGitHubClient{repos: []Repository{{Name: "aging", LastUpdated: time.Date(2023, 9, 2, 12, 0, 0, 0, time.UTC)}}}
+	scanner := NewScannerWithDeps(client, cache)
+	scanner.SetClock(func() time.Time { return now })
+
+	if _, err := scanner.Scan("test-org", ScanOptions{Freshness: FreshnessOptions{CalendarMonths: true}}); err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+
+	entries, err := scanner.History("test-org")
+	if err != nil {
+		t.Fatalf("History() error = %v", err)
+	}
+	if len(entries) != 1 || entries[0].Yellow != 1 || entries[0].Red != 0 {
+		t.Errorf("entries = %+v, want one entry with the repository yellow in calendar months", entries)
+	}
+}
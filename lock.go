@@ -0,0 +1,48 @@
+package patina
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+)
+
+const lockFileSuffix = ".lock"
+
+// lockFilePath returns the path to the lock file guarding an organization's cache files.
+func (c *Cache) lockFilePath(org string) string {
+	return filepath.Join(c.baseDir, cacheKey(org)+lockFileSuffix)
+}
+
+// lock takes an advisory lock on an organization's cache files and returns a
+// function that releases it. Writers take an exclusive lock, so concurrent
+// scans of one organization replace its cache, summary and history one at a
+// time; readers take a shared lock and wait for a write in progress.
+// On platforms without file locking, lock does nothing and writes rely on
+// atomic replacement alone.
+func (c *Cache) lock(org string, exclusive bool) (func(), error) {
+	if exclusive {
+		if err := os.MkdirAll(c.baseDir, 0755); err != nil {
+			return nil, err
+		}
+	}
+
+	f, err := os.OpenFile(c.lockFilePath(org), os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		if !exclusive {
+			// A missing or read-only cache directory has nothing to wait for
+			slog.Debug("reading cache without a lock", "organization", org, "error", err)
+			return func() {}, nil
+		}
+		return nil, fmt.Errorf("failed to open cache lock: %w", err)
+	}
+
+	if err := lockFile(f, exclusive); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to lock cache: %w", err)
+	}
+	return func() {
+		unlockFile(f)
+		f.Close()
+	}, nil
+}
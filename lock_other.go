@@ -0,0 +1,18 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd)
+
+package patina
+
+import "os"
+
+// fileLocking reports whether lock takes real file locks on this platform.
+const fileLocking = false
+
+// lockFile does nothing where flock is unavailable.
+func lockFile(f *os.File, exclusive bool) error {
+	return nil
+}
+
+// unlockFile does nothing where flock is unavailable.
+func unlockFile(f *os.File) error {
+	return nil
+}
@@ -0,0 +1,80 @@
+package patina
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCacheSaveWaitsForLock(t *testing.T) {
+	if !fileLocking {
+		t.Skip("file locking is unavailable on this platform")
+	}
+
+	cache := NewCacheWithDir(t.TempDir())
+	unlock, err := cache.lock("test-org", true)
+	if err != nil {
+		t.Fatalf("lock() error = %v", err)
+	}
+
+	saved := make(chan error)
+	go func() {
+		saved <- cache.Save(OrganizationCache{Organization: "test-org"})
+	}()
+
+	select {
+	case err := <-saved:
+		t.Fatalf("Save() returned %v while another writer held the lock", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	unlock()
+	if err := <-saved; err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	orgs, _, err := cache.LoadAll()
+	if err != nil {
+		t.Fatalf("LoadAll() error = %v", err)
+	}
+	if len(orgs) != 1 {
+		t.Errorf("LoadAll() returned %d organizations, want 1: the lock file is not an organization", len(orgs))
+	}
+}
+
+func TestAppendHistoryConcurrent(t *testing.T) {
+	if !fileLocking {
+		t.Skip("file locking is unavailable on this platform")
+	}
+
+	cache := NewCacheWithDir(t.TempDir())
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+
+	const writers = 10
+	var wg sync.WaitGroup
+	for i := range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := cache.AppendHistory("test-org", HistoryEntry{Timestamp: now.Add(time.Duration(i) * time.Minute)}); err != nil {
+				t.Errorf("AppendHistory() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	entries, err := cache.LoadHistory("test-org")
+	if err != nil {
+		t.Fatalf("LoadHistory() error = %v", err)
+	}
+	if len(entries) != writers {
+		t.Errorf("len(entries) = %d, want %d: concurrent appends were lost", len(entries), writers)
+	}
+}
+
+func TestCacheLoadWithoutCacheDir(t *testing.T) {
+	cache := NewCacheWithDir(t.TempDir() + "/missing")
+	if _, err := cache.Load("test-org"); err != ErrCacheNotFound {
+		t.Errorf("Load() error = %v, want ErrCacheNotFound", err)
+	}
+}
@@ -0,0 +1,54 @@
+package patina
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// MetricsSnapshot is an organization's freshness summary and when its data was fetched.
+type MetricsSnapshot struct {
+	Organization string
+	Summary      FreshnessSummary
+	FetchedAt    time.Time
+}
+
+// labelEscaper escapes label values for the Prometheus text exposition format.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// WritePrometheusMetrics writes the snapshots in the Prometheus text exposition format.
+func WritePrometheusMetrics(w io.Writer, snapshots []MetricsSnapshot) error {
+	var b strings.Builder
+
+	b.WriteString("# HELP patina_repositories_total Number of repositories in the organization.\n")
+	b.WriteString("# TYPE patina_repositories_total gauge\n")
+	for _, s := range snapshots {
+		fmt.Fprintf(&b, "patina_repositories_total{org=\"%s\"} %d\n", labelEscaper.Replace(s.Organization), s.Summary.Total)
+	}
+
+	b.WriteString("# HELP patina_repositories_by_freshness Number of repositories at each freshness level.\n")
+	b.WriteString("# TYPE patina_repositories_by_freshness gauge\n")
+	for _, s := range snapshots {
+		org := labelEscaper.Replace(s.Organization)
+		for _, level := range []struct {
+			freshness Freshness
+			count     int
+		}{
+			{FreshnessGreen, s.Summary.Green},
+			{FreshnessYellow, s.Summary.Yellow},
+			{FreshnessRed, s.Summary.Red},
+		} {
+			fmt.Fprintf(&b, "patina_repositories_by_freshness{org=\"%s\",freshness=\"%s\"} %d\n", org, level.freshness, level.count)
+		}
+	}
+
+	b.WriteString("# HELP patina_scan_timestamp_seconds Unix time the organization's repository data was fetched.\n")
+	b.WriteString("# TYPE patina_scan_timestamp_seconds gauge\n")
+	for _, s := range snapshots {
+		fmt.Fprintf(&b, "patina_scan_timestamp_seconds{org=\"%s\"} %d\n", labelEscaper.Replace(s.Organization), s.FetchedAt.Unix())
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
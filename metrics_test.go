@@ -0,0 +1,51 @@
+package patina
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWritePrometheusMetrics(t *testing.T) {
+	fetchedAt := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	snapshots := []MetricsSnapshot{
+		{Organization: "alpha", Summary: FreshnessSummary{Green: 5, Yellow: 3, Red: 2, Total: 10}, FetchedAt: fetchedAt},
+		{Organization: "beta", Summary: FreshnessSummary{Red: 1, Total: 1}, FetchedAt: fetchedAt},
+	}
+
+	var b strings.Builder
+	if err := WritePrometheusMetrics(&b, snapshots); err != nil {
+		t.Fatalf("WritePrometheusMetrics() error = %v", err)
+	}
+	out := b.String()
+
+	for _, want := range []string{
+		"# TYPE patina_repositories_total gauge\n",
+		`patina_repositories_total{org="alpha"} 10` + "\n",
+		`patina_repositories_total{org="beta"} 1` + "\n",
+		`patina_repositories_by_freshness{org="alpha",freshness="green"} 5` + "\n",
+		`patina_repositories_by_freshness{org="alpha",freshness="yellow"} 3` + "\n",
+		`patina_repositories_by_freshness{org="alpha",freshness="red"} 2` + "\n",
+		`patina_scan_timestamp_seconds{org="alpha"} 1718452800` + "\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q\n%s", want, out)
+		}
+	}
+
+	if n := strings.Count(out, "# TYPE patina_repositories_total"); n != 1 {
+		t.Errorf("TYPE line for patina_repositories_total appears %d times, want 1", n)
+	}
+}
+
+func TestWritePrometheusMetricsEscapesLabels(t *testing.T) {
+	var b strings.Builder
+	snapshots := []MetricsSnapshot{{Organization: "a\"b\\c"}}
+	if err := WritePrometheusMetrics(&b, snapshots); err != nil {
+		t.Fatalf("WritePrometheusMetrics() error = %v", err)
+	}
+
+	if want := `patina_repositories_total{org="a\"b\\c"} 0`; !strings.Contains(b.String(), want) {
+		t.Errorf("output missing %q\n%s", want, b.String())
+	}
+}
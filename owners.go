@@ -0,0 +1,105 @@
+package patina
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// UnownedTeam is the team of repositories missing from an ownership file.
+const UnownedTeam = "unowned"
+
+// Ownership maps repositories to the teams that own them, as tracked outside
+// GitHub. Keys are lower-cased repository names or owner/name full names.
+type Ownership map[string]string
+
+// ReadOwnership reads an ownership file; see ParseOwnership for its format.
+func ReadOwnership(path string) (Ownership, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ownership file: %w", err)
+	}
+	defer f.Close()
+
+	ownership, err := ParseOwnership(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ownership file %s: %w", path, err)
+	}
+	return ownership, nil
+}
+
+// ParseOwnership reads CSV rows of repository and team, where the repository
+// is a name or an owner/name full name. Lines starting with # are ignored, as
+// is a header row whose first field is "repo", "repository", or "name".
+func ParseOwnership(r io.Reader) (Ownership, error) {
+	return parseRepositoryCSV(r, "team")
+}
+
+// parseRepositoryCSV reads CSV rows of a repository and a value, named by
+// field in errors, into a map keyed by the lower-cased repository. Lines
+// starting with # are ignored, as is a header row whose first field is
+// "repo", "repository", or "name".
+func parseRepositoryCSV(r io.Reader, field string) (map[string]string, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	values := make(map[string]string)
+	for first := true; ; first = false {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		repo := strings.ToLower(strings.TrimSpace(record[0]))
+		if first && (repo == "repo" || repo == "repository" || repo == "name") {
+			continue
+		}
+		if len(record) < 2 || repo == "" || strings.TrimSpace(record[1]) == "" {
+			line, _ := reader.FieldPos(0)
+			return nil, fmt.Errorf("line %d: want a repository and a %s", line, field)
+		}
+		values[repo] = strings.TrimSpace(record[1])
+	}
+	return values, nil
+}
+
+// Team returns the team that owns a repository, matching its full name before
+// its name, or UnownedTeam if the ownership file does not list it.
+func (o Ownership) Team(repo Repository) string {
+	if team, ok := o[strings.ToLower(repo.FullName)]; ok && repo.FullName != "" {
+		return team
+	}
+	if team, ok := o[strings.ToLower(repo.Name)]; ok {
+		return team
+	}
+	return UnownedTeam
+}
+
+// Annotate sets the team of each view from the ownership file.
+func (o Ownership) Annotate(views []RepositoryView) {
+	for i := range views {
+		views[i].Team = o.Team(views[i].Repository)
+	}
+}
+
+// SummaryByTeam groups repositories by the team the ownership file assigns them
+// and computes per-team freshness counts. Teams are sorted alphabetically,
+// with the unowned bucket last.
+func SummaryByTeam(repos []Repository, ownership Ownership, now time.Time) []OwnerSummary {
+	return FreshnessOptions{}.SummaryByTeam(repos, ownership, now)
+}
+
+// SummaryByTeam computes per-team freshness counts like the package-level
+// SummaryByTeam, counting ages with these options.
+func (o FreshnessOptions) SummaryByTeam(repos []Repository, ownership Ownership, now time.Time) []OwnerSummary {
+	return o.summaryBy(repos, now, ownership.Team, UnownedTeam)
+}
@@ -0,0 +1,87 @@
+package patina
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseOwnership(t *testing.T) {
+	input := "repo,team\n" +
+		"# Platform repositories\n" +
+		"api, platform\n" +
+		"Other-Org/Tools,devex\n"
+
+	ownership, err := ParseOwnership(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseOwnership() error = %v", err)
+	}
+
+	tests := []struct {
+		repo Repository
+		want string
+	}{
+		{Repository{Name: "api", FullName: "my-org/api"}, "platform"},
+		{Repository{Name: "API", FullName: "my-org/API"}, "platform"},
+		{Repository{Name: "tools", FullName: "other-org/tools"}, "devex"},
+		{Repository{Name: "tools", FullName: "my-org/tools"}, UnownedTeam},
+		{Repository{Name: "web", FullName: "my-org/web"}, UnownedTeam},
+	}
+	for _, tt := range tests {
+		if got := ownership.Team(tt.repo); got != tt.want {
+			t.Errorf("Team(%s) = %q, want %q", tt.repo.FullName, got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"api\n", "api,\n", "repo,team\nweb, \n"} {
+		if _, err := ParseOwnership(strings.NewReader(bad)); err == nil {
+			t.Errorf("ParseOwnership(%q) error = nil, want error", bad)
+		}
+	}
+}
+
+func TestReadOwnership(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "owners.csv")
+	if err := os.WriteFile(path, []byte("api,platform\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ownership, err := ReadOwnership(path)
+	if err != nil || ownership["api"] != "platform" {
+		t.Errorf("ReadOwnership() = %v, %v; want api owned by platform", ownership, err)
+	}
+
+	if _, err := ReadOwnership(filepath.Join(t.TempDir(), "missing.csv")); err == nil {
+		t.Error("ReadOwnership() error = nil for a missing file")
+	}
+}
+
+func TestOwnershipAnnotateAndSummary(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	ownership := Ownership{"api": "platform", "web": "frontend"}
+	repos := []Repository{
+		{Name: "api", LastUpdated: now.AddDate(-1, 0, 0)},
+		{Name: "web", LastUpdated: now.AddDate(0, 0, -5)},
+		{Name: "scratch", LastUpdated: now.AddDate(-2, 0, 0)},
+	}
+
+	views := NewRepositoryViews(repos, now)
+	ownership.Annotate(views)
+	if views[0].Team != "platform" || views[2].Team != UnownedTeam {
+		t.Errorf("Annotate() teams = %q, %q, %q", views[0].Team, views[1].Team, views[2].Team)
+	}
+
+	summaries := SummaryByTeam(repos, ownership, now)
+	var teams []string
+	for _, s := range summaries {
+		teams = append(teams, s.Owner)
+	}
+	if got := strings.Join(teams, ","); got != "frontend,platform,unowned" {
+		t.Errorf("SummaryByTeam() teams = %s, want frontend,platform,unowned", got)
+	}
+	if summaries[1].Summary.Red != 1 {
+		t.Errorf("platform red = %d, want 1", summaries[1].Summary.Red)
+	}
+}
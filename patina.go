@@ -1,14 +1,19 @@
 package patina
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"os"
+	"os/exec"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/cli/go-gh/v2"
@@ -17,6 +22,10 @@ import (
 const (
 	githubAPIBaseURL = "https://api.github.com"
 	githubTokenEnv   = "GITHUB_TOKEN"
+	githubHostEnv    = "GH_HOST" // Same variable the gh CLI uses to select a host
+	githubDotCom     = "github.com"
+
+	defaultConcurrency = 8 // Maximum in-flight API requests per client
 )
 
 // GitHubClient provides methods for fetching GitHub data.
@@ -24,67 +33,403 @@ type GitHubClient interface {
 	FetchRepositories(org string) ([]Repository, error)
 }
 
+// repositoriesPerPage is the page size requested when listing repositories,
+// the largest GitHub allows.
+const repositoriesPerPage = 100
+
+// conditionalFetcher is implemented by clients that can revalidate previously
+// cached pages with ETags instead of downloading them again.
+type conditionalFetcher interface {
+	FetchRepositoriesConditional(org string, previous *OrganizationCache) ([]Repository, []PageETag, error)
+}
+
 // ghRepo represents the repository data returned by the GitHub API.
 type ghRepo struct {
-	Name     string    `json:"name"`
-	FullName string    `json:"full_name"`
-	HTMLURL  string    `json:"html_url"`
-	PushedAt time.Time `json:"pushed_at"`
-	Archived bool      `json:"archived"`
+	ID          int64      `json:"id"`
+	Name        string     `json:"name"`
+	FullName    string     `json:"full_name"`
+	HTMLURL     string     `json:"html_url"`
+	CloneURL    string     `json:"clone_url"`
+	SSHURL      string     `json:"ssh_url"`
+	Description string     `json:"description"`
+	Branch      string     `json:"default_branch"`
+	Visibility  string     `json:"visibility"`
+	PushedAt    time.Time  `json:"pushed_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	Archived    bool       `json:"archived"`
+	Fork        bool       `json:"fork"`
+	MirrorURL   string     `json:"mirror_url"`
+	Owner       *ghOwner   `json:"owner"`
+	License     *ghLicense `json:"license"`
+	Stars       int        `json:"stargazers_count"`
+	Issues      int        `json:"open_issues_count"`
+	Size        int        `json:"size"` // In kilobytes
+	Topics      []string   `json:"topics"`
+	Language    string     `json:"language"`
+	HasIssues   bool       `json:"has_issues"`
+	HasWiki     bool       `json:"has_wiki"`
+	HasProjects bool       `json:"has_projects"`
+}
+
+// ghOwner represents the owner object nested in the GitHub API repository data.
+type ghOwner struct {
+	Login string `json:"login"`
+}
+
+// ghLicense represents the license object nested in the GitHub API repository data.
+type ghLicense struct {
+	SPDXID string `json:"spdx_id"`
+}
+
+// toRepository converts the API representation into a Repository.
+func (r ghRepo) toRepository() Repository {
+	repo := Repository{
+		ID:            r.ID,
+		Name:          r.Name,
+		FullName:      r.FullName,
+		LastUpdated:   r.PushedAt,
+		PushedAt:      r.PushedAt,
+		UpdatedAt:     r.UpdatedAt,
+		HTMLURL:       r.HTMLURL,
+		CloneURL:      r.CloneURL,
+		SSHURL:        r.SSHURL,
+		Description:   r.Description,
+		Stars:         r.Stars,
+		OpenIssues:    r.Issues,
+		SizeKB:        r.Size,
+		Fork:          r.Fork,
+		MirrorURL:     r.MirrorURL,
+		Archived:      r.Archived,
+		Topics:        r.Topics,
+		Language:      r.Language,
+		DefaultBranch: r.Branch,
+		Visibility:    r.Visibility,
+		NeverPushed:   r.PushedAt.IsZero(),
+
+		IssuesDisabled:   !r.HasIssues,
+		WikiDisabled:     !r.HasWiki,
+		ProjectsDisabled: !r.HasProjects,
+	}
+	if r.Owner != nil {
+		repo.Owner = r.Owner.Login
+	}
+	if r.License != nil {
+		repo.License = r.License.SPDXID
+	}
+	return repo
+}
+
+// ErrNoAccess is returned when GitHub refuses access to an organization, for
+// example because it is suspended or the credentials lack the needed scopes.
+var ErrNoAccess = errors.New("no access")
+
+// ErrUnauthorized is returned when GitHub rejects the credentials, for
+// example because a token has expired or been revoked.
+var ErrUnauthorized = errors.New("bad credentials")
+
+// noAccessError reports that GitHub refused access to an organization or,
+// when name is an owner/repo full name, a repository, with GitHub's explanation.
+func noAccessError(name, detail string) error {
+	if strings.Contains(name, "/") {
+		return fmt.Errorf("%w to repository '%s' — check token scopes or repository access (%s)", ErrNoAccess, name, detail)
+	}
+	return fmt.Errorf("%w to org '%s' — check token scopes or org membership (%s)", ErrNoAccess, name, detail)
 }
 
-// NewGitHubClient creates a new GitHub client.
-// If GITHUB_TOKEN is set, uses direct API calls; otherwise falls back to gh CLI.
+// apiError converts a failed GitHub API response into an error. The name is
+// the organization or, for per-repository calls, the owner/repo full name.
+// Access denials are told apart from rate limiting by the message, as GitHub
+// reports both with status 403.
+func apiError(name string, status int, body []byte) error {
+	message := strings.TrimSpace(string(body))
+	var payload struct {
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(body, &payload) == nil && payload.Message != "" {
+		message = payload.Message
+	}
+
+	switch {
+	case (status == http.StatusForbidden || status == http.StatusTooManyRequests) &&
+		strings.Contains(strings.ToLower(message), "rate limit"):
+		return fmt.Errorf("GitHub API rate limit exceeded; wait for it to reset and try again: %s (status %d)", message, status)
+	case status == http.StatusForbidden:
+		return noAccessError(name, message)
+	case status == http.StatusUnauthorized:
+		return fmt.Errorf("%w: GitHub rejected the token; check that it has not expired or been revoked: %s (status %d)", ErrUnauthorized, message, status)
+	}
+	return fmt.Errorf("GitHub API error: %s (status %d)", message, status)
+}
+
+// githubEnterpriseTokenEnvs are checked in order for a token when GH_HOST
+// names a GitHub Enterprise host.
+var githubEnterpriseTokenEnvs = []string{"GH_ENTERPRISE_TOKEN", "GITHUB_ENTERPRISE_TOKEN"}
+
+// apiHost returns the GitHub host named by GH_HOST, defaulting to github.com.
+func apiHost() string {
+	if host := strings.ToLower(strings.TrimSpace(os.Getenv(githubHostEnv))); host != "" {
+		return host
+	}
+	return githubDotCom
+}
+
+// apiBaseURL returns the REST API base URL for a GitHub host.
+func apiBaseURL(host string) string {
+	switch {
+	case host == githubDotCom:
+		return githubAPIBaseURL
+	case strings.HasSuffix(host, ".ghe.com"):
+		return "https://api." + host
+	default:
+		return "https://" + host + "/api/v3"
+	}
+}
+
+// hostToken returns the token for a GitHub host from the environment:
+// GITHUB_TOKEN for github.com, or the first Enterprise token variable set
+// for any other host.
+func hostToken(host string) string {
+	if host == githubDotCom {
+		return os.Getenv(githubTokenEnv)
+	}
+	for _, env := range githubEnterpriseTokenEnvs {
+		if token := os.Getenv(env); token != "" {
+			return token
+		}
+	}
+	return ""
+}
+
+// NewGitHubClient creates a new GitHub client for the host named by GH_HOST
+// (github.com by default).
+// If GitHub App credentials are set, mints installation tokens for API calls.
+// Otherwise, if a token for the host is set (GITHUB_TOKEN for github.com,
+// GH_ENTERPRISE_TOKEN or GITHUB_ENTERPRISE_TOKEN for Enterprise hosts), uses
+// direct API calls; failing that, falls back to gh CLI. The variable may hold
+// several comma-separated tokens; see NewGitHubClientWithTokens.
+// The gh CLI is checked for installation and authentication before its first use,
+// so commands served from the cache work without it.
 func NewGitHubClient() GitHubClient {
-	if token := os.Getenv(githubTokenEnv); token != "" {
-		return &tokenClient{token: token, httpClient: &http.Client{Timeout: 30 * time.Second}}
+	if app := newAppClientFromEnv(); app != nil {
+		return app
+	}
+	if tokens := hostTokens(apiHost()); len(tokens) > 0 {
+		return NewGitHubClientWithTokens(tokens)
 	}
-	return &ghCLIClient{}
+	return &ghCLIClient{exec: gh.Exec}
 }
 
-// tokenClient implements GitHubClient using a personal access token.
-type tokenClient struct {
-	token      string
+// NewGitHubClientWithTokens creates a client for the host named by GH_HOST that
+// makes direct API calls with the given tokens, ignoring any set in the
+// environment. With several tokens, requests move to another token as each
+// nears its rate limit. If GitHub rejects the tokens, the client switches to
+// the gh CLI's own credentials when it is signed in.
+func NewGitHubClientWithTokens(tokens []string) GitHubClient {
+	if len(tokens) == 0 {
+		return &ghCLIClient{exec: gh.Exec}
+	}
+	return &authFallbackClient{
+		token: newTokenClient(apiHost(), tokens),
+		gh:    &ghCLIClient{exec: ghExecWithoutTokens},
+	}
+}
+
+// requestExecutor funnels GitHub API requests through a shared concurrency
+// limit and pauses all callers when the rate limit is exhausted, so that
+// concurrent fetches and enrichments cannot overrun it independently.
+type requestExecutor struct {
 	httpClient *http.Client
+	sem        chan struct{}
+	now        func() time.Time
+	sleep      func(time.Duration)
+
+	mu        sync.Mutex
+	remaining int // -1 until the first response reports it
+	resetAt   time.Time
+
+	// tokens, when set, authenticates each request with one of several
+	// tokens and tracks the rate limit of each in place of remaining
+	tokens *tokenPool
+}
+
+// newRequestExecutor creates a requestExecutor allowing up to concurrency
+// requests in flight at once.
+func newRequestExecutor(httpClient *http.Client, concurrency int) *requestExecutor {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	return &requestExecutor{
+		httpClient: httpClient,
+		sem:        make(chan struct{}, concurrency),
+		now:        time.Now,
+		sleep:      time.Sleep,
+		remaining:  -1,
+	}
+}
+
+// Do sends a request once a concurrency slot is free and the rate limit allows it.
+func (e *requestExecutor) Do(req *http.Request) (*http.Response, error) {
+	e.sem <- struct{}{}
+	defer func() { <-e.sem }()
+
+	if e.tokens != nil {
+		return e.doWithPool(req)
+	}
+
+	e.waitForRateLimit()
+
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	e.updateRateLimit(resp)
+	return resp, nil
+}
+
+// waitForRateLimit blocks until the rate limit resets if no requests remain.
+func (e *requestExecutor) waitForRateLimit() {
+	e.mu.Lock()
+	var wait time.Duration
+	if e.remaining == 0 {
+		wait = e.resetAt.Sub(e.now())
+	}
+	e.mu.Unlock()
+
+	if wait > 0 {
+		slog.Debug("rate limit exhausted, waiting for reset", "wait", wait)
+		e.sleep(wait)
+
+		e.mu.Lock()
+		e.remaining = -1
+		e.mu.Unlock()
+	}
+}
+
+// doWithPool sends a request with the pool token that has quota left,
+// waiting for the first reset if every token is exhausted.
+func (e *requestExecutor) doWithPool(req *http.Request) (*http.Response, error) {
+	token, wait := e.tokens.pick(e.now())
+	if wait > 0 {
+		slog.Debug("rate limit of every token exhausted, waiting for reset", "wait", wait)
+		e.sleep(wait)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if remaining, resetAt, ok := parseRateLimit(resp); ok {
+		e.tokens.update(token, remaining, resetAt)
+		slog.Debug("rate limit", "remaining", remaining, "reset", resetAt)
+	}
+	return resp, nil
+}
+
+// updateRateLimit records the rate limit state reported by a response.
+func (e *requestExecutor) updateRateLimit(resp *http.Response) {
+	remaining, resetAt, ok := parseRateLimit(resp)
+	if !ok {
+		return
+	}
+
+	e.mu.Lock()
+	e.remaining = remaining
+	e.resetAt = resetAt
+	e.mu.Unlock()
+
+	slog.Debug("rate limit", "remaining", remaining, "reset", e.resetAt)
+}
+
+// tokenClient implements GitHubClient using a personal access token. With
+// several tokens, the executor's pool replaces token on each request.
+type tokenClient struct {
+	token    string
+	baseURL  string
+	executor *requestExecutor
+}
+
+// newTokenClient creates a client for a host that sends requests with the
+// given tokens, rotating among them when there are several.
+func newTokenClient(host string, tokens []string) *tokenClient {
+	executor := newRequestExecutor(&http.Client{Timeout: 30 * time.Second}, defaultConcurrency)
+	if len(tokens) > 1 {
+		executor.tokens = newTokenPool(tokens)
+	}
+	return &tokenClient{token: tokens[0], baseURL: apiBaseURL(host), executor: executor}
 }
 
 // FetchRepositories retrieves all repositories using the GitHub API with a token.
 func (c *tokenClient) FetchRepositories(org string) ([]Repository, error) {
+	repos, _, err := c.FetchRepositoriesConditional(org, nil)
+	return repos, err
+}
+
+// FetchRepositoriesConditional retrieves all repositories, sending the ETag of each
+// page in previous with If-None-Match. Pages answered with 304 Not Modified reuse
+// the repositories cached for that page, and do not count against the rate limit.
+func (c *tokenClient) FetchRepositoriesConditional(org string, previous *OrganizationCache) ([]Repository, []PageETag, error) {
 	var allRepos []Repository
+	var pages []PageETag
 	page := 1
-	perPage := 100
+	perPage := repositoriesPerPage
+	start := time.Now()
+	notModified := 0
 
 	for {
 		url := fmt.Sprintf("%s/orgs/%s/repos?type=all&per_page=%d&page=%d",
-			githubAPIBaseURL, org, perPage, page)
+			c.baseURL, org, perPage, page)
+
+		slog.Debug("requesting repositories", "url", url, "page", page)
 
 		req, err := http.NewRequest("GET", url, nil)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create request: %w", err)
+			return nil, nil, fmt.Errorf("failed to create request: %w", err)
 		}
 
 		req.Header.Set("Accept", "application/vnd.github+json")
 		req.Header.Set("Authorization", "Bearer "+c.token)
 		req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
 
-		resp, err := c.httpClient.Do(req)
+		cachedPage, cachedRepos, hasCachedPage := previous.page(page)
+		if hasCachedPage && cachedPage.ETag != "" {
+			req.Header.Set("If-None-Match", cachedPage.ETag)
+		}
+
+		resp, err := c.executor.Do(req)
 		if err != nil {
-			return nil, fmt.Errorf("failed to fetch repositories: %w", err)
+			return nil, nil, fmt.Errorf("failed to fetch repositories: %w", err)
 		}
 
 		body, err := io.ReadAll(resp.Body)
 		resp.Body.Close()
 		if err != nil {
-			return nil, fmt.Errorf("failed to read response: %w", err)
+			return nil, nil, fmt.Errorf("failed to read response: %w", err)
+		}
+
+		if resp.StatusCode == http.StatusNotModified && hasCachedPage {
+			slog.Debug("page not modified", "org", org, "page", page)
+			notModified++
+			allRepos = append(allRepos, cachedRepos...)
+			pages = append(pages, cachedPage)
+
+			// Page boundaries are unchanged, so the cached page count still applies
+			if page >= len(previous.PageETags) {
+				break
+			}
+			page++
+			continue
 		}
 
 		if resp.StatusCode != http.StatusOK {
-			return nil, fmt.Errorf("GitHub API error: %s (status %d)", string(body), resp.StatusCode)
+			return nil, nil, apiError(org, resp.StatusCode, body)
 		}
 
 		var repos []ghRepo
 		if err := json.Unmarshal(body, &repos); err != nil {
-			return nil, fmt.Errorf("failed to parse response: %w", err)
+			return nil, nil, fmt.Errorf("failed to parse response: %w", err)
 		}
 
 		if len(repos) == 0 {
@@ -92,16 +437,9 @@ func (c *tokenClient) FetchRepositories(org string) ([]Repository, error) {
 		}
 
 		for _, repo := range repos {
-			if repo.Archived {
-				continue
-			}
-			allRepos = append(allRepos, Repository{
-				Name:        repo.Name,
-				FullName:    repo.FullName,
-				LastUpdated: repo.PushedAt,
-				HTMLURL:     repo.HTMLURL,
-			})
+			allRepos = append(allRepos, repo.toRepository())
 		}
+		pages = append(pages, PageETag{ETag: resp.Header.Get("ETag"), Count: len(repos)})
 
 		// Check if there are more pages
 		if !hasNextPage(resp) {
@@ -110,7 +448,10 @@ func (c *tokenClient) FetchRepositories(org string) ([]Repository, error) {
 		page++
 	}
 
-	return allRepos, nil
+	slog.Debug("fetched repositories", "org", org, "pages", page, "not_modified", notModified,
+		"repos", len(allRepos), "duration", time.Since(start))
+
+	return allRepos, pages, nil
 }
 
 // hasNextPage checks the Link header for pagination.
@@ -119,29 +460,156 @@ func hasNextPage(resp *http.Response) bool {
 	return strings.Contains(link, `rel="next"`)
 }
 
+// ghExecFunc runs a gh command and captures its output, matching gh.Exec.
+type ghExecFunc func(args ...string) (stdout, stderr bytes.Buffer, err error)
+
+const (
+	ghMaxAttempts  = 3               // Attempts per gh command before giving up on transient failures
+	ghRetryBackoff = 2 * time.Second // Initial delay between attempts, doubled each retry
+)
+
 // ghCLIClient implements GitHubClient using the gh CLI.
-type ghCLIClient struct{}
+type ghCLIClient struct {
+	exec  ghExecFunc
+	sleep func(time.Duration) // Defaults to time.Sleep
+
+	mu      sync.Mutex // Guards checked so concurrent fetches run the precheck once
+	checked bool
+}
+
+// run executes a gh command, retrying transient failures such as rate limiting,
+// server errors, and network errors with exponential backoff.
+func (c *ghCLIClient) run(args ...string) (stdout, stderr bytes.Buffer, err error) {
+	sleep := c.sleep
+	if sleep == nil {
+		sleep = time.Sleep
+	}
+
+	backoff := ghRetryBackoff
+	for attempt := 1; ; attempt++ {
+		stdout, stderr, err = c.exec(args...)
+		if err == nil || attempt >= ghMaxAttempts || !isTransientGHError(stderr.String()) {
+			return stdout, stderr, err
+		}
+
+		slog.Debug("retrying gh", "attempt", attempt, "backoff", backoff, "stderr", strings.TrimSpace(stderr.String()))
+		sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// isTransientGHError reports whether gh's stderr describes a failure worth retrying.
+func isTransientGHError(stderr string) bool {
+	msg := strings.ToLower(stderr)
+	for _, marker := range []string{
+		"rate limit",
+		"http 429",
+		"http 500",
+		"http 502",
+		"http 503",
+		"http 504",
+		"connection reset",
+		"i/o timeout",
+		"tls handshake timeout",
+	} {
+		if strings.Contains(msg, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// ensureChecked runs the precheck before the client's first successful fetch.
+func (c *ghCLIClient) ensureChecked() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.checked {
+		return nil
+	}
+	if err := c.precheck(); err != nil {
+		return err
+	}
+	c.checked = true
+	return nil
+}
+
+// precheck verifies that gh is installed and authenticated.
+func (c *ghCLIClient) precheck() error {
+	if _, stderr, err := c.exec("--version"); err != nil {
+		return wrapGHError(err, stderr.String())
+	}
+	if _, stderr, err := c.exec("auth", "status"); err != nil {
+		return wrapGHError(err, stderr.String())
+	}
+	return nil
+}
+
+// isGHAccessDenied reports whether gh's stderr describes a 403 response that is
+// not caused by rate limiting.
+func isGHAccessDenied(stderr string) bool {
+	msg := strings.ToLower(stderr)
+	return strings.Contains(msg, "http 403") && !strings.Contains(msg, "rate limit")
+}
+
+// wrapGHError converts a gh execution failure into an error with actionable guidance.
+func wrapGHError(err error, stderr string) error {
+	if errors.Is(err, exec.ErrNotFound) {
+		return fmt.Errorf("gh CLI not found; install it from https://cli.github.com or set %s: %w", githubTokenEnv, err)
+	}
+
+	msg := strings.ToLower(stderr)
+	switch {
+	case strings.Contains(msg, "gh auth login") || strings.Contains(msg, "not logged in"):
+		return fmt.Errorf("gh CLI is not authenticated; run 'gh auth login' or set %s: %w", githubTokenEnv, err)
+	case strings.Contains(msg, "could not resolve host") ||
+		strings.Contains(msg, "connection refused") ||
+		strings.Contains(msg, "i/o timeout") ||
+		strings.Contains(msg, "network is unreachable"):
+		return fmt.Errorf("network error contacting GitHub via gh CLI; check your connection: %w", err)
+	case strings.Contains(msg, "rate limit"):
+		return fmt.Errorf("GitHub API rate limit exceeded via gh CLI; wait for it to reset and try again: %w", err)
+	case strings.Contains(msg, "http 404"):
+		return fmt.Errorf("organization not found or not accessible with the current gh credentials: %w", err)
+	}
+
+	if stderr = strings.TrimSpace(stderr); stderr != "" {
+		return fmt.Errorf("failed to fetch repositories: %w: %s", err, stderr)
+	}
+	return fmt.Errorf("failed to fetch repositories: %w", err)
+}
 
 // FetchRepositories retrieves all repositories using the gh CLI.
+// Pages are requested explicitly rather than with --paginate, which emits one
+// JSON array per page that cannot be decoded as a single document.
 func (c *ghCLIClient) FetchRepositories(org string) ([]Repository, error) {
+	if err := c.ensureChecked(); err != nil {
+		return nil, err
+	}
+
 	var allRepos []Repository
 	page := 1
-	perPage := 100
+	perPage := repositoriesPerPage
+	start := time.Now()
 
 	for {
 		args := []string{
 			"api",
+			"--method", "GET",
 			fmt.Sprintf("/orgs/%s/repos", org),
-			"--paginate",
-			"-q", ".",
-			"-F", "per_page=" + strconv.Itoa(perPage),
-			"-F", "page=" + strconv.Itoa(page),
-			"-F", "type=all",
+			"-f", "per_page=" + strconv.Itoa(perPage),
+			"-f", "page=" + strconv.Itoa(page),
+			"-f", "type=all",
 		}
 
-		stdout, _, err := gh.Exec(args...)
+		slog.Debug("running gh", "args", args, "page", page)
+
+		stdout, stderr, err := c.run(args...)
 		if err != nil {
-			return nil, fmt.Errorf("failed to fetch repositories: %w", err)
+			if isGHAccessDenied(stderr.String()) {
+				return nil, noAccessError(org, strings.TrimSpace(stderr.String()))
+			}
+			return nil, wrapGHError(err, stderr.String())
 		}
 
 		var repos []ghRepo
@@ -149,26 +617,19 @@ func (c *ghCLIClient) FetchRepositories(org string) ([]Repository, error) {
 			return nil, fmt.Errorf("failed to parse response: %w", err)
 		}
 
-		if len(repos) == 0 {
-			break
-		}
-
 		for _, repo := range repos {
-			if repo.Archived {
-				continue
-			}
-			allRepos = append(allRepos, Repository{
-				Name:        repo.Name,
-				FullName:    repo.FullName,
-				LastUpdated: repo.PushedAt,
-				HTMLURL:     repo.HTMLURL,
-			})
+			allRepos = append(allRepos, repo.toRepository())
 		}
 
-		// gh --paginate handles pagination automatically, so we only need one iteration
-		break
+		// A short page means there are no more pages
+		if len(repos) < perPage {
+			break
+		}
+		page++
 	}
 
+	slog.Debug("fetched repositories", "org", org, "pages", page, "repos", len(allRepos), "duration", time.Since(start))
+
 	return allRepos, nil
 }
 
@@ -176,6 +637,7 @@ func (c *ghCLIClient) FetchRepositories(org string) ([]Repository, error) {
 type Scanner struct {
 	client GitHubClient
 	cache  *Cache
+	now    func() time.Time // Current time for fetches and history; time.Now if nil
 }
 
 // NewScanner creates a new Scanner with the default GitHub client and cache.
@@ -199,88 +661,470 @@ func NewScannerWithDeps(client GitHubClient, cache *Cache) *Scanner {
 	}
 }
 
+// SetClock replaces the source of the current time for the scanner and its
+// cache, so fetch times, history, and cache expiry all follow it (useful for testing).
+func (s *Scanner) SetClock(now func() time.Time) {
+	s.now = now
+	s.cache.SetClock(now)
+}
+
+// clock returns the current time from the scanner's clock.
+func (s *Scanner) clock() time.Time {
+	if s.now == nil {
+		return time.Now()
+	}
+	return s.now()
+}
+
 // ScanOptions configures the scan behaviour.
 type ScanOptions struct {
-	Refresh bool // Force refresh even if cache is valid
+	Refresh        bool // Force refresh even if cache is valid
+	WithLastCommit bool // Look up each repository's last commit (one extra API call per repository)
+	WithProtection bool // Look up whether each default branch is protected (one extra API call per repository)
+	IncludeForks   bool // Keep forked repositories in the results
+	IncludeMirrors bool // Keep mirrors of repositories maintained elsewhere in the results
+
+	// IncludeArchived keeps archived repositories, which classify as FreshnessArchived
+	IncludeArchived bool
+
+	// ActivityMetric chooses the timestamp that drives freshness; empty means ActivityPushed
+	ActivityMetric ActivityMetric
+
+	// Freshness counts ages for the history entry and changes recorded on a fetch
+	Freshness FreshnessOptions
+
+	// OrgTimeout and Deadline bound ScanAll: the time allowed for each
+	// organization, and for the whole run. Zero means no limit.
+	OrgTimeout time.Duration
+	Deadline   time.Duration
+}
+
+// ActivityMetric selects which repository timestamp counts as its last activity.
+type ActivityMetric string
+
+const (
+	ActivityPushed  ActivityMetric = "pushed"  // Last push to any branch
+	ActivityUpdated ActivityMetric = "updated" // Last change of any kind, including issues and settings
+)
+
+// ParseActivityMetric converts a string to an ActivityMetric value.
+func ParseActivityMetric(s string) (ActivityMetric, bool) {
+	switch s {
+	case "pushed":
+		return ActivityPushed, true
+	case "updated":
+		return ActivityUpdated, true
+	default:
+		return "", false
+	}
+}
+
+// UseActivityMetric returns repos with LastUpdated taken from the timestamp
+// the metric selects. Repositories cached before both timestamps were stored
+// keep their last push.
+func UseActivityMetric(repos []Repository, metric ActivityMetric) []Repository {
+	if metric != ActivityUpdated {
+		return repos
+	}
+
+	result := make([]Repository, len(repos))
+	for i, repo := range repos {
+		if !repo.UpdatedAt.IsZero() {
+			repo.LastUpdated = repo.UpdatedAt
+		}
+		result[i] = repo
+	}
+	return result
 }
 
 // ScanResult contains the results of scanning an organization.
 type ScanResult struct {
-	Organization string
-	Repositories []Repository
-	FetchedAt    time.Time
-	FromCache    bool
+	Organization    string
+	Repositories    []Repository
+	FetchedAt       time.Time
+	FromCache       bool
+	ExcludedForks   int      // Forks left out of Repositories because IncludeForks was not set
+	ExcludedMirrors int      // Mirrors left out of Repositories because IncludeMirrors was not set
+	NeverPushed     int      // Empty repositories left out of Repositories because they have no age
+	CacheWriteErr   error    // Non-fatal failure to update the cache; the results are still valid
+	HistoryErr      error    // Non-fatal failure to record the freshness history; the cache may still have been written
+	Renamed         []Rename // Repositories renamed since the previous fetch, if one was cached
+	Changes         *Changes // Differences from the previous fetch; nil if none was cached
+}
+
+// newScanResult builds a scan result, leaving out forks, mirrors, and archived
+// repositories unless requested and counting never-pushed repositories
+// separately. The cache always keeps all of them, and both activity
+// timestamps, so any choice works on cached data.
+func newScanResult(org string, repos []Repository, fetchedAt time.Time, fromCache bool, opts ScanOptions) *ScanResult {
+	repos = UseActivityMetric(repos, opts.ActivityMetric)
+	if !opts.IncludeArchived {
+		repos = ExcludeArchived(repos)
+	}
+	result := &ScanResult{
+		Organization: org,
+		Repositories: repos,
+		FetchedAt:    fetchedAt,
+		FromCache:    fromCache,
+	}
+	if !opts.IncludeForks {
+		result.Repositories = ExcludeForks(repos)
+		result.ExcludedForks = len(repos) - len(result.Repositories)
+	}
+	if !opts.IncludeMirrors {
+		kept := ExcludeMirrors(result.Repositories)
+		result.ExcludedMirrors = len(result.Repositories) - len(kept)
+		result.Repositories = kept
+	}
+	pushed := ExcludeNeverPushed(result.Repositories)
+	result.NeverPushed = len(result.Repositories) - len(pushed)
+	result.Repositories = pushed
+	return result
 }
 
 // Scan retrieves repository data for an organization, using cache if available.
 func (s *Scanner) Scan(org string, opts ScanOptions) (*ScanResult, error) {
-	now := time.Now()
+	if err := ValidateOrganization(org); err != nil {
+		return nil, err
+	}
+
+	now := s.clock()
 
-	// Try to use cache unless refresh is requested
-	if !opts.Refresh {
-		cached, err := s.cache.Load(org)
-		if err == nil {
-			return &ScanResult{
-				Organization: org,
-				Repositories: cached.Repositories,
-				FetchedAt:    cached.FetchedAt,
-				FromCache:    true,
-			}, nil
+	// Try to use cache unless refresh is requested. Stale or bypassed cache
+	// data is kept so its ETags can revalidate pages instead of refetching them.
+	var previous *OrganizationCache
+	cached, err := s.cache.Load(org)
+	switch {
+	case err == nil && !opts.Refresh:
+		slog.Debug("cache hit", "org", org, "fetched_at", cached.FetchedAt, "repos", len(cached.Repositories))
+		var cacheErr error
+		if opts.WithLastCommit || opts.WithProtection {
+			cacheErr = s.enrichCached(&cached, opts)
 		}
+		result := newScanResult(org, cached.Repositories, cached.FetchedAt, true, opts)
+		result.CacheWriteErr = cacheErr
+		return result, nil
+	case err == nil:
+		slog.Debug("cache bypassed", "org", org, "reason", "refresh requested")
+		previous = &cached
+	case errors.Is(err, ErrCacheExpired):
+		slog.Debug("cache miss", "org", org, "reason", err)
+		previous = &cached
+	case errors.Is(err, ErrCacheCorrupt):
+		// Remove the file so a failed fetch cannot leave it to be read again
+		slog.Warn("cache file is corrupt; removing it and fetching again", "org", org, "error", err)
+		if err := s.cache.Clear(org); err != nil {
+			slog.Warn("failed to remove corrupt cache file", "org", org, "error", err)
+		}
+	default:
+		slog.Debug("cache miss", "org", org, "reason", err)
 	}
 
 	// Fetch fresh data
-	repos, err := s.client.FetchRepositories(org)
+	var repos []Repository
+	var pages []PageETag
+	if cf, ok := s.client.(conditionalFetcher); ok {
+		repos, pages, err = cf.FetchRepositoriesConditional(org, previous)
+	} else {
+		repos, err = s.client.FetchRepositories(org)
+	}
 	if err != nil {
 		return nil, err
 	}
 
+	// Keep last commits from the previous fetch for repositories not pushed to
+	// since, so a refresh only looks up the ones that may have changed
+	if previous != nil {
+		mergeLastCommits(previous.Repositories, repos)
+	}
+	if fetcher, ok := s.client.(lastCommitFetcher); ok && opts.WithLastCommit {
+		enrichMissingLastCommits(fetcher, repos)
+	}
+	if fetcher, ok := s.client.(protectionFetcher); ok && opts.WithProtection {
+		enrichMissingProtection(fetcher, repos)
+	}
+
 	// Save to cache
 	cacheData := OrganizationCache{
 		Organization: org,
 		Repositories: repos,
 		FetchedAt:    now,
+		PageETags:    pages,
 	}
+	// Cache write failures don't fail the scan; the caller decides whether to report them
+	result := newScanResult(org, repos, now, false, opts)
 	if err := s.cache.Save(cacheData); err != nil {
-		// Log but don't fail if cache save fails
-		fmt.Printf("Warning: failed to save cache: %v\n", err)
+		result.CacheWriteErr = fmt.Errorf("failed to save cache: %w", err)
 	}
 
-	return &ScanResult{
+	summary := opts.Freshness.Summary(ExcludeNeverPushed(ExcludeArchived(ExcludeMirrors(ExcludeForks(repos)))), now)
+	entry := HistoryEntry{Timestamp: now, Green: summary.Green, Yellow: summary.Yellow, Red: summary.Red}
+	if err := s.cache.AppendHistory(org, entry); err != nil {
+		result.HistoryErr = fmt.Errorf("failed to record freshness history: %w", err)
+	}
+
+	if previous != nil {
+		result.Renamed = DetectRenames(previous.Repositories, repos)
+		before := newScanResult(org, previous.Repositories, previous.FetchedAt, true, opts)
+		changes := opts.Freshness.DetectChanges(before.Repositories, result.Repositories, previous.FetchedAt, now)
+		result.Changes = &changes
+	}
+	return result, nil
+}
+
+// History returns the freshness counts recorded for an organization on each fetch, oldest first.
+func (s *Scanner) History(org string) ([]HistoryEntry, error) {
+	return s.cache.LoadHistory(org)
+}
+
+// enrichCached looks up the last commits and branch protection requested by
+// opts for cached repositories that lack them, and stores the result without
+// extending the cache's validity.
+func (s *Scanner) enrichCached(cached *OrganizationCache, opts ScanOptions) error {
+	enriched := false
+	if fetcher, ok := s.client.(lastCommitFetcher); ok && opts.WithLastCommit {
+		enriched = enrichMissingLastCommits(fetcher, cached.Repositories)
+	}
+	if fetcher, ok := s.client.(protectionFetcher); ok && opts.WithProtection {
+		enriched = enrichMissingProtection(fetcher, cached.Repositories) || enriched
+	}
+	if !enriched {
+		return nil
+	}
+
+	if err := s.cache.write(*cached); err != nil {
+		return fmt.Errorf("failed to save cache: %w", err)
+	}
+	return nil
+}
+
+// CacheState describes the state of an organization's cache.
+type CacheState string
+
+const (
+	CacheStateValid   CacheState = "valid"
+	CacheStateExpired CacheState = "expired"
+	CacheStateMissing CacheState = "missing"
+	CacheStateCorrupt CacheState = "corrupt" // Removed and refetched by the next scan
+)
+
+// ScanPlan describes what a scan would do without performing it.
+type ScanPlan struct {
+	Organization string
+	CacheState   CacheState
+	FetchedAt    time.Time // Zero if the cache is missing or corrupt
+	Refresh      bool
+	WillFetch    bool // True if the scan would call the GitHub API
+}
+
+// Plan resolves whether a scan would use the cache or fetch from the API.
+// It makes no API calls and does not write to the cache.
+func (s *Scanner) Plan(org string, opts ScanOptions) ScanPlan {
+	plan := ScanPlan{
 		Organization: org,
-		Repositories: repos,
-		FetchedAt:    now,
-		FromCache:    false,
-	}, nil
+		Refresh:      opts.Refresh,
+	}
+
+	cached, err := s.cache.Load(org)
+	switch {
+	case err == nil:
+		plan.CacheState = CacheStateValid
+		plan.FetchedAt = cached.FetchedAt
+	case errors.Is(err, ErrCacheExpired):
+		plan.CacheState = CacheStateExpired
+		plan.FetchedAt = cached.FetchedAt
+	case errors.Is(err, ErrCacheCorrupt):
+		plan.CacheState = CacheStateCorrupt
+	default:
+		plan.CacheState = CacheStateMissing
+	}
+
+	plan.WillFetch = opts.Refresh || plan.CacheState != CacheStateValid
+	return plan
 }
 
 // FreshnessSummary contains counts of repositories by freshness level.
 type FreshnessSummary struct {
-	Green  int
-	Yellow int
-	Red    int
-	Total  int
+	Green  int `json:"green"`
+	Yellow int `json:"yellow"`
+	Red    int `json:"red"`
+	Total  int `json:"total"`
+
+	// Archived repositories count towards Total but not towards any freshness level
+	Archived int `json:"archived,omitempty"`
+
+	// Exempted stale repositories count towards Total but not towards Red;
+	// see CalculateSummaryWithExemptions
+	Exempted int `json:"exempted,omitempty"`
 }
 
 // CalculateSummary computes the freshness summary for a list of repositories.
 func CalculateSummary(repos []Repository, now time.Time) FreshnessSummary {
+	return FreshnessOptions{}.Summary(repos, now)
+}
+
+// Summary computes the freshness summary like CalculateSummary, counting ages
+// with these options.
+func (o FreshnessOptions) Summary(repos []Repository, now time.Time) FreshnessSummary {
 	var summary FreshnessSummary
 	summary.Total = len(repos)
 
 	for _, repo := range repos {
-		switch CalculateFreshness(repo.LastUpdated, now) {
+		switch o.RepositoryFreshness(repo, now) {
 		case FreshnessGreen:
 			summary.Green++
 		case FreshnessYellow:
 			summary.Yellow++
 		case FreshnessRed:
 			summary.Red++
+		case FreshnessArchived:
+			summary.Archived++
 		}
 	}
 
 	return summary
 }
 
+// MergeSummaries adds freshness summaries together, such as those of several organizations.
+func MergeSummaries(summaries ...FreshnessSummary) FreshnessSummary {
+	var merged FreshnessSummary
+	for _, s := range summaries {
+		merged.Green += s.Green
+		merged.Yellow += s.Yellow
+		merged.Red += s.Red
+		merged.Archived += s.Archived
+		merged.Exempted += s.Exempted
+		merged.Total += s.Total
+	}
+	return merged
+}
+
+// AggregateByOrg computes the freshness summary of each organization's repositories.
+func AggregateByOrg(repos map[string][]Repository, now time.Time) map[string]FreshnessSummary {
+	return FreshnessOptions{}.AggregateByOrg(repos, now)
+}
+
+// AggregateByOrg computes each organization's freshness summary, counting ages with these options.
+func (o FreshnessOptions) AggregateByOrg(repos map[string][]Repository, now time.Time) map[string]FreshnessSummary {
+	summaries := make(map[string]FreshnessSummary, len(repos))
+	for org, orgRepos := range repos {
+		summaries[org] = o.Summary(orgRepos, now)
+	}
+	return summaries
+}
+
+// CalculatePercentages returns the percentage of repositories at each
+// freshness level. An empty summary gives zero for each rather than NaN.
+func CalculatePercentages(summary FreshnessSummary) (green, yellow, red float64) {
+	if summary.Total == 0 {
+		return 0, 0, 0
+	}
+	total := float64(summary.Total)
+	return float64(summary.Green) / total * 100, float64(summary.Yellow) / total * 100, float64(summary.Red) / total * 100
+}
+
+// histogramMonths is the number of calendar months covered by MonthlyActivityHistogram.
+const histogramMonths = 24
+
+// MonthBucket counts repositories last updated in a calendar month.
+type MonthBucket struct {
+	Month time.Time // First instant of the month; zero for the overflow bucket
+	Label string
+	Count int
+	Older bool // True for the overflow bucket of repositories older than the window
+}
+
+// MonthlyActivityHistogram counts repositories by the month of their last update
+// over the last 24 months, including the current month. Repositories older than
+// the window are counted in an overflow bucket. Buckets are ordered oldest first,
+// starting with the overflow bucket.
+func MonthlyActivityHistogram(repos []Repository, now time.Time) []MonthBucket {
+	current := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	start := current.AddDate(0, -(histogramMonths - 1), 0)
+
+	buckets := make([]MonthBucket, histogramMonths+1)
+	buckets[0] = MonthBucket{Label: "Older", Older: true}
+	for i := 0; i < histogramMonths; i++ {
+		month := start.AddDate(0, i, 0)
+		buckets[i+1] = MonthBucket{Month: month, Label: month.Format("Jan 2006")}
+	}
+
+	for _, repo := range repos {
+		updated := repo.LastUpdated.In(now.Location())
+		if updated.Before(start) {
+			buckets[0].Count++
+			continue
+		}
+
+		index := (updated.Year()-start.Year())*12 + int(updated.Month()-start.Month())
+		if index >= histogramMonths {
+			// Clamp timestamps in the future to the current month
+			index = histogramMonths - 1
+		}
+		buckets[index+1].Count++
+	}
+
+	return buckets
+}
+
+// UnassignedOwner is the owner bucket used for repositories with no discernible owner.
+const UnassignedOwner = "unassigned"
+
+// OwnerSummary contains the freshness summary and repositories for a single owner.
+type OwnerSummary struct {
+	Owner        string
+	Summary      FreshnessSummary
+	Repositories []Repository
+}
+
+// SummaryByOwner groups repositories by owner and computes per-owner freshness counts.
+// Owners are sorted alphabetically, with the unassigned bucket last.
+func SummaryByOwner(repos []Repository, now time.Time) []OwnerSummary {
+	return FreshnessOptions{}.SummaryByOwner(repos, now)
+}
+
+// SummaryByOwner computes per-owner freshness counts like the package-level
+// SummaryByOwner, counting ages with these options.
+func (o FreshnessOptions) SummaryByOwner(repos []Repository, now time.Time) []OwnerSummary {
+	return o.summaryBy(repos, now, func(repo Repository) string {
+		if repo.Owner == "" {
+			return UnassignedOwner
+		}
+		return repo.Owner
+	}, UnassignedOwner)
+}
+
+// summaryBy groups repositories by the owner key returns and computes each
+// group's freshness counts, sorted alphabetically with the last group at the end.
+func (o FreshnessOptions) summaryBy(repos []Repository, now time.Time, key func(Repository) string, last string) []OwnerSummary {
+	groups := make(map[string][]Repository)
+	for _, repo := range repos {
+		owner := key(repo)
+		groups[owner] = append(groups[owner], repo)
+	}
+
+	summaries := make([]OwnerSummary, 0, len(groups))
+	for owner, ownerRepos := range groups {
+		summaries = append(summaries, OwnerSummary{
+			Owner:        owner,
+			Summary:      o.Summary(ownerRepos, now),
+			Repositories: ownerRepos,
+		})
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		if summaries[i].Owner == last {
+			return false
+		}
+		if summaries[j].Owner == last {
+			return true
+		}
+		return summaries[i].Owner < summaries[j].Owner
+	})
+
+	return summaries
+}
+
 // SortByAge sorts repositories by last update time, oldest first.
 func SortByAge(repos []Repository) {
 	sort.Slice(repos, func(i, j int) bool {
@@ -295,29 +1139,456 @@ func SortByAgeDesc(repos []Repository) {
 	})
 }
 
+// SortBySize sorts repositories by disk size, largest first.
+func SortBySize(repos []Repository) {
+	sort.SliceStable(repos, func(i, j int) bool {
+		return repos[i].SizeKB > repos[j].SizeKB
+	})
+}
+
+// TotalSizeKB returns the combined size of repositories in kilobytes.
+func TotalSizeKB(repos []Repository) int64 {
+	var total int64
+	for _, repo := range repos {
+		total += int64(repo.SizeKB)
+	}
+	return total
+}
+
+// FormatSize returns a human-readable size, such as "12.3 GB", for a size in kilobytes.
+func FormatSize(kb int64) string {
+	units := []string{"KB", "MB", "GB", "TB"}
+	size := float64(kb)
+	unit := 0
+	for size >= 1024 && unit < len(units)-1 {
+		size /= 1024
+		unit++
+	}
+	if unit == 0 {
+		return fmt.Sprintf("%d KB", kb)
+	}
+	return fmt.Sprintf("%.1f %s", size, units[unit])
+}
+
 // FilterByFreshness returns repositories matching the specified freshness level.
 func FilterByFreshness(repos []Repository, freshness Freshness, now time.Time) []Repository {
+	return FreshnessOptions{}.FilterByFreshness(repos, freshness, now)
+}
+
+// FilterByFreshness returns repositories matching the specified freshness
+// level, counting ages with these options.
+func (o FreshnessOptions) FilterByFreshness(repos []Repository, freshness Freshness, now time.Time) []Repository {
+	var filtered []Repository
+	for _, repo := range repos {
+		if o.RepositoryFreshness(repo, now) == freshness {
+			filtered = append(filtered, repo)
+		}
+	}
+	return filtered
+}
+
+// FilterAtLeast returns repositories at least as stale as the specified
+// freshness level, such as yellow and red repositories for FreshnessYellow.
+func FilterAtLeast(repos []Repository, level Freshness, now time.Time) []Repository {
+	return FreshnessOptions{}.FilterAtLeast(repos, level, now)
+}
+
+// FilterAtLeast returns repositories at least as stale as the specified
+// freshness level, counting ages with these options.
+func (o FreshnessOptions) FilterAtLeast(repos []Repository, level Freshness, now time.Time) []Repository {
+	var filtered []Repository
+	for _, repo := range repos {
+		if o.RepositoryFreshness(repo, now).AtLeast(level) {
+			filtered = append(filtered, repo)
+		}
+	}
+	return filtered
+}
+
+// FilterStaleSince returns repositories last updated before the cutoff time.
+func FilterStaleSince(repos []Repository, cutoff time.Time) []Repository {
+	var filtered []Repository
+	for _, repo := range repos {
+		if repo.LastUpdated.Before(cutoff) {
+			filtered = append(filtered, repo)
+		}
+	}
+	return filtered
+}
+
+// FilterNewerThan returns repositories last updated less than d before now.
+func FilterNewerThan(repos []Repository, d time.Duration, now time.Time) []Repository {
+	var filtered []Repository
+	for _, repo := range repos {
+		if now.Sub(repo.LastUpdated) < d {
+			filtered = append(filtered, repo)
+		}
+	}
+	return filtered
+}
+
+// FilterByDateRange returns repositories last updated within the inclusive
+// range of days from the start of from to the end of to. A zero bound leaves
+// that end of the range open.
+func FilterByDateRange(repos []Repository, from, to time.Time) []Repository {
+	var filtered []Repository
+	for _, repo := range repos {
+		if !from.IsZero() && repo.LastUpdated.Before(from) {
+			continue
+		}
+		if !to.IsZero() && !repo.LastUpdated.Before(to.AddDate(0, 0, 1)) {
+			continue
+		}
+		filtered = append(filtered, repo)
+	}
+	return filtered
+}
+
+// FilterByMinAge returns repositories whose age in whole days is at least days.
+func FilterByMinAge(repos []Repository, days int, now time.Time) []Repository {
+	var filtered []Repository
+	for _, repo := range repos {
+		if int(now.Sub(repo.LastUpdated).Hours()/24) >= days {
+			filtered = append(filtered, repo)
+		}
+	}
+	return filtered
+}
+
+// FilterByMinStars returns repositories with at least n stars.
+func FilterByMinStars(repos []Repository, n int) []Repository {
+	var filtered []Repository
+	for _, repo := range repos {
+		if repo.Stars >= n {
+			filtered = append(filtered, repo)
+		}
+	}
+	return filtered
+}
+
+// FilterNoLicense returns repositories that have no license.
+func FilterNoLicense(repos []Repository) []Repository {
+	var filtered []Repository
+	for _, repo := range repos {
+		if repo.License == "" {
+			filtered = append(filtered, repo)
+		}
+	}
+	return filtered
+}
+
+// FilterIssuesDisabled returns repositories with issues disabled, which on a
+// stale repository often signals an intent to archive it.
+func FilterIssuesDisabled(repos []Repository) []Repository {
+	var filtered []Repository
+	for _, repo := range repos {
+		if repo.IssuesDisabled {
+			filtered = append(filtered, repo)
+		}
+	}
+	return filtered
+}
+
+// TopicQuery selects repositories by their topics. Every condition that is
+// set must hold; an empty query matches every repository.
+type TopicQuery struct {
+	All  []string // Repository must have every one of these topics
+	Any  []string // Repository must have at least one of these topics
+	None []string // Repository must have none of these topics
+}
+
+// ParseTopics splits a comma-separated list of topics, lower-casing them as
+// GitHub does and dropping empty entries.
+func ParseTopics(s string) []string {
+	var topics []string
+	for _, topic := range strings.Split(s, ",") {
+		if topic = strings.ToLower(strings.TrimSpace(topic)); topic != "" {
+			topics = append(topics, topic)
+		}
+	}
+	return topics
+}
+
+// Matches reports whether a repository's topics satisfy the query. A
+// repository with no topics fails All and Any conditions and passes None.
+func (q TopicQuery) Matches(topics []string) bool {
+	has := make(map[string]bool, len(topics))
+	for _, topic := range topics {
+		has[strings.ToLower(topic)] = true
+	}
+
+	for _, topic := range q.All {
+		if !has[topic] {
+			return false
+		}
+	}
+	if len(q.Any) > 0 {
+		found := false
+		for _, topic := range q.Any {
+			found = found || has[topic]
+		}
+		if !found {
+			return false
+		}
+	}
+	for _, topic := range q.None {
+		if has[topic] {
+			return false
+		}
+	}
+	return true
+}
+
+// FilterByTopics returns repositories whose topics satisfy the query.
+func FilterByTopics(repos []Repository, query TopicQuery) []Repository {
+	var filtered []Repository
+	for _, repo := range repos {
+		if query.Matches(repo.Topics) {
+			filtered = append(filtered, repo)
+		}
+	}
+	return filtered
+}
+
+// ExcludeForks returns repositories that are not forks.
+func ExcludeForks(repos []Repository) []Repository {
 	var filtered []Repository
 	for _, repo := range repos {
-		if CalculateFreshness(repo.LastUpdated, now) == freshness {
+		if !repo.Fork {
 			filtered = append(filtered, repo)
 		}
 	}
 	return filtered
 }
 
-// GetTopStale returns the n oldest repositories.
+// ExcludeMirrors returns repositories that are not mirrors.
+func ExcludeMirrors(repos []Repository) []Repository {
+	var filtered []Repository
+	for _, repo := range repos {
+		if repo.MirrorURL == "" {
+			filtered = append(filtered, repo)
+		}
+	}
+	return filtered
+}
+
+// ExcludeArchived returns repositories that are not archived.
+func ExcludeArchived(repos []Repository) []Repository {
+	var filtered []Repository
+	for _, repo := range repos {
+		if !repo.Archived {
+			filtered = append(filtered, repo)
+		}
+	}
+	return filtered
+}
+
+// ExcludeNeverPushed returns repositories that have been pushed to at least once.
+func ExcludeNeverPushed(repos []Repository) []Repository {
+	var filtered []Repository
+	for _, repo := range repos {
+		if !repo.NeverPushed {
+			filtered = append(filtered, repo)
+		}
+	}
+	return filtered
+}
+
+// ParseSince converts a cutoff expression into an absolute time.
+// It accepts absolute dates (2006-01-02) and relative durations counted back
+// from now, such as 180d, 4w, 6mo, or 2y.
+func ParseSince(s string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t, nil
+	}
+
+	units := []struct {
+		suffix string
+		apply  func(n int) time.Time
+	}{
+		{"mo", func(n int) time.Time { return now.AddDate(0, -n, 0) }},
+		{"d", func(n int) time.Time { return now.AddDate(0, 0, -n) }},
+		{"w", func(n int) time.Time { return now.AddDate(0, 0, -7*n) }},
+		{"y", func(n int) time.Time { return now.AddDate(-n, 0, 0) }},
+	}
+
+	for _, u := range units {
+		if !strings.HasSuffix(s, u.suffix) {
+			continue
+		}
+		n, err := strconv.Atoi(strings.TrimSuffix(s, u.suffix))
+		if err != nil || n < 0 {
+			break
+		}
+		return u.apply(n), nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid date %q (must be YYYY-MM-DD or a relative duration like 180d, 4w, 6mo, 2y)", s)
+}
+
+// Rename records a repository whose name changed between two fetches.
+type Rename struct {
+	ID      int64  `json:"id"`
+	OldName string `json:"old_name"`
+	NewName string `json:"new_name"`
+}
+
+// DetectRenames matches repositories by their stable ID and returns those whose
+// name changed, sorted by new name. Repositories without an ID, such as those
+// cached by earlier versions, are ignored.
+func DetectRenames(previous, current []Repository) []Rename {
+	oldNames := make(map[int64]string, len(previous))
+	for _, repo := range previous {
+		if repo.ID != 0 {
+			oldNames[repo.ID] = repo.Name
+		}
+	}
+
+	var renames []Rename
+	for _, repo := range current {
+		oldName, ok := oldNames[repo.ID]
+		if repo.ID == 0 || !ok || oldName == repo.Name {
+			continue
+		}
+		renames = append(renames, Rename{ID: repo.ID, OldName: oldName, NewName: repo.Name})
+	}
+
+	sort.Slice(renames, func(i, j int) bool {
+		return renames[i].NewName < renames[j].NewName
+	})
+	return renames
+}
+
+// Changes summarizes how an organization's repositories differ between two fetches.
+type Changes struct {
+	Added            int `json:"added"`
+	Removed          int `json:"removed"`
+	FreshnessChanged int `json:"freshness_changed"`
+}
+
+// DetectChanges compares two fetches, matching repositories by ID and then by
+// name, so renames count as neither added nor removed. Freshness is compared
+// as each fetch would have shown it: previous at previousAt, current at now.
+func DetectChanges(previous, current []Repository, previousAt, now time.Time) Changes {
+	return FreshnessOptions{}.DetectChanges(previous, current, previousAt, now)
+}
+
+// DetectChanges compares two fetches like the package-level DetectChanges,
+// counting ages with these options.
+func (o FreshnessOptions) DetectChanges(previous, current []Repository, previousAt, now time.Time) Changes {
+	byID := make(map[int64]int, len(previous))
+	byName := make(map[string]int, len(previous))
+	for i, repo := range previous {
+		if repo.ID != 0 {
+			byID[repo.ID] = i
+		}
+		byName[changeKey(repo)] = i
+	}
+
+	var changes Changes
+	matched := make(map[int]bool, len(previous))
+	for _, repo := range current {
+		i, ok := byID[repo.ID]
+		if repo.ID == 0 || !ok {
+			i, ok = byName[changeKey(repo)]
+		}
+		if !ok || matched[i] {
+			changes.Added++
+			continue
+		}
+		matched[i] = true
+		if o.RepositoryFreshness(previous[i], previousAt) != o.RepositoryFreshness(repo, now) {
+			changes.FreshnessChanged++
+		}
+	}
+	changes.Removed = len(previous) - len(matched)
+	return changes
+}
+
+// changeKey identifies a repository by name for DetectChanges.
+func changeKey(repo Repository) string {
+	if repo.FullName != "" {
+		return strings.ToLower(repo.FullName)
+	}
+	return strings.ToLower(repo.Name)
+}
+
+// FindRepository returns the repository matching name, compared
+// case-insensitively against both the short and full names.
+func FindRepository(repos []Repository, name string) (Repository, bool) {
+	for _, repo := range repos {
+		if strings.EqualFold(repo.Name, name) || strings.EqualFold(repo.FullName, name) {
+			return repo, true
+		}
+	}
+	return Repository{}, false
+}
+
+// FilterByNames returns the repositories matching any of names, compared as in
+// FindRepository, along with the names that matched no repository.
+func FilterByNames(repos []Repository, names []string) ([]Repository, []string) {
+	var filtered []Repository
+	var missing []string
+	seen := make(map[string]bool)
+	for _, name := range names {
+		repo, ok := FindRepository(repos, name)
+		if !ok {
+			missing = append(missing, name)
+			continue
+		}
+		if !seen[repo.Name] {
+			seen[repo.Name] = true
+			filtered = append(filtered, repo)
+		}
+	}
+	return filtered, missing
+}
+
+// ReadRepositoryList reads repository names from a file, one per line.
+// Blank lines and lines starting with # are ignored.
+func ReadRepositoryList(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read repository list: %w", err)
+	}
+	return parseNameList(string(data)), nil
+}
+
+// ReadOrganizationList reads organization names from r, one per line.
+// Blank lines and lines starting with # are ignored.
+func ReadOrganizationList(r io.Reader) ([]string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read organization list: %w", err)
+	}
+	return parseNameList(string(data)), nil
+}
+
+// parseNameList splits newline-delimited names, skipping blank lines and # comments.
+func parseNameList(data string) []string {
+	names := []string{}
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		names = append(names, line)
+	}
+	return names
+}
+
+// GetTopStale returns the n oldest repositories, leaving out archived ones.
+// If n is zero or negative, all repositories are returned oldest first.
 func GetTopStale(repos []Repository, n int) []Repository {
 	if len(repos) == 0 {
 		return nil
 	}
 
-	// Create a copy to avoid modifying the original
-	sorted := make([]Repository, len(repos))
-	copy(sorted, repos)
+	// Filtering copies the slice, so the original is not reordered
+	sorted := ExcludeArchived(repos)
 	SortByAge(sorted)
 
-	if n > len(sorted) {
+	if n <= 0 || n > len(sorted) {
 		n = len(sorted)
 	}
 
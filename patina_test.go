@@ -1,21 +1,52 @@
 package patina
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 )
 
 // mockGitHubClient implements GitHubClient for testing.
 type mockGitHubClient struct {
-	repos []Repository
-	err   error
+	repos   []Repository
+	err     error
+	commits map[string]LastCommit
+
+	mu            sync.Mutex
+	commitLookups int
 }
 
 func (m *mockGitHubClient) FetchRepositories(org string) ([]Repository, error) {
 	if m.err != nil {
 		return nil, m.err
 	}
-	return m.repos, nil
+	// Return a copy so enrichment does not leak between scans
+	repos := make([]Repository, len(m.repos))
+	copy(repos, m.repos)
+	return repos, nil
+}
+
+func (m *mockGitHubClient) FetchLastCommit(fullName string) (LastCommit, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.commitLookups++
+
+	last, ok := m.commits[fullName]
+	if !ok {
+		return LastCommit{}, fmt.Errorf("no commit for %s", fullName)
+	}
+	return last, nil
 }
 
 func TestCalculateSummary(t *testing.T) {
@@ -122,6 +153,38 @@ func TestFilterByFreshness(t *testing.T) {
 	}
 }
 
+func TestFilterAtLeast(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+
+	repos := []Repository{
+		{Name: "green", LastUpdated: now.AddDate(0, 0, -1)},
+		{Name: "yellow", LastUpdated: now.AddDate(0, 0, -90)},
+		{Name: "red", LastUpdated: now.AddDate(-1, 0, 0)},
+		{Name: "archived", LastUpdated: now.AddDate(-2, 0, 0), Archived: true},
+	}
+
+	tests := []struct {
+		level     Freshness
+		wantNames []string
+	}{
+		{FreshnessGreen, []string{"green", "yellow", "red"}},
+		{FreshnessYellow, []string{"yellow", "red"}},
+		{FreshnessRed, []string{"red"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.level), func(t *testing.T) {
+			var names []string
+			for _, repo := range FilterAtLeast(repos, tt.level, now) {
+				names = append(names, repo.Name)
+			}
+			if !slices.Equal(names, tt.wantNames) {
+				t.Errorf("FilterAtLeast(%s) = %v, want %v", tt.level, names, tt.wantNames)
+			}
+		})
+	}
+}
+
 func TestGetTopStale(t *testing.T) {
 	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
 
@@ -167,6 +230,56 @@ func TestGetTopStaleWithFewerRepos(t *testing.T) {
 	}
 }
 
+func TestGetTopStaleZeroReturnsAll(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+
+	repos := []Repository{
+		{Name: "repo1", LastUpdated: now.AddDate(0, 0, -30)},
+		{Name: "repo2", LastUpdated: now.AddDate(-1, 0, 0)},
+		{Name: "repo3", LastUpdated: now.AddDate(0, 0, -90)},
+	}
+
+	all := GetTopStale(repos, 0)
+
+	if len(all) != 3 {
+		t.Fatalf("len(all) = %d, want 3", len(all))
+	}
+	if all[0].Name != "repo2" {
+		t.Errorf("all[0].Name = %s, want repo2", all[0].Name)
+	}
+}
+
+func TestFindRepository(t *testing.T) {
+	repos := []Repository{
+		{Name: "repo1", FullName: "org/repo1"},
+		{Name: "Repo2", FullName: "org/Repo2"},
+	}
+
+	tests := []struct {
+		name   string
+		want   string
+		wantOk bool
+	}{
+		{"repo1", "repo1", true},
+		{"org/repo1", "repo1", true},
+		{"repo2", "Repo2", true},
+		{"ORG/REPO2", "Repo2", true},
+		{"missing", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := FindRepository(repos, tt.name)
+			if ok != tt.wantOk {
+				t.Fatalf("FindRepository(%q) ok = %v, want %v", tt.name, ok, tt.wantOk)
+			}
+			if got.Name != tt.want {
+				t.Errorf("FindRepository(%q).Name = %s, want %s", tt.name, got.Name, tt.want)
+			}
+		})
+	}
+}
+
 func TestGetTopStaleEmpty(t *testing.T) {
 	top := GetTopStale(nil, 10)
 	if top != nil {
@@ -222,6 +335,153 @@ func TestScannerWithMock(t *testing.T) {
 	}
 }
 
+func TestScannerClock(t *testing.T) {
+	cache := NewCacheWithDir(t.TempDir())
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+
+	mockClient := &mockGitHubClient{
+		repos: []Repository{
+			{Name: "repo1", FullName: "org/repo1", LastUpdated: now.AddDate(0, 0, -30)},
+		},
+	}
+
+	scanner := NewScannerWithDeps(mockClient, cache)
+	scanner.SetClock(func() time.Time { return now })
+
+	result, err := scanner.Scan("org", ScanOptions{})
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if !result.FetchedAt.Equal(now) {
+		t.Errorf("result.FetchedAt = %v, want %v", result.FetchedAt, now)
+	}
+
+	cached, err := cache.Load("org")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !cached.FetchedAt.Equal(now) {
+		t.Errorf("cached.FetchedAt = %v, want %v", cached.FetchedAt, now)
+	}
+
+	history, err := scanner.History("org")
+	if err != nil || len(history) != 1 || !history[0].Timestamp.Equal(now) {
+		t.Errorf("History() = %v, %v; want one entry at %v", history, err, now)
+	}
+
+	// Moving the clock past the cache duration expires the cache
+	now = now.Add(cacheValidity + time.Hour)
+	result, err = scanner.Scan("org", ScanOptions{})
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if result.FromCache {
+		t.Error("result.FromCache = true after the cache expired, want false")
+	}
+	if !result.FetchedAt.Equal(now) {
+		t.Errorf("result.FetchedAt = %v, want %v", result.FetchedAt, now)
+	}
+}
+
+func TestScannerCachesEmptyOrganization(t *testing.T) {
+	mockClient := &mockGitHubClient{}
+	scanner := NewScannerWithDeps(mockClient, NewCacheWithDir(t.TempDir()))
+
+	result, err := scanner.Scan("empty-org", ScanOptions{})
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if result.FromCache {
+		t.Error("result.FromCache = true on first scan, want false")
+	}
+
+	// Make any further fetch fail, so only the cache can satisfy the scan
+	mockClient.err = errors.New("unexpected fetch")
+	result, err = scanner.Scan("empty-org", ScanOptions{})
+	if err != nil {
+		t.Fatalf("Scan() error = %v, want the cached empty result", err)
+	}
+	if !result.FromCache {
+		t.Error("result.FromCache = false for a cached empty organization, want true")
+	}
+	if len(result.Repositories) != 0 {
+		t.Errorf("len(result.Repositories) = %d, want 0", len(result.Repositories))
+	}
+}
+
+func TestScannerRemovesCorruptCache(t *testing.T) {
+	cache := NewCacheWithDir(t.TempDir())
+	if err := os.MkdirAll(cache.CacheDir(), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(cache.cacheFilePath("test-org"), []byte{0x1f, 0x8b, 0x08}, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	mockClient := &mockGitHubClient{err: errors.New("network down")}
+	scanner := NewScannerWithDeps(mockClient, cache)
+
+	if plan := scanner.Plan("test-org", ScanOptions{}); plan.CacheState != CacheStateCorrupt || !plan.WillFetch {
+		t.Errorf("Plan() = %+v, want a corrupt cache that would be fetched", plan)
+	}
+
+	// The corrupt file is removed even when the fetch that should replace it fails
+	if _, err := scanner.Scan("test-org", ScanOptions{}); err == nil {
+		t.Fatal("Scan() error = nil, want the fetch error")
+	}
+	if _, err := cache.Load("test-org"); !errors.Is(err, ErrCacheNotFound) {
+		t.Errorf("Load() error = %v after scan, want %v", err, ErrCacheNotFound)
+	}
+
+	mockClient.err = nil
+	mockClient.repos = []Repository{{Name: "api", LastUpdated: time.Now()}}
+	result, err := scanner.Scan("test-org", ScanOptions{})
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if result.FromCache || len(result.Repositories) != 1 {
+		t.Errorf("Scan() = %d repositories, FromCache %t; want 1 fetched", len(result.Repositories), result.FromCache)
+	}
+	if !cache.IsValid("test-org") {
+		t.Error("cache not rewritten after the scan")
+	}
+}
+
+func TestScannerPlan(t *testing.T) {
+	tmpDir := t.TempDir()
+	cache := NewCacheWithDir(tmpDir)
+	mockClient := &mockGitHubClient{err: errors.New("should not be called")}
+	scanner := NewScannerWithDeps(mockClient, cache)
+
+	plan := scanner.Plan("org", ScanOptions{})
+	if plan.CacheState != CacheStateMissing {
+		t.Errorf("CacheState = %s, want %s", plan.CacheState, CacheStateMissing)
+	}
+	if !plan.WillFetch {
+		t.Error("WillFetch = false with missing cache, want true")
+	}
+
+	if err := cache.Save(OrganizationCache{Organization: "org"}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	plan = scanner.Plan("org", ScanOptions{})
+	if plan.CacheState != CacheStateValid {
+		t.Errorf("CacheState = %s, want %s", plan.CacheState, CacheStateValid)
+	}
+	if plan.FetchedAt.IsZero() {
+		t.Error("FetchedAt is zero with valid cache")
+	}
+	if plan.WillFetch {
+		t.Error("WillFetch = true with valid cache, want false")
+	}
+
+	plan = scanner.Plan("org", ScanOptions{Refresh: true})
+	if !plan.WillFetch {
+		t.Error("WillFetch = false with Refresh option, want true")
+	}
+}
+
 func TestCalculateSummaryEmpty(t *testing.T) {
 	now := time.Now()
 	summary := CalculateSummary(nil, now)
@@ -239,3 +499,1329 @@ func TestCalculateSummaryEmpty(t *testing.T) {
 		t.Errorf("Red = %d, want 0", summary.Red)
 	}
 }
+
+func TestMergeSummaries(t *testing.T) {
+	merged := MergeSummaries(
+		FreshnessSummary{Green: 2, Yellow: 1, Red: 1, Total: 4},
+		FreshnessSummary{Green: 1, Red: 3, Archived: 2, Total: 6},
+	)
+
+	want := FreshnessSummary{Green: 3, Yellow: 1, Red: 4, Archived: 2, Total: 10}
+	if merged != want {
+		t.Errorf("MergeSummaries() = %+v, want %+v", merged, want)
+	}
+	if empty := MergeSummaries(); empty != (FreshnessSummary{}) {
+		t.Errorf("MergeSummaries() with no summaries = %+v, want zero", empty)
+	}
+}
+
+func TestAggregateByOrg(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	repos := map[string][]Repository{
+		"org-a": {
+			{Name: "active", LastUpdated: now.AddDate(0, 0, -5)},
+			{Name: "stale", LastUpdated: now.AddDate(-1, 0, 0)},
+		},
+		"org-b": {
+			{Name: "aging", LastUpdated: now.AddDate(0, -3, 0)},
+		},
+		"org-c": nil,
+	}
+
+	summaries := AggregateByOrg(repos, now)
+
+	want := map[string]FreshnessSummary{
+		"org-a": {Green: 1, Red: 1, Total: 2},
+		"org-b": {Yellow: 1, Total: 1},
+		"org-c": {},
+	}
+	if len(summaries) != len(want) {
+		t.Fatalf("len(summaries) = %d, want %d", len(summaries), len(want))
+	}
+	for org, w := range want {
+		if summaries[org] != w {
+			t.Errorf("summaries[%s] = %+v, want %+v", org, summaries[org], w)
+		}
+	}
+}
+
+func TestCalculatePercentages(t *testing.T) {
+	green, yellow, red := CalculatePercentages(FreshnessSummary{Green: 2, Yellow: 1, Red: 1, Total: 4})
+	if green != 50 || yellow != 25 || red != 25 {
+		t.Errorf("CalculatePercentages() = %v, %v, %v; want 50, 25, 25", green, yellow, red)
+	}
+
+	// An empty summary must not divide by zero
+	green, yellow, red = CalculatePercentages(FreshnessSummary{})
+	if green != 0 || yellow != 0 || red != 0 {
+		t.Errorf("CalculatePercentages() on empty summary = %v, %v, %v; want 0, 0, 0", green, yellow, red)
+	}
+}
+
+func TestSummaryByOwner(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+
+	repos := []Repository{
+		{Name: "repo1", Owner: "zeta", LastUpdated: now.AddDate(0, 0, -1)},
+		{Name: "repo2", Owner: "alpha", LastUpdated: now.AddDate(-1, 0, 0)},
+		{Name: "repo3", Owner: "alpha", LastUpdated: now.AddDate(0, 0, -90)},
+		{Name: "repo4", LastUpdated: now.AddDate(-2, 0, 0)},
+	}
+
+	summaries := SummaryByOwner(repos, now)
+
+	if len(summaries) != 3 {
+		t.Fatalf("len(summaries) = %d, want 3", len(summaries))
+	}
+
+	wantOwners := []string{"alpha", "zeta", UnassignedOwner}
+	for i, want := range wantOwners {
+		if summaries[i].Owner != want {
+			t.Errorf("summaries[%d].Owner = %s, want %s", i, summaries[i].Owner, want)
+		}
+	}
+
+	alpha := summaries[0]
+	if alpha.Summary.Total != 2 {
+		t.Errorf("alpha Total = %d, want 2", alpha.Summary.Total)
+	}
+	if alpha.Summary.Yellow != 1 {
+		t.Errorf("alpha Yellow = %d, want 1", alpha.Summary.Yellow)
+	}
+	if alpha.Summary.Red != 1 {
+		t.Errorf("alpha Red = %d, want 1", alpha.Summary.Red)
+	}
+
+	unassigned := summaries[2]
+	if len(unassigned.Repositories) != 1 || unassigned.Repositories[0].Name != "repo4" {
+		t.Errorf("unassigned.Repositories = %v, want [repo4]", unassigned.Repositories)
+	}
+}
+
+func TestSummaryByOwnerEmpty(t *testing.T) {
+	summaries := SummaryByOwner(nil, time.Now())
+	if len(summaries) != 0 {
+		t.Errorf("len(summaries) = %d, want 0", len(summaries))
+	}
+}
+
+func TestFilterNewerThan(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	repos := []Repository{
+		{Name: "today", LastUpdated: now},
+		{Name: "last-week", LastUpdated: now.AddDate(0, 0, -7)},
+		{Name: "boundary", LastUpdated: now.AddDate(0, 0, -30)},
+		{Name: "old", LastUpdated: now.AddDate(-1, 0, 0)},
+	}
+
+	filtered := FilterNewerThan(repos, 30*24*time.Hour, now)
+
+	var names []string
+	for _, repo := range filtered {
+		names = append(names, repo.Name)
+	}
+	if strings.Join(names, ",") != "today,last-week" {
+		t.Errorf("names = %v, want [today last-week]", names)
+	}
+}
+
+func TestFilterStaleSince(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	cutoff := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	repos := []Repository{
+		{Name: "recent", LastUpdated: now.AddDate(0, 0, -1)},
+		{Name: "old", LastUpdated: time.Date(2022, 12, 31, 0, 0, 0, 0, time.UTC)},
+		{Name: "at-cutoff", LastUpdated: cutoff},
+		{Name: "ancient", LastUpdated: time.Date(2019, 3, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	filtered := FilterStaleSince(repos, cutoff)
+
+	if len(filtered) != 2 {
+		t.Fatalf("len(filtered) = %d, want 2", len(filtered))
+	}
+	if filtered[0].Name != "old" {
+		t.Errorf("filtered[0].Name = %s, want old", filtered[0].Name)
+	}
+	if filtered[1].Name != "ancient" {
+		t.Errorf("filtered[1].Name = %s, want ancient", filtered[1].Name)
+	}
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		input   string
+		want    time.Time
+		wantErr bool
+	}{
+		{"2023-01-01", time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC), false},
+		{"180d", now.AddDate(0, 0, -180), false},
+		{"4w", now.AddDate(0, 0, -28), false},
+		{"6mo", now.AddDate(0, -6, 0), false},
+		{"2y", now.AddDate(-2, 0, 0), false},
+		{"", time.Time{}, true},
+		{"yesterday", time.Time{}, true},
+		{"6m", time.Time{}, true},
+		{"-5d", time.Time{}, true},
+		{"2023/01/01", time.Time{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseSince(tt.input, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSince(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("ParseSince(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterByDateRange(t *testing.T) {
+	day := func(y int, m time.Month, d, h int) time.Time { return time.Date(y, m, d, h, 0, 0, 0, time.UTC) }
+
+	repos := []Repository{
+		{Name: "before", LastUpdated: day(2023, 3, 31, 23)},
+		{Name: "start", LastUpdated: day(2023, 4, 1, 0)},
+		{Name: "middle", LastUpdated: day(2023, 5, 15, 12)},
+		{Name: "end", LastUpdated: day(2023, 6, 30, 23)},
+		{Name: "after", LastUpdated: day(2023, 7, 1, 0)},
+	}
+
+	q2From := day(2023, 4, 1, 0)
+	q2To := day(2023, 6, 30, 0)
+
+	tests := []struct {
+		name     string
+		from, to time.Time
+		want     []string
+	}{
+		{"closed range", q2From, q2To, []string{"start", "middle", "end"}},
+		{"open start", time.Time{}, q2To, []string{"before", "start", "middle", "end"}},
+		{"open end", q2From, time.Time{}, []string{"start", "middle", "end", "after"}},
+		{"single day", q2To, q2To, []string{"end"}},
+		{"unbounded", time.Time{}, time.Time{}, []string{"before", "start", "middle", "end", "after"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filtered := FilterByDateRange(repos, tt.from, tt.to)
+
+			var got []string
+			for _, repo := range filtered {
+				got = append(got, repo.Name)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("FilterByDateRange() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterByMinAge(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+
+	repos := []Repository{
+		{Name: "recent", LastUpdated: now.AddDate(0, 0, -10)},
+		{Name: "almost", LastUpdated: now.AddDate(0, 0, -90).Add(time.Hour)},
+		{Name: "exact", LastUpdated: now.AddDate(0, 0, -90)},
+		{Name: "old", LastUpdated: now.AddDate(-1, 0, 0)},
+	}
+
+	filtered := FilterByMinAge(repos, 90, now)
+
+	if len(filtered) != 2 {
+		t.Fatalf("len(filtered) = %d, want 2", len(filtered))
+	}
+	if filtered[0].Name != "exact" || filtered[1].Name != "old" {
+		t.Errorf("filtered = %v, want [exact old]", filtered)
+	}
+}
+
+func TestFilterByMinStars(t *testing.T) {
+	repos := []Repository{
+		{Name: "toy"},
+		{Name: "few", Stars: 4},
+		{Name: "exact", Stars: 5},
+		{Name: "popular", Stars: 120},
+	}
+
+	filtered := FilterByMinStars(repos, 5)
+
+	if len(filtered) != 2 {
+		t.Fatalf("len(filtered) = %d, want 2", len(filtered))
+	}
+	if filtered[0].Name != "exact" || filtered[1].Name != "popular" {
+		t.Errorf("filtered = %v, want [exact popular]", filtered)
+	}
+}
+
+func TestFilterByNames(t *testing.T) {
+	repos := []Repository{
+		{Name: "api", FullName: "org/api"},
+		{Name: "web", FullName: "org/web"},
+		{Name: "docs", FullName: "org/docs"},
+	}
+
+	filtered, missing := FilterByNames(repos, []string{"WEB", "org/api", "gone", "web"})
+
+	if len(filtered) != 2 || filtered[0].Name != "web" || filtered[1].Name != "api" {
+		t.Errorf("filtered = %v, want [web api]", filtered)
+	}
+	if len(missing) != 1 || missing[0] != "gone" {
+		t.Errorf("missing = %v, want [gone]", missing)
+	}
+}
+
+func TestReadRepositoryList(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "repos.txt")
+	content := "# focus list\napi\n\n  web  \n# docs\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	names, err := ReadRepositoryList(path)
+	if err != nil {
+		t.Fatalf("ReadRepositoryList() error = %v", err)
+	}
+	if strings.Join(names, ",") != "api,web" {
+		t.Errorf("names = %v, want [api web]", names)
+	}
+
+	if _, err := ReadRepositoryList(filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+		t.Error("ReadRepositoryList() error = nil for missing file, want error")
+	}
+}
+
+func TestReadOrganizationList(t *testing.T) {
+	names, err := ReadOrganizationList(strings.NewReader("# platform teams\norg-a\n\n  org-b  \n"))
+	if err != nil {
+		t.Fatalf("ReadOrganizationList() error = %v", err)
+	}
+	if strings.Join(names, ",") != "org-a,org-b" {
+		t.Errorf("names = %v, want [org-a org-b]", names)
+	}
+}
+
+func TestFilterNoLicense(t *testing.T) {
+	repos := []Repository{
+		{Name: "mit", License: "MIT"},
+		{Name: "none"},
+		{Name: "apache", License: "Apache-2.0"},
+		{Name: "other", License: "NOASSERTION"},
+	}
+
+	filtered := FilterNoLicense(repos)
+
+	if len(filtered) != 1 {
+		t.Fatalf("len(filtered) = %d, want 1", len(filtered))
+	}
+	if filtered[0].Name != "none" {
+		t.Errorf("filtered[0].Name = %s, want none", filtered[0].Name)
+	}
+}
+
+func TestFilterIssuesDisabled(t *testing.T) {
+	repos := []Repository{
+		{Name: "api"},
+		{Name: "legacy", IssuesDisabled: true},
+		{Name: "docs", WikiDisabled: true},
+	}
+
+	filtered := FilterIssuesDisabled(repos)
+
+	if len(filtered) != 1 || filtered[0].Name != "legacy" {
+		t.Errorf("FilterIssuesDisabled() = %v, want [legacy]", filtered)
+	}
+}
+
+func TestFilterByTopics(t *testing.T) {
+	repos := []Repository{
+		{Name: "api", Topics: []string{"go", "service"}},
+		{Name: "web", Topics: []string{"typescript", "service"}},
+		{Name: "old", Topics: []string{"go", "deprecated"}},
+		{Name: "bare"},
+	}
+
+	names := func(repos []Repository) string {
+		var out []string
+		for _, repo := range repos {
+			out = append(out, repo.Name)
+		}
+		return strings.Join(out, ",")
+	}
+
+	tests := []struct {
+		name  string
+		query TopicQuery
+		want  string
+	}{
+		{"empty", TopicQuery{}, "api,web,old,bare"},
+		{"all", TopicQuery{All: []string{"go", "service"}}, "api"},
+		{"any", TopicQuery{Any: []string{"typescript", "deprecated"}}, "web,old"},
+		{"none", TopicQuery{None: []string{"deprecated"}}, "api,web,bare"},
+		{"combined", TopicQuery{Any: []string{"go", "typescript"}, None: []string{"deprecated"}}, "api,web"},
+		{"no match", TopicQuery{All: []string{"rust"}}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := names(FilterByTopics(repos, tt.query)); got != tt.want {
+				t.Errorf("FilterByTopics() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTopics(t *testing.T) {
+	got := ParseTopics(" Go, ,service,")
+	if len(got) != 2 || got[0] != "go" || got[1] != "service" {
+		t.Errorf("ParseTopics() = %q, want [go service]", got)
+	}
+	if got := ParseTopics(""); got != nil {
+		t.Errorf("ParseTopics(\"\") = %q, want nil", got)
+	}
+}
+
+func TestExcludeForks(t *testing.T) {
+	repos := []Repository{
+		{Name: "source"},
+		{Name: "fork", Fork: true},
+		{Name: "other"},
+	}
+
+	filtered := ExcludeForks(repos)
+
+	if len(filtered) != 2 {
+		t.Fatalf("len(filtered) = %d, want 2", len(filtered))
+	}
+	for _, repo := range filtered {
+		if repo.Fork {
+			t.Errorf("filtered contains fork %s", repo.Name)
+		}
+	}
+}
+
+func TestScannerExcludesForks(t *testing.T) {
+	cache := NewCacheWithDir(t.TempDir())
+	now := time.Now()
+
+	mockClient := &mockGitHubClient{
+		repos: []Repository{
+			{Name: "source", LastUpdated: now},
+			{Name: "fork", LastUpdated: now, Fork: true},
+		},
+	}
+	scanner := NewScannerWithDeps(mockClient, cache)
+
+	result, err := scanner.Scan("org", ScanOptions{})
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if len(result.Repositories) != 1 || result.ExcludedForks != 1 {
+		t.Errorf("got %d repos, %d excluded forks; want 1, 1", len(result.Repositories), result.ExcludedForks)
+	}
+
+	// Forks are kept in the cache, so including them works on cached data
+	result, err = scanner.Scan("org", ScanOptions{IncludeForks: true})
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if !result.FromCache {
+		t.Error("result.FromCache = false, want true")
+	}
+	if len(result.Repositories) != 2 || result.ExcludedForks != 0 {
+		t.Errorf("got %d repos, %d excluded forks; want 2, 0", len(result.Repositories), result.ExcludedForks)
+	}
+}
+
+func TestScannerExcludesMirrors(t *testing.T) {
+	cache := NewCacheWithDir(t.TempDir())
+	now := time.Now()
+
+	mockClient := &mockGitHubClient{
+		repos: []Repository{
+			{Name: "source", LastUpdated: now},
+			{Name: "mirror", LastUpdated: now.AddDate(-2, 0, 0), MirrorURL: "https://git.example.com/mirror.git"},
+			{Name: "fork", LastUpdated: now, Fork: true},
+		},
+	}
+	scanner := NewScannerWithDeps(mockClient, cache)
+
+	result, err := scanner.Scan("org", ScanOptions{})
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if len(result.Repositories) != 1 || result.ExcludedMirrors != 1 || result.ExcludedForks != 1 {
+		t.Errorf("got %d repos, %d excluded mirrors, %d excluded forks; want 1, 1, 1",
+			len(result.Repositories), result.ExcludedMirrors, result.ExcludedForks)
+	}
+
+	// Mirrors are kept in the cache, so including them works on cached data
+	result, err = scanner.Scan("org", ScanOptions{IncludeMirrors: true})
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if !result.FromCache || len(result.Repositories) != 2 || result.ExcludedMirrors != 0 {
+		t.Errorf("got %d repos, %d excluded mirrors, from cache %t; want 2, 0, true",
+			len(result.Repositories), result.ExcludedMirrors, result.FromCache)
+	}
+
+	// The stale mirror does not count in the recorded history
+	history, err := scanner.History("org")
+	if err != nil || len(history) != 1 || history[0].Red != 0 {
+		t.Errorf("History() = %+v, %v; want one entry with no red repositories", history, err)
+	}
+}
+
+func TestScannerCountsNeverPushed(t *testing.T) {
+	now := time.Now()
+	mockClient := &mockGitHubClient{
+		repos: []Repository{
+			{Name: "active", LastUpdated: now},
+			{Name: "empty", NeverPushed: true},
+		},
+	}
+	scanner := NewScannerWithDeps(mockClient, NewCacheWithDir(t.TempDir()))
+
+	result, err := scanner.Scan("org", ScanOptions{})
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if len(result.Repositories) != 1 || result.Repositories[0].Name != "active" {
+		t.Errorf("Repositories = %v, want only active", result.Repositories)
+	}
+	if result.NeverPushed != 1 {
+		t.Errorf("NeverPushed = %d, want 1", result.NeverPushed)
+	}
+
+	// The empty repository is not counted as an ancient red repository
+	summary := CalculateSummary(result.Repositories, now)
+	if summary.Red != 0 || summary.Total != 1 {
+		t.Errorf("summary = %+v, want 1 green and no red", summary)
+	}
+}
+
+func TestScannerArchivedRepositories(t *testing.T) {
+	now := time.Now()
+
+	mockClient := &mockGitHubClient{
+		repos: []Repository{
+			{Name: "active", LastUpdated: now},
+			{Name: "stale", LastUpdated: now.AddDate(-1, 0, 0)},
+			{Name: "archived", LastUpdated: now.AddDate(-2, 0, 0), Archived: true},
+		},
+	}
+	scanner := NewScannerWithDeps(mockClient, NewCacheWithDir(t.TempDir()))
+
+	result, err := scanner.Scan("org", ScanOptions{})
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if len(result.Repositories) != 2 {
+		t.Errorf("got %d repos, want 2 without archived", len(result.Repositories))
+	}
+
+	// Archived repositories are kept in the cache and counted in their own state
+	result, err = scanner.Scan("org", ScanOptions{IncludeArchived: true})
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	summary := CalculateSummary(result.Repositories, now)
+	want := FreshnessSummary{Green: 1, Red: 1, Archived: 1, Total: 3}
+	if summary != want {
+		t.Errorf("summary = %+v, want %+v", summary, want)
+	}
+
+	top := GetTopStale(result.Repositories, 0)
+	if len(top) != 2 || top[0].Name != "stale" {
+		t.Errorf("GetTopStale() = %v, want [stale active]", top)
+	}
+	if red := FilterByFreshness(result.Repositories, FreshnessRed, now); len(red) != 1 {
+		t.Errorf("FilterByFreshness(red) = %d repos, want 1", len(red))
+	}
+}
+
+func TestGhRepoToRepository(t *testing.T) {
+	pushed := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	updated := pushed.AddDate(0, 1, 0)
+
+	repo := ghRepo{
+		Name:        "repo1",
+		FullName:    "org/repo1",
+		HTMLURL:     "https://github.com/org/repo1",
+		CloneURL:    "https://github.com/org/repo1.git",
+		SSHURL:      "git@github.com:org/repo1.git",
+		Description: "Payments API",
+		Branch:      "main",
+		PushedAt:    pushed,
+		UpdatedAt:   updated,
+		Size:        2048,
+		Topics:      []string{"go", "payments"},
+		Fork:        true,
+		MirrorURL:   "https://git.example.com/repo1.git",
+		HasIssues:   true,
+		HasProjects: true,
+		Owner:       &ghOwner{Login: "org"},
+		License:     &ghLicense{SPDXID: "MIT"},
+	}.toRepository()
+
+	if !repo.Fork {
+		t.Error("Fork = false, want true")
+	}
+	if repo.MirrorURL != "https://git.example.com/repo1.git" {
+		t.Errorf("MirrorURL = %s, want the mirror URL", repo.MirrorURL)
+	}
+
+	if repo.Owner != "org" {
+		t.Errorf("Owner = %s, want org", repo.Owner)
+	}
+	if repo.License != "MIT" {
+		t.Errorf("License = %s, want MIT", repo.License)
+	}
+	if !repo.LastUpdated.Equal(pushed) {
+		t.Errorf("LastUpdated = %v, want %v", repo.LastUpdated, pushed)
+	}
+	if !repo.PushedAt.Equal(pushed) {
+		t.Errorf("PushedAt = %v, want %v", repo.PushedAt, pushed)
+	}
+	if !repo.UpdatedAt.Equal(updated) {
+		t.Errorf("UpdatedAt = %v, want %v", repo.UpdatedAt, updated)
+	}
+	if repo.CloneURL != "https://github.com/org/repo1.git" || repo.SSHURL != "git@github.com:org/repo1.git" {
+		t.Errorf("CloneURL, SSHURL = %s, %s, want the clone and SSH URLs", repo.CloneURL, repo.SSHURL)
+	}
+	if repo.DefaultBranch != "main" {
+		t.Errorf("DefaultBranch = %s, want main", repo.DefaultBranch)
+	}
+	if repo.SizeKB != 2048 {
+		t.Errorf("SizeKB = %d, want 2048", repo.SizeKB)
+	}
+	if len(repo.Topics) != 2 || repo.Topics[1] != "payments" {
+		t.Errorf("Topics = %v, want [go payments]", repo.Topics)
+	}
+
+	if repo.NeverPushed {
+		t.Error("NeverPushed = true, want false")
+	}
+	if repo.Description != "Payments API" {
+		t.Errorf("Description = %s, want Payments API", repo.Description)
+	}
+	if repo.IssuesDisabled || !repo.WikiDisabled || repo.ProjectsDisabled {
+		t.Errorf("IssuesDisabled, WikiDisabled, ProjectsDisabled = %t, %t, %t, want false, true, false",
+			repo.IssuesDisabled, repo.WikiDisabled, repo.ProjectsDisabled)
+	}
+
+	bare := ghRepo{Name: "repo2"}.toRepository()
+	if !bare.NeverPushed {
+		t.Error("NeverPushed = false for zero pushed_at, want true")
+	}
+	if bare.Owner != "" {
+		t.Errorf("Owner = %s, want empty", bare.Owner)
+	}
+	if bare.License != "" {
+		t.Errorf("License = %s, want empty", bare.License)
+	}
+}
+
+func TestSortBySize(t *testing.T) {
+	repos := []Repository{
+		{Name: "small", SizeKB: 10},
+		{Name: "large", SizeKB: 5000},
+		{Name: "medium", SizeKB: 300},
+	}
+
+	SortBySize(repos)
+
+	want := []string{"large", "medium", "small"}
+	for i, name := range want {
+		if repos[i].Name != name {
+			t.Errorf("repos[%d] = %s, want %s", i, repos[i].Name, name)
+		}
+	}
+	if got := TotalSizeKB(repos); got != 5310 {
+		t.Errorf("TotalSizeKB() = %d, want 5310", got)
+	}
+}
+
+func TestFormatSize(t *testing.T) {
+	tests := []struct {
+		kb   int64
+		want string
+	}{
+		{0, "0 KB"},
+		{512, "512 KB"},
+		{1536, "1.5 MB"},
+		{12897485, "12.3 GB"},
+		{3 * 1024 * 1024 * 1024, "3.0 TB"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatSize(tt.kb); got != tt.want {
+			t.Errorf("FormatSize(%d) = %q, want %q", tt.kb, got, tt.want)
+		}
+	}
+}
+
+func TestUseActivityMetric(t *testing.T) {
+	pushed := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	updated := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	repos := []Repository{
+		{Name: "triaged", LastUpdated: pushed, PushedAt: pushed, UpdatedAt: updated},
+		{Name: "legacy", LastUpdated: pushed}, // Cached before updated_at was stored
+	}
+
+	if got := UseActivityMetric(repos, ActivityPushed); !got[0].LastUpdated.Equal(pushed) {
+		t.Errorf("pushed: LastUpdated = %v, want %v", got[0].LastUpdated, pushed)
+	}
+
+	got := UseActivityMetric(repos, ActivityUpdated)
+	if !got[0].LastUpdated.Equal(updated) {
+		t.Errorf("updated: LastUpdated = %v, want %v", got[0].LastUpdated, updated)
+	}
+	if !got[1].LastUpdated.Equal(pushed) {
+		t.Errorf("updated: legacy LastUpdated = %v, want %v", got[1].LastUpdated, pushed)
+	}
+	if !repos[0].LastUpdated.Equal(pushed) {
+		t.Error("UseActivityMetric modified its input")
+	}
+}
+
+func TestParseActivityMetric(t *testing.T) {
+	tests := []struct {
+		input string
+		want  ActivityMetric
+		ok    bool
+	}{
+		{"pushed", ActivityPushed, true},
+		{"updated", ActivityUpdated, true},
+		{"created", "", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := ParseActivityMetric(tt.input)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("ParseActivityMetric(%q) = %q, %t, want %q, %t", tt.input, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestMonthlyActivityHistogram(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+
+	repos := []Repository{
+		{Name: "this-month", LastUpdated: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)},
+		{Name: "future", LastUpdated: now.AddDate(0, 0, 1)},
+		{Name: "last-month", LastUpdated: time.Date(2024, 5, 31, 23, 0, 0, 0, time.UTC)},
+		{Name: "window-start", LastUpdated: time.Date(2022, 7, 1, 0, 0, 0, 0, time.UTC)},
+		{Name: "just-older", LastUpdated: time.Date(2022, 6, 30, 23, 59, 0, 0, time.UTC)},
+		{Name: "ancient", LastUpdated: time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	buckets := MonthlyActivityHistogram(repos, now)
+
+	if len(buckets) != histogramMonths+1 {
+		t.Fatalf("len(buckets) = %d, want %d", len(buckets), histogramMonths+1)
+	}
+
+	if !buckets[0].Older || buckets[0].Count != 2 {
+		t.Errorf("overflow bucket = %+v, want Older with count 2", buckets[0])
+	}
+	if buckets[1].Label != "Jul 2022" || buckets[1].Count != 1 {
+		t.Errorf("first month bucket = %+v, want Jul 2022 with count 1", buckets[1])
+	}
+	if buckets[23].Label != "May 2024" || buckets[23].Count != 1 {
+		t.Errorf("previous month bucket = %+v, want May 2024 with count 1", buckets[23])
+	}
+	if buckets[24].Label != "Jun 2024" || buckets[24].Count != 2 {
+		t.Errorf("current month bucket = %+v, want Jun 2024 with count 2", buckets[24])
+	}
+
+	total := 0
+	for _, b := range buckets {
+		total += b.Count
+	}
+	if total != len(repos) {
+		t.Errorf("total count = %d, want %d", total, len(repos))
+	}
+}
+
+func TestWrapGHError(t *testing.T) {
+	execErr := errors.New("gh execution failed: exit status 1")
+
+	tests := []struct {
+		name   string
+		err    error
+		stderr string
+		want   string
+	}{
+		{"not installed", fmt.Errorf("lookup: %w", exec.ErrNotFound), "", "gh CLI not found"},
+		{"not authenticated", execErr, "To get started with GitHub CLI, please run:  gh auth login", "not authenticated"},
+		{"not logged in", execErr, "You are not logged into any GitHub hosts.", "not authenticated"},
+		{"network", execErr, "error connecting to api.github.com: dial tcp: lookup api.github.com: could not resolve host", "network error"},
+		{"not found", execErr, "gh: Not Found (HTTP 404)", "organization not found"},
+		{"other", execErr, "something unexpected", "something unexpected"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := wrapGHError(tt.err, tt.stderr)
+			if !strings.Contains(got.Error(), tt.want) {
+				t.Errorf("wrapGHError() = %q, want it to contain %q", got, tt.want)
+			}
+			if !errors.Is(got, tt.err) {
+				t.Errorf("wrapGHError() does not wrap the original error")
+			}
+		})
+	}
+}
+
+func TestGHCLIClientPrecheck(t *testing.T) {
+	var calls [][]string
+	client := &ghCLIClient{exec: func(args ...string) (stdout, stderr bytes.Buffer, err error) {
+		calls = append(calls, args)
+		if args[0] == "auth" {
+			stderr.WriteString("You are not logged into any GitHub hosts. Run gh auth login to authenticate.")
+			err = errors.New("exit status 1")
+		}
+		return
+	}}
+
+	_, err := client.FetchRepositories("org")
+	if err == nil || !strings.Contains(err.Error(), "not authenticated") {
+		t.Errorf("FetchRepositories() error = %v, want not authenticated", err)
+	}
+	if len(calls) != 2 {
+		t.Errorf("len(calls) = %d, want 2 precheck calls before any API call", len(calls))
+	}
+}
+
+// fakeGHPages returns a ghExecFunc serving the given number of repositories
+// in pages of the requested size, recording the page numbers requested.
+func fakeGHPages(t *testing.T, total int, requested *[]string) ghExecFunc {
+	return func(args ...string) (stdout, stderr bytes.Buffer, err error) {
+		var page, perPage int
+		for i, arg := range args {
+			if arg == "--paginate" {
+				t.Errorf("args contain --paginate")
+			}
+			if arg == "-f" && i+1 < len(args) {
+				fmt.Sscanf(args[i+1], "page=%d", &page)
+				fmt.Sscanf(args[i+1], "per_page=%d", &perPage)
+			}
+		}
+		*requested = append(*requested, fmt.Sprint(page))
+
+		var repos []string
+		for i := (page - 1) * perPage; i < page*perPage && i < total; i++ {
+			repos = append(repos, fmt.Sprintf(`{"name":"repo%d","full_name":"org/repo%d"}`, i, i))
+		}
+		stdout.WriteString("[" + strings.Join(repos, ",") + "]")
+		return
+	}
+}
+
+func TestGHCLIClientFetchRepositoriesPaginates(t *testing.T) {
+	var requested []string
+	client := &ghCLIClient{exec: fakeGHPages(t, 250, &requested), checked: true}
+
+	repos, err := client.FetchRepositories("org")
+	if err != nil {
+		t.Fatalf("FetchRepositories() error = %v", err)
+	}
+
+	if len(repos) != 250 {
+		t.Errorf("len(repos) = %d, want 250", len(repos))
+	}
+	if strings.Join(requested, ",") != "1,2,3" {
+		t.Errorf("requested pages = %v, want [1 2 3]", requested)
+	}
+	if repos[249].Name != "repo249" {
+		t.Errorf("repos[249].Name = %s, want repo249", repos[249].Name)
+	}
+}
+
+func TestGHCLIClientFetchRepositoriesExactPage(t *testing.T) {
+	var requested []string
+	client := &ghCLIClient{exec: fakeGHPages(t, 100, &requested), checked: true}
+
+	repos, err := client.FetchRepositories("org")
+	if err != nil {
+		t.Fatalf("FetchRepositories() error = %v", err)
+	}
+
+	if len(repos) != 100 {
+		t.Errorf("len(repos) = %d, want 100", len(repos))
+	}
+	if strings.Join(requested, ",") != "1,2" {
+		t.Errorf("requested pages = %v, want [1 2]", requested)
+	}
+}
+
+func TestRequestExecutorWaitsForRateLimitReset(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	reset := now.Add(90 * time.Second)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+	}))
+	defer server.Close()
+
+	var slept []time.Duration
+	executor := newRequestExecutor(server.Client(), 2)
+	executor.now = func() time.Time { return now }
+	executor.sleep = func(d time.Duration) { slept = append(slept, d) }
+
+	for i := 0; i < 2; i++ {
+		req, _ := http.NewRequest("GET", server.URL, nil)
+		resp, err := executor.Do(req)
+		if err != nil {
+			t.Fatalf("Do() error = %v", err)
+		}
+		resp.Body.Close()
+	}
+
+	if len(slept) != 1 || slept[0] != 90*time.Second {
+		t.Errorf("slept = %v, want [1m30s] before the second request", slept)
+	}
+}
+
+func TestRequestExecutorLimitsConcurrency(t *testing.T) {
+	var mu sync.Mutex
+	inFlight, maxInFlight := 0, 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		mu.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	executor := newRequestExecutor(server.Client(), 2)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req, _ := http.NewRequest("GET", server.URL, nil)
+			resp, err := executor.Do(req)
+			if err != nil {
+				t.Errorf("Do() error = %v", err)
+				return
+			}
+			resp.Body.Close()
+		}()
+	}
+	wg.Wait()
+
+	if maxInFlight > 2 {
+		t.Errorf("maxInFlight = %d, want at most 2", maxInFlight)
+	}
+}
+
+func TestTokenClientFetchRepositoriesPaginates(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer ghp_token" {
+			t.Errorf("Authorization = %q, want Bearer ghp_token", r.Header.Get("Authorization"))
+		}
+		switch r.URL.Query().Get("page") {
+		case "1":
+			w.Header().Set("Link", fmt.Sprintf(`<%s/orgs/org/repos?page=2>; rel="next"`, server.URL))
+			fmt.Fprint(w, `[{"name":"repo1"},{"name":"archived","archived":true}]`)
+		case "2":
+			fmt.Fprint(w, `[{"name":"repo2"}]`)
+		default:
+			t.Errorf("unexpected page %q", r.URL.Query().Get("page"))
+		}
+	}))
+	defer server.Close()
+
+	client := &tokenClient{
+		token:    "ghp_token",
+		baseURL:  server.URL,
+		executor: newRequestExecutor(server.Client(), 1),
+	}
+
+	repos, err := client.FetchRepositories("org")
+	if err != nil {
+		t.Fatalf("FetchRepositories() error = %v", err)
+	}
+
+	// Archived repositories are kept and flagged; scans leave them out unless asked
+	if len(repos) != 3 || repos[0].Name != "repo1" || repos[2].Name != "repo2" {
+		t.Errorf("repos = %v, want [repo1 archived repo2]", repos)
+	}
+	if len(repos) > 1 && !repos[1].Archived {
+		t.Error("repos[1].Archived = false, want true")
+	}
+}
+
+func TestTokenClientFetchRepositoriesConditional(t *testing.T) {
+	var server *httptest.Server
+	page2Changed := false
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("page") {
+		case "1":
+			if r.Header.Get("If-None-Match") == `"etag-1"` {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", `"etag-1"`)
+			w.Header().Set("Link", fmt.Sprintf(`<%s/orgs/org/repos?page=2>; rel="next"`, server.URL))
+			fmt.Fprint(w, `[{"name":"repo1"},{"name":"archived","archived":true},{"name":"repo2"}]`)
+		case "2":
+			if r.Header.Get("If-None-Match") == `"etag-2"` && !page2Changed {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", `"etag-2b"`)
+			fmt.Fprint(w, `[{"name":"repo3"},{"name":"repo4"}]`)
+		}
+	}))
+	defer server.Close()
+
+	client := &tokenClient{
+		token:    "ghp_token",
+		baseURL:  server.URL,
+		executor: newRequestExecutor(server.Client(), 1),
+	}
+
+	repos, pages, err := client.FetchRepositoriesConditional("org", nil)
+	if err != nil {
+		t.Fatalf("FetchRepositoriesConditional() error = %v", err)
+	}
+	if len(repos) != 5 || len(pages) != 2 {
+		t.Fatalf("len(repos) = %d, len(pages) = %d, want 5 and 2", len(repos), len(pages))
+	}
+	if pages[0] != (PageETag{ETag: `"etag-1"`, Count: 3}) {
+		t.Errorf("pages[0] = %+v, want etag-1 with count 3", pages[0])
+	}
+
+	// Pretend the cached second page was different, so only it is refetched
+	previous := &OrganizationCache{
+		Repositories: []Repository{{Name: "repo1"}, {Name: "archived", Archived: true}, {Name: "repo2"}, {Name: "old3"}},
+		PageETags:    []PageETag{{ETag: `"etag-1"`, Count: 3}, {ETag: `"etag-2"`, Count: 1}},
+	}
+	page2Changed = true
+
+	repos, pages, err = client.FetchRepositoriesConditional("org", previous)
+	if err != nil {
+		t.Fatalf("FetchRepositoriesConditional() error = %v", err)
+	}
+
+	var names []string
+	for _, repo := range repos {
+		names = append(names, repo.Name)
+	}
+	if strings.Join(names, ",") != "repo1,archived,repo2,repo3,repo4" {
+		t.Errorf("repos = %v, want [repo1 archived repo2 repo3 repo4]", names)
+	}
+	if pages[1].ETag != `"etag-2b"` {
+		t.Errorf("pages[1].ETag = %s, want etag-2b", pages[1].ETag)
+	}
+
+	// Every page unchanged reuses the cache entirely
+	page2Changed = false
+	repos, _, err = client.FetchRepositoriesConditional("org", previous)
+	if err != nil {
+		t.Fatalf("FetchRepositoriesConditional() error = %v", err)
+	}
+	if len(repos) != 4 || repos[3].Name != "old3" {
+		t.Errorf("repos = %v, want cached [repo1 archived repo2 old3]", repos)
+	}
+}
+
+func TestOrganizationCachePage(t *testing.T) {
+	cache := &OrganizationCache{
+		Repositories: []Repository{{Name: "a"}, {Name: "b"}, {Name: "c"}},
+		PageETags:    []PageETag{{ETag: "1", Count: 2}, {ETag: "2", Count: 1}, {ETag: "3", Count: 5}},
+	}
+
+	p, repos, ok := cache.page(2)
+	if !ok || p.ETag != "2" || len(repos) != 1 || repos[0].Name != "c" {
+		t.Errorf("page(2) = %+v, %v, %v, want etag 2 with [c]", p, repos, ok)
+	}
+
+	// Counts that overrun the cached repositories are ignored
+	if _, _, ok := cache.page(3); ok {
+		t.Error("page(3) ok = true for inconsistent counts, want false")
+	}
+	if _, _, ok := cache.page(4); ok {
+		t.Error("page(4) ok = true beyond cached pages, want false")
+	}
+
+	var nilCache *OrganizationCache
+	if _, _, ok := nilCache.page(1); ok {
+		t.Error("page(1) ok = true on nil cache, want false")
+	}
+}
+
+func TestGHCLIClientRetriesTransientErrors(t *testing.T) {
+	attempts := 0
+	var slept []time.Duration
+	client := &ghCLIClient{
+		checked: true,
+		sleep:   func(d time.Duration) { slept = append(slept, d) },
+		exec: func(args ...string) (stdout, stderr bytes.Buffer, err error) {
+			attempts++
+			if attempts < 3 {
+				stderr.WriteString("gh: Server Error (HTTP 502)")
+				err = errors.New("exit status 1")
+				return
+			}
+			stdout.WriteString(`[{"name":"repo1"}]`)
+			return
+		},
+	}
+
+	repos, err := client.FetchRepositories("org")
+	if err != nil {
+		t.Fatalf("FetchRepositories() error = %v", err)
+	}
+	if len(repos) != 1 {
+		t.Errorf("len(repos) = %d, want 1", len(repos))
+	}
+	if attempts != 3 {
+		t.Errorf("attempts = %d, want 3", attempts)
+	}
+	if len(slept) != 2 || slept[0] != ghRetryBackoff || slept[1] != 2*ghRetryBackoff {
+		t.Errorf("slept = %v, want [%v %v]", slept, ghRetryBackoff, 2*ghRetryBackoff)
+	}
+}
+
+func TestGHCLIClientGivesUpAfterMaxAttempts(t *testing.T) {
+	attempts := 0
+	client := &ghCLIClient{
+		checked: true,
+		sleep:   func(time.Duration) {},
+		exec: func(args ...string) (stdout, stderr bytes.Buffer, err error) {
+			attempts++
+			stderr.WriteString("gh: API rate limit exceeded for user ID 1. (HTTP 403)")
+			err = errors.New("exit status 1")
+			return
+		},
+	}
+
+	_, err := client.FetchRepositories("org")
+	if err == nil || !strings.Contains(err.Error(), "rate limit exceeded") {
+		t.Errorf("FetchRepositories() error = %v, want rate limit error", err)
+	}
+	if attempts != ghMaxAttempts {
+		t.Errorf("attempts = %d, want %d", attempts, ghMaxAttempts)
+	}
+}
+
+func TestGHCLIClientDoesNotRetryPermanentErrors(t *testing.T) {
+	attempts := 0
+	client := &ghCLIClient{
+		checked: true,
+		sleep:   func(time.Duration) { t.Error("sleep called for permanent error") },
+		exec: func(args ...string) (stdout, stderr bytes.Buffer, err error) {
+			attempts++
+			stderr.WriteString("gh: Not Found (HTTP 404)")
+			err = errors.New("exit status 1")
+			return
+		},
+	}
+
+	if _, err := client.FetchRepositories("org"); err == nil {
+		t.Error("FetchRepositories() error = nil, want error")
+	}
+	if attempts != 1 {
+		t.Errorf("attempts = %d, want 1", attempts)
+	}
+}
+
+func TestScannerReportsCacheWriteErr(t *testing.T) {
+	// A cache directory beneath a regular file cannot be created
+	blocker := filepath.Join(t.TempDir(), "blocker")
+	if err := os.WriteFile(blocker, nil, 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	cache := NewCacheWithDir(filepath.Join(blocker, "cache"))
+
+	mockClient := &mockGitHubClient{
+		repos: []Repository{{Name: "repo1", LastUpdated: time.Now()}},
+	}
+	scanner := NewScannerWithDeps(mockClient, cache)
+
+	result, err := scanner.Scan("org", ScanOptions{})
+	if err != nil {
+		t.Fatalf("Scan() error = %v, want nil for a cache write failure", err)
+	}
+	if result.CacheWriteErr == nil || result.HistoryErr == nil {
+		t.Errorf("CacheWriteErr = %v, HistoryErr = %v; want both set", result.CacheWriteErr, result.HistoryErr)
+	}
+	if len(result.Repositories) != 1 {
+		t.Errorf("len(result.Repositories) = %d, want 1", len(result.Repositories))
+	}
+}
+
+func TestDetectRenames(t *testing.T) {
+	previous := []Repository{
+		{ID: 1, Name: "old-api"},
+		{ID: 2, Name: "web"},
+		{ID: 3, Name: "deleted"},
+		{Name: "legacy-no-id"},
+	}
+	current := []Repository{
+		{ID: 1, Name: "api"},
+		{ID: 2, Name: "web"},
+		{ID: 4, Name: "added"},
+		{Name: "renamed-no-id"},
+	}
+
+	renames := DetectRenames(previous, current)
+
+	if len(renames) != 1 {
+		t.Fatalf("len(renames) = %d, want 1: %v", len(renames), renames)
+	}
+	if renames[0] != (Rename{ID: 1, OldName: "old-api", NewName: "api"}) {
+		t.Errorf("renames[0] = %+v, want old-api → api", renames[0])
+	}
+}
+
+func TestDetectChanges(t *testing.T) {
+	before := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := before.AddDate(0, 3, 0)
+
+	previous := []Repository{
+		{ID: 1, Name: "api", FullName: "org/api", LastUpdated: before.AddDate(0, 0, -1)},
+		{ID: 2, Name: "old-name", FullName: "org/old-name", LastUpdated: before.AddDate(-1, 0, 0)},
+		{Name: "docs", FullName: "org/docs", LastUpdated: before.AddDate(-1, 0, 0)},
+		{ID: 4, Name: "gone", FullName: "org/gone", LastUpdated: before},
+	}
+	current := []Repository{
+		{ID: 1, Name: "api", FullName: "org/api", LastUpdated: before.AddDate(0, 0, -1)},   // Aged from green to yellow
+		{ID: 2, Name: "new-name", FullName: "org/new-name", LastUpdated: now},              // Renamed and pushed to
+		{ID: 3, Name: "docs", FullName: "org/docs", LastUpdated: before.AddDate(-1, 0, 0)}, // Matched by name
+		{ID: 5, Name: "web", FullName: "org/web", LastUpdated: now},
+	}
+
+	got := DetectChanges(previous, current, before, now)
+	want := Changes{Added: 1, Removed: 1, FreshnessChanged: 2}
+	if got != want {
+		t.Errorf("DetectChanges() = %+v, want %+v", got, want)
+	}
+}
+
+func TestScannerDetectsChangesOnRefresh(t *testing.T) {
+	cache := NewCacheWithDir(t.TempDir())
+	now := time.Now()
+	mockClient := &mockGitHubClient{
+		repos: []Repository{{ID: 1, Name: "api", LastUpdated: now}},
+	}
+	scanner := NewScannerWithDeps(mockClient, cache)
+
+	result, err := scanner.Scan("org", ScanOptions{})
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if result.Changes != nil {
+		t.Errorf("result.Changes = %+v without a previous fetch, want nil", result.Changes)
+	}
+
+	mockClient.repos = []Repository{{ID: 1, Name: "api", LastUpdated: now}, {ID: 2, Name: "web", LastUpdated: now}}
+	result, err = scanner.Scan("org", ScanOptions{Refresh: true})
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+
+	if result.Changes == nil || *result.Changes != (Changes{Added: 1}) {
+		t.Errorf("result.Changes = %+v, want 1 added", result.Changes)
+	}
+}
+
+func TestScannerDetectsRenamesOnRefresh(t *testing.T) {
+	cache := NewCacheWithDir(t.TempDir())
+	now := time.Now()
+	mockClient := &mockGitHubClient{
+		repos: []Repository{{ID: 7, Name: "repo-old", LastUpdated: now}},
+	}
+	scanner := NewScannerWithDeps(mockClient, cache)
+
+	if _, err := scanner.Scan("org", ScanOptions{}); err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+
+	mockClient.repos = []Repository{{ID: 7, Name: "repo-new", LastUpdated: now}}
+	result, err := scanner.Scan("org", ScanOptions{Refresh: true})
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+
+	if len(result.Renamed) != 1 || result.Renamed[0].OldName != "repo-old" || result.Renamed[0].NewName != "repo-new" {
+		t.Errorf("result.Renamed = %v, want repo-old → repo-new", result.Renamed)
+	}
+}
+
+func TestAPIError(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		body       string
+		want       string
+		wantAccess bool
+	}{
+		{"suspended", http.StatusForbidden, `{"message":"Organization is suspended"}`, "no access to org 'org' — check token scopes or org membership (Organization is suspended)", true},
+		{"rate limit", http.StatusForbidden, `{"message":"API rate limit exceeded for user ID 1."}`, "rate limit exceeded", false},
+		{"secondary rate limit", http.StatusTooManyRequests, `{"message":"You have exceeded a secondary rate limit."}`, "rate limit exceeded", false},
+		{"other", http.StatusInternalServerError, `oops`, "GitHub API error: oops (status 500)", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := apiError("org", tt.status, []byte(tt.body))
+			if !strings.Contains(got.Error(), tt.want) {
+				t.Errorf("apiError() = %q, want it to contain %q", got, tt.want)
+			}
+			if errors.Is(got, ErrNoAccess) != tt.wantAccess {
+				t.Errorf("errors.Is(apiError(), ErrNoAccess) = %t, want %t", !tt.wantAccess, tt.wantAccess)
+			}
+		})
+	}
+
+	got := apiError("org/repo", http.StatusForbidden, []byte(`{"message":"Resource not accessible by integration"}`))
+	want := "no access to repository 'org/repo' — check token scopes or repository access (Resource not accessible by integration)"
+	if got.Error() != want || !errors.Is(got, ErrNoAccess) {
+		t.Errorf("apiError() for a repository = %q, want %q", got, want)
+	}
+}
+
+func TestGHCLIClientReportsNoAccess(t *testing.T) {
+	client := &ghCLIClient{
+		checked: true,
+		exec: func(args ...string) (stdout, stderr bytes.Buffer, err error) {
+			stderr.WriteString("gh: Resource protected by organization SAML enforcement. (HTTP 403)")
+			err = errors.New("exit status 1")
+			return
+		},
+	}
+
+	_, err := client.FetchRepositories("org")
+	if !errors.Is(err, ErrNoAccess) {
+		t.Fatalf("FetchRepositories() error = %v, want ErrNoAccess", err)
+	}
+	if !strings.Contains(err.Error(), "no access to org 'org'") {
+		t.Errorf("FetchRepositories() error = %q, want it to name the organization", err)
+	}
+}
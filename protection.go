@@ -0,0 +1,139 @@
+package patina
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// ProtectionStatus records whether a repository's default branch is protected.
+type ProtectionStatus string
+
+const (
+	ProtectionProtected   ProtectionStatus = "protected"
+	ProtectionUnprotected ProtectionStatus = "unprotected"
+
+	// ProtectionUnknown means the token may not read the branch's protection,
+	// which GitHub only reveals to repository administrators.
+	ProtectionUnknown ProtectionStatus = "unknown"
+)
+
+// protectionFetcher is implemented by clients that can look up branch protection.
+type protectionFetcher interface {
+	FetchProtection(fullName, branch string) (ProtectionStatus, error)
+}
+
+// protectionPath returns the API path of a branch's protection settings.
+func protectionPath(fullName, branch string) string {
+	return fmt.Sprintf("/repos/%s/branches/%s/protection", fullName, url.PathEscape(branch))
+}
+
+// FetchProtection looks up whether a branch is protected using the GitHub API with a token.
+// GitHub answers 404 for unprotected branches and 403 when the token is not an administrator's.
+func (c *tokenClient) FetchProtection(fullName, branch string) (ProtectionStatus, error) {
+	url := c.baseURL + protectionPath(fullName, branch)
+
+	slog.Debug("requesting branch protection", "url", url)
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+
+	resp, err := c.executor.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch branch protection: %w", err)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return ProtectionProtected, nil
+	case http.StatusNotFound:
+		return ProtectionUnprotected, nil
+	}
+	err = apiError(fullName, resp.StatusCode, body)
+	if errors.Is(err, ErrNoAccess) {
+		return ProtectionUnknown, nil
+	}
+	return "", err
+}
+
+// FetchProtection looks up whether a branch is protected using an installation token.
+func (c *appClient) FetchProtection(fullName, branch string) (ProtectionStatus, error) {
+	token, err := c.installationToken()
+	if err != nil {
+		return "", err
+	}
+
+	client := &tokenClient{token: token, baseURL: c.baseURL, executor: c.executor}
+	return client.FetchProtection(fullName, branch)
+}
+
+// FetchProtection looks up whether a branch is protected using the gh CLI.
+func (c *ghCLIClient) FetchProtection(fullName, branch string) (ProtectionStatus, error) {
+	args := []string{"api", "--method", "GET", protectionPath(fullName, branch)}
+
+	slog.Debug("running gh", "args", args)
+
+	_, stderr, err := c.run(args...)
+	if err != nil {
+		switch {
+		case strings.Contains(stderr.String(), "HTTP 404"):
+			return ProtectionUnprotected, nil
+		case isGHAccessDenied(stderr.String()):
+			return ProtectionUnknown, nil
+		}
+		return "", wrapGHError(err, stderr.String())
+	}
+
+	return ProtectionProtected, nil
+}
+
+// enrichMissingProtection looks up the default branch protection of only
+// those repositories that have not been checked, reporting whether any were.
+// Failures for individual repositories are logged and leave them unchecked.
+func enrichMissingProtection(fetcher protectionFetcher, repos []Repository) bool {
+	sem := make(chan struct{}, defaultConcurrency)
+	var wg sync.WaitGroup
+
+	checked := false
+	for i := range repos {
+		repo := &repos[i]
+		if repo.Protection != "" || repo.DefaultBranch == "" {
+			continue
+		}
+		checked = true
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			status, err := fetcher.FetchProtection(repo.FullName, repo.DefaultBranch)
+			if err != nil {
+				slog.Warn("failed to fetch branch protection", "repo", repo.FullName, "error", err)
+				return
+			}
+			repo.Protection = status
+		}()
+	}
+
+	wg.Wait()
+	return checked
+}
@@ -0,0 +1,134 @@
+package patina
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// mockProtectionClient is a mockGitHubClient that can also look up branch protection.
+type mockProtectionClient struct {
+	mockGitHubClient
+	protection map[string]ProtectionStatus
+
+	protectionMu      sync.Mutex
+	protectionLookups int
+}
+
+func (m *mockProtectionClient) FetchProtection(fullName, branch string) (ProtectionStatus, error) {
+	m.protectionMu.Lock()
+	defer m.protectionMu.Unlock()
+	m.protectionLookups++
+
+	status, ok := m.protection[fullName]
+	if !ok {
+		return "", fmt.Errorf("no protection for %s", fullName)
+	}
+	return status, nil
+}
+
+func TestTokenClientFetchProtection(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/org/protected/branches/main/protection":
+			fmt.Fprint(w, `{"url":"https://api.github.com/repos/org/protected/branches/main/protection"}`)
+		case "/repos/org/open/branches/main/protection":
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message":"Branch not protected"}`)
+		case "/repos/org/admin-only/branches/main/protection":
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"message":"Resource not accessible by integration"}`)
+		case "/repos/org/limited/branches/main/protection":
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"message":"API rate limit exceeded"}`)
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := &tokenClient{
+		token:    "ghp_token",
+		baseURL:  server.URL,
+		executor: newRequestExecutor(server.Client(), 1),
+	}
+
+	tests := []struct {
+		repo string
+		want ProtectionStatus
+	}{
+		{"org/protected", ProtectionProtected},
+		{"org/open", ProtectionUnprotected},
+		{"org/admin-only", ProtectionUnknown},
+	}
+	for _, tt := range tests {
+		got, err := client.FetchProtection(tt.repo, "main")
+		if err != nil {
+			t.Errorf("FetchProtection(%s) error = %v", tt.repo, err)
+		}
+		if got != tt.want {
+			t.Errorf("FetchProtection(%s) = %s, want %s", tt.repo, got, tt.want)
+		}
+	}
+
+	if _, err := client.FetchProtection("org/limited", "main"); err == nil {
+		t.Error("FetchProtection() error = nil when rate limited, want error")
+	}
+}
+
+func TestScannerWithProtection(t *testing.T) {
+	cache := NewCacheWithDir(t.TempDir())
+	now := time.Now()
+
+	mockClient := &mockProtectionClient{
+		mockGitHubClient: mockGitHubClient{
+			repos: []Repository{
+				{Name: "repo1", FullName: "org/repo1", DefaultBranch: "main", LastUpdated: now},
+				{Name: "repo2", FullName: "org/repo2", DefaultBranch: "main", LastUpdated: now},
+				{Name: "repo3", FullName: "org/repo3", DefaultBranch: "main", LastUpdated: now},
+				{Name: "empty", FullName: "org/empty", NeverPushed: true},
+			},
+		},
+		protection: map[string]ProtectionStatus{
+			"org/repo1": ProtectionProtected,
+			"org/repo2": ProtectionUnknown,
+		},
+	}
+	scanner := NewScannerWithDeps(mockClient, cache)
+
+	// Protection is skipped unless requested
+	if _, err := scanner.Scan("org", ScanOptions{}); err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if mockClient.protectionLookups != 0 {
+		t.Errorf("protectionLookups = %d without WithProtection, want 0", mockClient.protectionLookups)
+	}
+
+	// Repositories without a default branch are not looked up; failures leave them unchecked
+	result, err := scanner.Scan("org", ScanOptions{WithProtection: true})
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if mockClient.protectionLookups != 3 {
+		t.Errorf("protectionLookups = %d, want 3", mockClient.protectionLookups)
+	}
+
+	want := map[string]ProtectionStatus{"repo1": ProtectionProtected, "repo2": ProtectionUnknown, "repo3": ""}
+	for name, status := range want {
+		repo, _ := FindRepository(result.Repositories, name)
+		if repo.Protection != status {
+			t.Errorf("%s.Protection = %q, want %q", name, repo.Protection, status)
+		}
+	}
+
+	// Checked repositories are cached, so only the failed lookup is retried
+	if _, err := scanner.Scan("org", ScanOptions{WithProtection: true}); err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if mockClient.protectionLookups != 4 {
+		t.Errorf("protectionLookups = %d, want 4", mockClient.protectionLookups)
+	}
+}
@@ -0,0 +1,147 @@
+package patina
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// rateLimitPath is the API path reporting the rate limit of each resource.
+// Requests to it do not count against the limit.
+const rateLimitPath = "/rate_limit"
+
+// RateLimit is the quota of one GitHub API resource.
+type RateLimit struct {
+	Limit     int       `json:"limit"`
+	Used      int       `json:"used"`
+	Remaining int       `json:"remaining"`
+	Reset     time.Time `json:"reset"`
+}
+
+// RateLimits is the quota of each GitHub API resource patina may use.
+type RateLimits struct {
+	Core    RateLimit `json:"core"`
+	Search  RateLimit `json:"search"`
+	GraphQL RateLimit `json:"graphql"`
+}
+
+// rateLimitFetcher is implemented by clients that can look up their rate limits.
+type rateLimitFetcher interface {
+	FetchRateLimits() (RateLimits, error)
+}
+
+// ghRateLimit is one resource in a rate limit response.
+type ghRateLimit struct {
+	Limit     int   `json:"limit"`
+	Used      int   `json:"used"`
+	Remaining int   `json:"remaining"`
+	Reset     int64 `json:"reset"`
+}
+
+func (r ghRateLimit) toRateLimit() RateLimit {
+	return RateLimit{
+		Limit:     r.Limit,
+		Used:      r.Used,
+		Remaining: r.Remaining,
+		Reset:     time.Unix(r.Reset, 0),
+	}
+}
+
+// parseRateLimits converts a rate limit response.
+func parseRateLimits(body []byte) (RateLimits, error) {
+	var resp struct {
+		Resources struct {
+			Core    ghRateLimit `json:"core"`
+			Search  ghRateLimit `json:"search"`
+			GraphQL ghRateLimit `json:"graphql"`
+		} `json:"resources"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return RateLimits{}, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return RateLimits{
+		Core:    resp.Resources.Core.toRateLimit(),
+		Search:  resp.Resources.Search.toRateLimit(),
+		GraphQL: resp.Resources.GraphQL.toRateLimit(),
+	}, nil
+}
+
+// FetchRateLimits looks up the remaining API quota of the credentials a
+// client uses, without spending any of it.
+func FetchRateLimits(client GitHubClient) (RateLimits, error) {
+	fetcher, ok := client.(rateLimitFetcher)
+	if !ok {
+		return RateLimits{}, errors.New("GitHub client cannot fetch rate limits")
+	}
+	return fetcher.FetchRateLimits()
+}
+
+// FetchRateLimits retrieves the rate limits using the GitHub API with a token.
+func (c *tokenClient) FetchRateLimits() (RateLimits, error) {
+	url := c.baseURL + rateLimitPath
+
+	slog.Debug("requesting rate limits", "url", url)
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return RateLimits{}, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+
+	resp, err := c.executor.Do(req)
+	if err != nil {
+		return RateLimits{}, fmt.Errorf("failed to fetch rate limits: %w", err)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return RateLimits{}, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return RateLimits{}, apiError("", resp.StatusCode, body)
+	}
+	return parseRateLimits(body)
+}
+
+// FetchRateLimits retrieves the rate limits of the installation token.
+func (c *appClient) FetchRateLimits() (RateLimits, error) {
+	token, err := c.installationToken()
+	if err != nil {
+		return RateLimits{}, err
+	}
+
+	client := &tokenClient{token: token, baseURL: c.baseURL, executor: c.executor}
+	return client.FetchRateLimits()
+}
+
+// FetchRateLimits retrieves the rate limits using the gh CLI.
+func (c *ghCLIClient) FetchRateLimits() (RateLimits, error) {
+	if err := c.ensureChecked(); err != nil {
+		return RateLimits{}, err
+	}
+
+	args := []string{"api", "--method", "GET", rateLimitPath}
+
+	slog.Debug("running gh", "args", args)
+
+	stdout, stderr, err := c.run(args...)
+	if err != nil {
+		return RateLimits{}, wrapGHError(err, stderr.String())
+	}
+	return parseRateLimits(stdout.Bytes())
+}
+
+// FetchRateLimits retrieves the rate limits through the REST API, which
+// reports the GraphQL quota alongside the others.
+func (c *graphqlClient) FetchRateLimits() (RateLimits, error) {
+	return c.rest.FetchRateLimits()
+}
@@ -0,0 +1,102 @@
+package patina
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const rateLimitResponse = `{"resources":{
+	"core":{"limit":5000,"used":10,"remaining":4990,"reset":1718452800},
+	"search":{"limit":30,"used":0,"remaining":30,"reset":1718449260},
+	"graphql":{"limit":5000,"used":0,"remaining":5000,"reset":1718452800}}}`
+
+func TestTokenClientFetchRateLimits(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rate_limit" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer ghp_token" {
+			t.Errorf("Authorization = %q", got)
+		}
+		fmt.Fprint(w, rateLimitResponse)
+	}))
+	defer server.Close()
+
+	client := &tokenClient{
+		token:    "ghp_token",
+		baseURL:  server.URL,
+		executor: newRequestExecutor(server.Client(), 1),
+	}
+
+	limits, err := FetchRateLimits(client)
+	if err != nil {
+		t.Fatalf("FetchRateLimits() error = %v", err)
+	}
+
+	want := RateLimit{Limit: 5000, Used: 10, Remaining: 4990, Reset: time.Unix(1718452800, 0)}
+	if limits.Core != want {
+		t.Errorf("Core = %+v, want %+v", limits.Core, want)
+	}
+	if limits.Search.Remaining != 30 || limits.GraphQL.Limit != 5000 {
+		t.Errorf("FetchRateLimits() = %+v", limits)
+	}
+}
+
+func TestTokenClientFetchRateLimitsBadCredentials(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"message":"Bad credentials"}`)
+	}))
+	defer server.Close()
+
+	client := &tokenClient{token: "bad", baseURL: server.URL, executor: newRequestExecutor(server.Client(), 1)}
+
+	_, err := client.FetchRateLimits()
+	if err == nil || !strings.Contains(err.Error(), "status 401") {
+		t.Errorf("FetchRateLimits() error = %v, want status 401", err)
+	}
+}
+
+func TestTokenClientFetchRateLimitsAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"message":"Not Found"}`)
+	}))
+	defer server.Close()
+
+	client := &tokenClient{token: "ghp_token", baseURL: server.URL, executor: newRequestExecutor(server.Client(), 1)}
+
+	_, err := client.FetchRateLimits()
+	if err == nil || err.Error() != "GitHub API error: Not Found (status 404)" {
+		t.Errorf("FetchRateLimits() error = %v, want GitHub's message", err)
+	}
+}
+
+func TestGHCLIClientFetchRateLimits(t *testing.T) {
+	client := &ghCLIClient{checked: true, exec: func(args ...string) (stdout, stderr bytes.Buffer, err error) {
+		if got := strings.Join(args, " "); got != "api --method GET /rate_limit" {
+			t.Errorf("gh args = %q", got)
+		}
+		stdout.WriteString(rateLimitResponse)
+		return stdout, stderr, nil
+	}}
+
+	limits, err := client.FetchRateLimits()
+	if err != nil {
+		t.Fatalf("FetchRateLimits() error = %v", err)
+	}
+	if limits.Core.Remaining != 4990 || !limits.Search.Reset.Equal(time.Unix(1718449260, 0)) {
+		t.Errorf("FetchRateLimits() = %+v", limits)
+	}
+}
+
+func TestFetchRateLimitsUnsupported(t *testing.T) {
+	if _, err := FetchRateLimits(&mockGitHubClient{}); err == nil {
+		t.Error("FetchRateLimits() error = nil for a client without rate limits")
+	}
+}
@@ -0,0 +1,836 @@
+package patina
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+)
+
+// sparklineScans is the number of most recent scans shown in the red trend sparkline.
+const sparklineScans = 30
+
+// ReportInput is the data rendered by RenderHTMLReport.
+type ReportInput struct {
+	Organization    string
+	GeneratedAt     string
+	LastCommit      bool
+	Protection      bool
+	ExcludedForks   int
+	ExcludedMirrors int
+	NeverPushed     int
+	Ownership       bool
+
+	// StaleIssuesDisabled counts stale repositories with issues disabled
+	StaleIssuesDisabled int
+
+	Labels      ReportLabels
+	Summary     FreshnessSummary
+	Groups      []ReportGroup
+	Owners      []ReportOwner
+	Histogram   []ReportHistogramBar
+	Trend       *ReportTrend
+	GreenPct    float64
+	YellowPct   float64
+	RedPct      float64
+	ArchivedPct float64
+	ExemptedPct float64
+	ColourBlind bool // Use the colour-blind palette, with shapes on status badges
+}
+
+// ReportLabels describes the age range of each freshness bucket.
+type ReportLabels struct {
+	Green  string
+	Yellow string
+	Red    string
+}
+
+// NewReportLabels describes the freshness buckets for the given yellow and red thresholds in months.
+func NewReportLabels(yellowMonths, redMonths int) ReportLabels {
+	return ReportLabels{
+		Green:  fmt.Sprintf("≤%d months", yellowMonths),
+		Yellow: fmt.Sprintf("%d-%d months", yellowMonths, redMonths),
+		Red:    fmt.Sprintf(">%d months", redMonths),
+	}
+}
+
+// ReportRepository is a repository row in the report.
+type ReportRepository struct {
+	Name        string
+	FullName    string
+	URL         string
+	Description string
+	ShortDesc   string // Description shortened for the table
+	Search      string // Lowercased name and description matched by the search box
+	Age         string
+	Date        string // Last updated date, shown beside the relative age
+	LastCommit  string
+	License     string
+	Protection  string
+	Team        string
+	Freshness   string
+	Abandoned   bool   // Stale with issues disabled, often a sign of intent to archive
+	Exemption   string // Why a stale repository is exempt from the red count
+	Countdown   string // Days until the next freshness level, such as "12 days until red"
+	ColourClass string
+	Style       template.CSS // Inline badge colour, for a gradient by exact age
+}
+
+// ReportGroup is a collapsible section of repositories at one freshness level.
+type ReportGroup struct {
+	Label        string
+	ColourClass  string
+	Open         bool
+	Repositories []ReportRepository
+}
+
+// ReportHistogramBar is one month of the activity histogram.
+type ReportHistogramBar struct {
+	Label     string
+	Count     int
+	HeightPct float64
+}
+
+// ReportTrend is the sparkline of red repositories over recent scans.
+type ReportTrend struct {
+	Points string
+	Scans  int
+	From   string
+	To     string
+	Latest int
+}
+
+// NewReportTrend builds the red-count sparkline from the most recent history
+// entries. It returns nil when there are too few entries to draw a trend.
+func NewReportTrend(history []HistoryEntry) *ReportTrend {
+	if len(history) > sparklineScans {
+		history = history[len(history)-sparklineScans:]
+	}
+	if len(history) < 2 {
+		return nil
+	}
+
+	values := make([]int, len(history))
+	for i, entry := range history {
+		values[i] = entry.Red
+	}
+
+	return &ReportTrend{
+		Points: sparklinePoints(values, 300, 40),
+		Scans:  len(history),
+		From:   history[0].Timestamp.Format("2006-01-02"),
+		To:     history[len(history)-1].Timestamp.Format("2006-01-02"),
+		Latest: values[len(values)-1],
+	}
+}
+
+// sparklinePoints scales values into SVG polyline points within a width by
+// height box, with larger values drawn higher.
+func sparklinePoints(values []int, width, height float64) string {
+	lo, hi := values[0], values[0]
+	for _, v := range values {
+		lo = min(lo, v)
+		hi = max(hi, v)
+	}
+
+	var b strings.Builder
+	for i, v := range values {
+		x := float64(i) / float64(len(values)-1) * width
+		y := height / 2
+		if hi > lo {
+			y = height - float64(v-lo)/float64(hi-lo)*height
+		}
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		fmt.Fprintf(&b, "%.1f,%.1f", x, y)
+	}
+	return b.String()
+}
+
+// ReportOwner is an owner or team with its stale repositories.
+type ReportOwner struct {
+	Owner   string
+	Summary FreshnessSummary
+	Stale   []ReportRepository
+}
+
+// RenderHTMLReport writes the HTML freshness report for data to w.
+func RenderHTMLReport(w io.Writer, data ReportInput) error {
+	tmpl, err := parseReportTemplate()
+	if err != nil {
+		return err
+	}
+	if err := tmpl.Execute(w, data); err != nil {
+		return fmt.Errorf("failed to generate report: %w", err)
+	}
+	return nil
+}
+
+// parseReportTemplate parses the per-organization report template.
+func parseReportTemplate() (*template.Template, error) {
+	funcMap := template.FuncMap{
+		"add": func(a, b interface{}) float64 {
+			var af, bf float64
+			switch v := a.(type) {
+			case int:
+				af = float64(v)
+			case float64:
+				af = v
+			}
+			switch v := b.(type) {
+			case int:
+				bf = float64(v)
+			case float64:
+				bf = v
+			}
+			return af + bf
+		},
+	}
+	tmpl, err := template.New("report").Funcs(funcMap).Parse(HTMLReportTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// HTMLReportTemplate is the html/template source rendered by RenderHTMLReport.
+const HTMLReportTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>Repository Freshness Report - {{.Organization}}</title>
+    <style>
+        * {
+            box-sizing: border-box;
+            margin: 0;
+            padding: 0;
+        }
+        body {
+            font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, Oxygen, Ubuntu, sans-serif;
+            line-height: 1.6;
+            color: #333;
+            background: #f5f5f5;
+            padding: 2rem;
+        }
+        .container {
+            max-width: 1200px;
+            margin: 0 auto;
+        }
+        h1 {
+            color: #24292e;
+            margin-bottom: 0.5rem;
+        }
+        .subtitle {
+            color: #586069;
+            margin-bottom: 2rem;
+        }
+        .summary-grid {
+            display: grid;
+            grid-template-columns: repeat(auto-fit, minmax(200px, 1fr));
+            gap: 1rem;
+            margin-bottom: 2rem;
+        }
+        .summary-card {
+            background: white;
+            border-radius: 8px;
+            padding: 1.5rem;
+            box-shadow: 0 1px 3px rgba(0,0,0,0.1);
+            text-align: center;
+        }
+        .summary-card.green { border-left: 4px solid #28a745; }
+        .summary-card.yellow { border-left: 4px solid #ffc107; }
+        .summary-card.red { border-left: 4px solid #dc3545; }
+        .summary-card.archived { border-left: 4px solid #adb5bd; }
+        .summary-card.exempted { border-left: 4px solid #e9a9b0; }
+        .summary-card.total { border-left: 4px solid #6c757d; }
+        .summary-number {
+            font-size: 2.5rem;
+            font-weight: bold;
+        }
+        .summary-card.green .summary-number { color: #28a745; }
+        .summary-card.yellow .summary-number { color: #b8860b; }
+        .summary-card.red .summary-number { color: #dc3545; }
+        .summary-card.archived .summary-number { color: #6c757d; }
+        .summary-card.exempted .summary-number { color: #a4505a; }
+        .summary-label {
+            color: #586069;
+            font-size: 0.9rem;
+        }
+        .chart-section {
+            background: white;
+            border-radius: 8px;
+            padding: 1.5rem;
+            box-shadow: 0 1px 3px rgba(0,0,0,0.1);
+            margin-bottom: 2rem;
+        }
+        .chart-title {
+            font-size: 1.1rem;
+            margin-bottom: 1rem;
+            color: #24292e;
+        }
+        .pie-chart {
+            width: 200px;
+            height: 200px;
+            border-radius: 50%;
+            margin: 0 auto;
+            background: conic-gradient(
+                #28a745 0deg {{printf "%.1f" .GreenPct}}%,
+                #ffc107 {{printf "%.1f" .GreenPct}}% {{printf "%.1f" (add .GreenPct .YellowPct)}}%,
+                #dc3545 {{printf "%.1f" (add .GreenPct .YellowPct)}}% {{printf "%.1f" (add (add .GreenPct .YellowPct) .RedPct)}}%,
+                #e9a9b0 {{printf "%.1f" (add (add .GreenPct .YellowPct) .RedPct)}}% {{printf "%.1f" (add (add (add .GreenPct .YellowPct) .RedPct) .ExemptedPct)}}%,
+                #adb5bd {{printf "%.1f" (add (add (add .GreenPct .YellowPct) .RedPct) .ExemptedPct)}}% 100%
+            );
+        }
+        .legend {
+            display: flex;
+            justify-content: center;
+            gap: 2rem;
+            margin-top: 1rem;
+        }
+        .legend-item {
+            display: flex;
+            align-items: center;
+            gap: 0.5rem;
+        }
+        .legend-colour {
+            width: 16px;
+            height: 16px;
+            border-radius: 3px;
+        }
+        .legend-colour.green { background: #28a745; }
+        .legend-colour.yellow { background: #ffc107; }
+        .legend-colour.red { background: #dc3545; }
+        .legend-colour.archived { background: #adb5bd; }
+        .legend-colour.exempted { background: #e9a9b0; }
+        .histogram {
+            display: flex;
+            align-items: flex-end;
+            gap: 4px;
+            height: 200px;
+            padding-top: 1.5rem;
+        }
+        .histogram-column {
+            flex: 1;
+            display: flex;
+            flex-direction: column;
+            justify-content: flex-end;
+            align-items: center;
+            height: 100%;
+        }
+        .histogram-count {
+            font-size: 0.75rem;
+            color: #586069;
+        }
+        .histogram-bar {
+            width: 100%;
+            min-height: 1px;
+            background: #0366d6;
+            border-radius: 3px 3px 0 0;
+        }
+        .histogram-bar.older {
+            background: #6c757d;
+        }
+        .histogram-labels {
+            display: flex;
+            gap: 4px;
+            margin-top: 0.5rem;
+        }
+        .histogram-label {
+            flex: 1;
+            font-size: 0.65rem;
+            color: #586069;
+            text-align: center;
+            writing-mode: vertical-rl;
+            transform: rotate(180deg);
+        }
+        .sparkline {
+            display: flex;
+            align-items: center;
+            gap: 1rem;
+            color: #586069;
+            font-size: 0.9rem;
+        }
+        .sparkline polyline {
+            fill: none;
+            stroke: #dc3545;
+            stroke-width: 2;
+        }
+        .table-section {
+            background: white;
+            border-radius: 8px;
+            box-shadow: 0 1px 3px rgba(0,0,0,0.1);
+            overflow: hidden;
+        }
+        .table-header {
+            padding: 1rem 1.5rem;
+            border-bottom: 1px solid #e1e4e8;
+        }
+        table {
+            width: 100%;
+            border-collapse: collapse;
+        }
+        th {
+            text-align: left;
+            padding: 0.75rem 1.5rem;
+            background: #f6f8fa;
+            border-bottom: 1px solid #e1e4e8;
+            font-weight: 600;
+            color: #24292e;
+        }
+        td {
+            padding: 0.75rem 1.5rem;
+            border-bottom: 1px solid #e1e4e8;
+        }
+        tr:hover {
+            background: #f6f8fa;
+        }
+        .status-badge {
+            display: inline-block;
+            padding: 0.25rem 0.75rem;
+            border-radius: 12px;
+            font-size: 0.85rem;
+            font-weight: 500;
+        }
+        .status-badge.green {
+            background: #dcffe4;
+            color: #22863a;
+        }
+        .status-badge.yellow {
+            background: #fff3cd;
+            color: #856404;
+        }
+        .status-badge.red {
+            background: #ffeef0;
+            color: #cb2431;
+        }
+        .status-badge.archived {
+            background: #e9ecef;
+            color: #495057;
+        }
+        .status-badge.exempted {
+            background: #f8e1e4;
+            color: #a4505a;
+        }
+        a {
+            color: #0366d6;
+            text-decoration: none;
+        }
+        a:hover {
+            text-decoration: underline;
+        }
+        .footer {
+            text-align: center;
+            margin-top: 2rem;
+            color: #586069;
+            font-size: 0.85rem;
+        }
+        .table-header {
+            display: flex;
+            justify-content: space-between;
+            align-items: center;
+        }
+        .filter-buttons {
+            display: flex;
+            gap: 0.5rem;
+        }
+        .filter-btn {
+            padding: 0.4rem 0.8rem;
+            border: 1px solid #e1e4e8;
+            border-radius: 6px;
+            background: white;
+            cursor: pointer;
+            font-size: 0.85rem;
+            transition: all 0.15s ease;
+        }
+        .filter-btn:hover {
+            background: #f6f8fa;
+        }
+        .filter-btn.active {
+            border-color: #0366d6;
+            background: #f1f8ff;
+            color: #0366d6;
+        }
+        .filter-btn.green.active {
+            border-color: #28a745;
+            background: #dcffe4;
+            color: #22863a;
+        }
+        .filter-btn.yellow.active {
+            border-color: #b8860b;
+            background: #fff3cd;
+            color: #856404;
+        }
+        .filter-btn.red.active {
+            border-color: #dc3545;
+            background: #ffeef0;
+            color: #cb2431;
+        }
+        .filter-btn.archived.active {
+            border-color: #6c757d;
+            background: #e9ecef;
+            color: #495057;
+        }
+        .freshness-group {
+            border-bottom: 1px solid #e1e4e8;
+        }
+        .freshness-group:last-child {
+            border-bottom: none;
+        }
+        .freshness-group summary {
+            padding: 0.75rem 1.5rem;
+            cursor: pointer;
+            font-weight: 600;
+            background: #fafbfc;
+        }
+        .freshness-group.hidden, tr.hidden {
+            display: none;
+        }
+        .table-controls {
+            display: flex;
+            gap: 1rem;
+            align-items: center;
+        }
+        .search-box {
+            padding: 0.4rem 0.8rem;
+            border: 1px solid #e1e4e8;
+            border-radius: 6px;
+            font-size: 0.85rem;
+            width: 16rem;
+        }
+        .description {
+            color: #586069;
+            font-size: 0.9rem;
+        }
+        .no-license {
+            color: #cb2431;
+        }
+        .issues-disabled {
+            color: #cb2431;
+            font-size: 0.8rem;
+            border: 1px solid #cb2431;
+            border-radius: 4px;
+            padding: 0 0.3rem;
+        }
+        .exempt {
+            color: #586069;
+            font-size: 0.8rem;
+            border: 1px solid #959da5;
+            border-radius: 4px;
+            padding: 0 0.3rem;
+            cursor: help;
+        }
+        .age, .countdown {
+            color: #586069;
+            font-size: 0.9rem;
+        }
+        .protection-unprotected {
+            color: #cb2431;
+            font-weight: 500;
+        }
+        .protection-unknown {
+            color: #6a737d;
+        }
+        .owner-section {
+            margin-top: 2rem;
+        }
+        .owner-card {
+            border-bottom: 1px solid #e1e4e8;
+        }
+        .owner-card:last-child {
+            border-bottom: none;
+        }
+        .owner-header {
+            display: flex;
+            justify-content: space-between;
+            align-items: center;
+            padding: 0.75rem 1.5rem;
+            background: #f6f8fa;
+        }
+        .owner-counts {
+            display: flex;
+            gap: 0.5rem;
+        }
+        .empty-note {
+            padding: 0.75rem 1.5rem;
+            color: #586069;
+        }
+{{- if .ColourBlind}}
+        /* Colour-blind palette: blue, orange, and purple, with a distinct shape per status */
+        .summary-card.green { border-left-color: #0072b2; }
+        .summary-card.yellow { border-left-color: #e69f00; }
+        .summary-card.red { border-left-color: #cc79a7; }
+        .summary-card.green .summary-number { color: #0072b2; }
+        .summary-card.yellow .summary-number { color: #b07800; }
+        .summary-card.red .summary-number { color: #a6527f; }
+        .pie-chart {
+            background: conic-gradient(
+                #0072b2 0deg {{printf "%.1f" .GreenPct}}%,
+                #e69f00 {{printf "%.1f" .GreenPct}}% {{printf "%.1f" (add .GreenPct .YellowPct)}}%,
+                #cc79a7 {{printf "%.1f" (add .GreenPct .YellowPct)}}% {{printf "%.1f" (add (add .GreenPct .YellowPct) .RedPct)}}%,
+                #ecd0e1 {{printf "%.1f" (add (add .GreenPct .YellowPct) .RedPct)}}% {{printf "%.1f" (add (add (add .GreenPct .YellowPct) .RedPct) .ExemptedPct)}}%,
+                #adb5bd {{printf "%.1f" (add (add (add .GreenPct .YellowPct) .RedPct) .ExemptedPct)}}% 100%
+            );
+        }
+        .legend-colour.green { background: #0072b2; border-radius: 50%; }
+        .legend-colour.yellow { background: #e69f00; clip-path: polygon(50% 0, 100% 100%, 0 100%); border-radius: 0; }
+        .legend-colour.red { background: #cc79a7; border-radius: 0; }
+        .summary-card.exempted { border-left-color: #ecd0e1; }
+        .legend-colour.exempted { background: #ecd0e1; }
+        .sparkline polyline { stroke: #cc79a7; }
+        .status-badge.green, .filter-btn.green.active { background: #deebf7; color: #005a8c; border-color: #0072b2; }
+        .status-badge.yellow, .filter-btn.yellow.active { background: #fdf0d5; color: #8a5a00; border-color: #e69f00; }
+        .status-badge.red, .filter-btn.red.active { background: #f6e1ed; color: #8e3b6a; border-color: #cc79a7; }
+        .status-badge.green::before { content: "● "; }
+        .status-badge.yellow::before { content: "▲ "; }
+        .status-badge.red::before { content: "■ "; }
+{{- end}}
+    </style>
+</head>
+<body>
+    <div class="container">
+        <h1>Repository Freshness Report</h1>
+        <p class="subtitle">Organisation: <strong>{{.Organization}}</strong> | Generated: {{.GeneratedAt}}{{if .ExcludedForks}} | {{.ExcludedForks}} forks excluded{{end}}{{if .ExcludedMirrors}} | {{.ExcludedMirrors}} mirrors excluded{{end}}{{if .NeverPushed}} | {{.NeverPushed}} empty repositories{{end}}{{if .StaleIssuesDisabled}} | {{.StaleIssuesDisabled}} stale with issues disabled{{end}}</p>
+
+        <div class="summary-grid">
+            <div class="summary-card total">
+                <div class="summary-number">{{.Summary.Total}}</div>
+                <div class="summary-label">Total Repositories</div>
+            </div>
+            <div class="summary-card green">
+                <div class="summary-number">{{.Summary.Green}}</div>
+                <div class="summary-label">Active ({{.Labels.Green}})</div>
+            </div>
+            <div class="summary-card yellow">
+                <div class="summary-number">{{.Summary.Yellow}}</div>
+                <div class="summary-label">Aging ({{.Labels.Yellow}})</div>
+            </div>
+            <div class="summary-card red">
+                <div class="summary-number">{{.Summary.Red}}</div>
+                <div class="summary-label">Stale ({{.Labels.Red}})</div>
+            </div>
+            {{if gt .Summary.Exempted 0}}
+            <div class="summary-card exempted">
+                <div class="summary-number">{{.Summary.Exempted}}</div>
+                <div class="summary-label">Exempted</div>
+            </div>
+            {{end}}
+            {{if gt .Summary.Archived 0}}
+            <div class="summary-card archived">
+                <div class="summary-number">{{.Summary.Archived}}</div>
+                <div class="summary-label">Archived</div>
+            </div>
+            {{end}}
+        </div>
+
+        {{if gt .Summary.Total 0}}
+        <div class="chart-section">
+            <div class="chart-title">Distribution</div>
+            <div class="pie-chart"></div>
+            <div class="legend">
+                <div class="legend-item">
+                    <div class="legend-colour green"></div>
+                    <span>Active ({{printf "%.1f" .GreenPct}}%)</span>
+                </div>
+                <div class="legend-item">
+                    <div class="legend-colour yellow"></div>
+                    <span>Aging ({{printf "%.1f" .YellowPct}}%)</span>
+                </div>
+                <div class="legend-item">
+                    <div class="legend-colour red"></div>
+                    <span>Stale ({{printf "%.1f" .RedPct}}%)</span>
+                </div>
+                {{if gt .Summary.Exempted 0}}
+                <div class="legend-item">
+                    <div class="legend-colour exempted"></div>
+                    <span>Exempted ({{printf "%.1f" .ExemptedPct}}%)</span>
+                </div>
+                {{end}}
+                {{if gt .Summary.Archived 0}}
+                <div class="legend-item">
+                    <div class="legend-colour archived"></div>
+                    <span>Archived ({{printf "%.1f" .ArchivedPct}}%)</span>
+                </div>
+                {{end}}
+            </div>
+        </div>
+        {{end}}
+
+        {{if gt .Summary.Total 0}}
+        <div class="chart-section">
+            <div class="chart-title">Repositories by Month of Last Activity</div>
+            <div class="histogram">
+                {{range $i, $bar := .Histogram}}
+                <div class="histogram-column" title="{{$bar.Label}}: {{$bar.Count}}">
+                    {{if $bar.Count}}<div class="histogram-count">{{$bar.Count}}</div>{{end}}
+                    <div class="histogram-bar{{if eq $i 0}} older{{end}}" style="height: {{printf "%.1f" $bar.HeightPct}}%"></div>
+                </div>
+                {{end}}
+            </div>
+            <div class="histogram-labels">
+                {{range .Histogram}}
+                <div class="histogram-label">{{.Label}}</div>
+                {{end}}
+            </div>
+        </div>
+        {{end}}
+
+        {{with .Trend}}
+        <div class="chart-section">
+            <div class="chart-title">Stale Repositories Over Time</div>
+            <div class="sparkline">
+                <svg width="300" height="40" viewBox="-2 -2 304 44" role="img" aria-label="Stale repository count over the last {{.Scans}} scans">
+                    <polyline points="{{.Points}}"/>
+                </svg>
+                <span>Last {{.Scans}} scans, {{.From}} to {{.To}}; {{.Latest}} stale at the latest</span>
+            </div>
+        </div>
+        {{end}}
+
+        <div class="table-section">
+            <div class="table-header">
+                <div><strong>All Repositories</strong> (sorted by age, oldest first)</div>
+                <div class="table-controls">
+                    <input type="search" id="repo-search" class="search-box" placeholder="Search name or description" oninput="applyFilters()">
+                    <div class="filter-buttons">
+                        <button class="filter-btn active" data-filter="all" onclick="filterTable('all')">All</button>
+                        <button class="filter-btn red" data-filter="red" onclick="filterTable('red')">Red</button>
+                        <button class="filter-btn yellow" data-filter="yellow" onclick="filterTable('yellow')">Yellow</button>
+                        <button class="filter-btn green" data-filter="green" onclick="filterTable('green')">Green</button>
+                        {{if gt .Summary.Archived 0}}<button class="filter-btn archived" data-filter="archived" onclick="filterTable('archived')">Archived</button>{{end}}
+                    </div>
+                </div>
+            </div>
+            <div id="repo-groups">
+                {{range .Groups}}
+                <details class="freshness-group" data-status="{{.ColourClass}}"{{if .Open}} open{{end}}>
+                    <summary>
+                        <span class="status-badge {{.ColourClass}}">{{.ColourClass}}</span>
+                        {{.Label}} ({{len .Repositories}})
+                    </summary>
+                    {{if .Repositories}}
+                    <table>
+                        <thead>
+                            <tr>
+                                <th>#</th>
+                                <th>Repository</th>
+                                <th>Description</th>
+                                {{if $.Ownership}}<th>Owner</th>{{end}}
+                                <th>Last Updated</th>
+                                {{if $.LastCommit}}<th>Last Commit</th>{{end}}
+                                <th>License</th>
+                                {{if and $.Protection (eq .ColourClass "red")}}<th>Branch Protection</th>{{end}}
+                                <th>Status</th>
+                            </tr>
+                        </thead>
+                        <tbody>
+                            {{range $i, $repo := .Repositories}}
+                            <tr data-status="{{$repo.ColourClass}}" data-search="{{$repo.Search}}">
+                                <td>{{add $i 1}}</td>
+                                <td><a href="{{$repo.URL}}" target="_blank">{{$repo.FullName}}</a>{{if $repo.Abandoned}} <span class="issues-disabled" title="Issues are disabled, often a sign of intent to archive">issues disabled</span>{{end}}{{with $repo.Exemption}} <span class="exempt" title="{{.}}">exempt</span>{{end}}</td>
+                                <td class="description"{{if ne $repo.ShortDesc $repo.Description}} title="{{$repo.Description}}"{{end}}>{{$repo.ShortDesc}}</td>
+                                {{if $.Ownership}}<td>{{$repo.Team}}</td>{{end}}
+                                <td>{{$repo.Date}} <span class="age">({{$repo.Age}})</span></td>
+                                {{if $.LastCommit}}<td>{{$repo.LastCommit}}</td>{{end}}
+                                <td>{{if $repo.License}}{{$repo.License}}{{else}}<span class="no-license">none</span>{{end}}</td>
+                                {{if and $.Protection (eq $repo.ColourClass "red")}}<td>{{with $repo.Protection}}<span class="protection-{{.}}">{{.}}</span>{{else}}—{{end}}</td>{{end}}
+                                <td><span class="status-badge {{$repo.ColourClass}}"{{with $repo.Style}} style="{{.}}"{{end}}>{{$repo.Freshness}}</span>{{with $repo.Countdown}} <span class="countdown">{{.}}</span>{{end}}</td>
+                            </tr>
+                            {{end}}
+                        </tbody>
+                    </table>
+                    {{else}}
+                    <div class="empty-note">No repositories.</div>
+                    {{end}}
+                </details>
+                {{end}}
+            </div>
+        </div>
+
+        {{if .Owners}}
+        <div class="table-section owner-section">
+            <div class="table-header">
+                <div><strong>Stale Repositories by {{if .Ownership}}Team{{else}}Owner{{end}}</strong></div>
+            </div>
+            {{range .Owners}}
+            <div class="owner-card">
+                <div class="owner-header">
+                    <strong>{{.Owner}}</strong>
+                    <div class="owner-counts">
+                        <span class="status-badge green">{{.Summary.Green}} green</span>
+                        <span class="status-badge yellow">{{.Summary.Yellow}} yellow</span>
+                        <span class="status-badge red">{{.Summary.Red}} red</span>
+                        {{if gt .Summary.Exempted 0}}<span class="status-badge exempted">{{.Summary.Exempted}} exempted</span>{{end}}
+                        {{if gt .Summary.Archived 0}}<span class="status-badge archived">{{.Summary.Archived}} archived</span>{{end}}
+                    </div>
+                </div>
+                {{if .Stale}}
+                <table>
+                    <tbody>
+                        {{range .Stale}}
+                        <tr>
+                            <td><a href="{{.URL}}" target="_blank">{{.FullName}}</a></td>
+                            <td>{{.Date}} <span class="age">({{.Age}})</span></td>
+                        </tr>
+                        {{end}}
+                    </tbody>
+                </table>
+                {{else}}
+                <div class="empty-note">No stale repositories.</div>
+                {{end}}
+            </div>
+            {{end}}
+        </div>
+        {{end}}
+
+        <div class="footer">
+            Generated by <strong>patina</strong>
+        </div>
+    </div>
+
+    <script>
+        let currentStatus = 'all';
+
+        function filterTable(status) {
+            const buttons = document.querySelectorAll('.filter-btn');
+
+            buttons.forEach(btn => {
+                btn.classList.remove('active');
+                if (btn.dataset.filter === status) {
+                    btn.classList.add('active');
+                }
+            });
+
+            currentStatus = status;
+            applyFilters();
+        }
+
+        function applyFilters() {
+            const groups = document.querySelectorAll('#repo-groups .freshness-group');
+            const query = document.getElementById('repo-search').value.trim().toLowerCase();
+
+            groups.forEach(group => {
+                let matches = 0;
+                group.querySelectorAll('tbody tr').forEach(row => {
+                    const match = row.dataset.search.includes(query);
+                    row.classList.toggle('hidden', !match);
+                    if (match) {
+                        matches++;
+                    }
+                });
+
+                const statusMatch = currentStatus === 'all' || group.dataset.status === currentStatus;
+                if (statusMatch && (query === '' || matches > 0)) {
+                    group.classList.remove('hidden');
+                    if (currentStatus !== 'all' || query !== '') {
+                        group.open = true;
+                    }
+                } else {
+                    group.classList.add('hidden');
+                }
+            });
+        }
+    </script>
+</body>
+</html>`
@@ -0,0 +1,206 @@
+package patina
+
+import (
+	"html/template"
+	"strings"
+	"testing"
+)
+
+func TestRenderHTMLReport(t *testing.T) {
+	summary := FreshnessSummary{Green: 1, Yellow: 2, Red: 3, Total: 6}
+	data := ReportInput{
+		Organization: "my-org",
+		Summary:      summary,
+		Labels:       NewReportLabels(3, 6),
+		Owners:       []ReportOwner{{Owner: "alice", Summary: summary}},
+	}
+
+	var b strings.Builder
+	if err := RenderHTMLReport(&b, data); err != nil {
+		t.Fatalf("RenderHTMLReport() error = %v", err)
+	}
+
+	for _, want := range []string{
+		`<div class="summary-number">6</div>`,
+		`<span class="status-badge red">3 red</span>`,
+		`<div class="summary-label">Active (≤3 months)</div>`,
+		`<div class="summary-label">Aging (3-6 months)</div>`,
+	} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("report output missing %q", want)
+		}
+	}
+}
+
+func TestRenderHTMLReportArchived(t *testing.T) {
+	data := ReportInput{
+		Organization: "my-org",
+		Summary:      FreshnessSummary{Green: 2, Yellow: 1, Red: 1, Archived: 1, Total: 5},
+		Labels:       NewReportLabels(2, 6),
+		GreenPct:     40,
+		YellowPct:    20,
+		RedPct:       20,
+		ArchivedPct:  20,
+	}
+
+	var b strings.Builder
+	if err := RenderHTMLReport(&b, data); err != nil {
+		t.Fatalf("RenderHTMLReport() error = %v", err)
+	}
+
+	for _, want := range []string{
+		`<div class="summary-label">Archived</div>`,
+		`#dc3545 60.0% 80.0%`,
+		`#adb5bd 80.0% 100%`,
+		`<span>Archived (20.0%)</span>`,
+		`data-filter="archived"`,
+	} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("report output missing %q", want)
+		}
+	}
+}
+
+func TestRenderHTMLReportProtection(t *testing.T) {
+	data := ReportInput{
+		Organization: "my-org",
+		Protection:   true,
+		Summary:      FreshnessSummary{Green: 1, Red: 2, Total: 3},
+		Labels:       NewReportLabels(2, 6),
+		Groups: []ReportGroup{
+			{Label: "Stale", ColourClass: "red", Repositories: []ReportRepository{
+				{Name: "legacy", ColourClass: "red", Protection: "unprotected"},
+				{Name: "locked", ColourClass: "red", Protection: "unknown"},
+			}},
+			{Label: "Active", ColourClass: "green", Repositories: []ReportRepository{
+				{Name: "api", ColourClass: "green", Protection: "protected"},
+			}},
+		},
+	}
+
+	var b strings.Builder
+	if err := RenderHTMLReport(&b, data); err != nil {
+		t.Fatalf("RenderHTMLReport() error = %v", err)
+	}
+	out := b.String()
+
+	for _, want := range []string{
+		`<span class="protection-unprotected">unprotected</span>`,
+		`<span class="protection-unknown">unknown</span>`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("report output missing %q", want)
+		}
+	}
+	// Protection is only shown for stale repositories
+	if n := strings.Count(out, "<th>Branch Protection</th>"); n != 1 {
+		t.Errorf("Branch Protection headers = %d, want 1", n)
+	}
+	if strings.Contains(out, `protection-protected`) {
+		t.Error("report shows protection for an active repository")
+	}
+}
+
+func TestRenderHTMLReportOwnership(t *testing.T) {
+	data := ReportInput{
+		Organization: "my-org",
+		Ownership:    true,
+		Summary:      FreshnessSummary{Red: 1, Total: 1},
+		Labels:       NewReportLabels(2, 6),
+		Groups: []ReportGroup{
+			{Label: "Stale", ColourClass: "red", Repositories: []ReportRepository{
+				{Name: "legacy", ColourClass: "red", Team: "platform"},
+			}},
+		},
+		Owners: []ReportOwner{{Owner: "platform", Summary: FreshnessSummary{Red: 1, Total: 1}}},
+	}
+
+	var b strings.Builder
+	if err := RenderHTMLReport(&b, data); err != nil {
+		t.Fatalf("RenderHTMLReport() error = %v", err)
+	}
+	out := b.String()
+
+	for _, want := range []string{"<th>Owner</th>", "<td>platform</td>", "Stale Repositories by Team"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("report output missing %q", want)
+		}
+	}
+}
+
+func TestRenderHTMLReportColourBlind(t *testing.T) {
+	render := func(colourBlind bool) string {
+		t.Helper()
+		data := ReportInput{Organization: "my-org", Labels: NewReportLabels(2, 6), GreenPct: 50, RedPct: 50, ColourBlind: colourBlind}
+		var b strings.Builder
+		if err := RenderHTMLReport(&b, data); err != nil {
+			t.Fatalf("RenderHTMLReport() error = %v", err)
+		}
+		return b.String()
+	}
+
+	if out := render(false); strings.Contains(out, "#0072b2") {
+		t.Error("default report uses the colour-blind palette")
+	}
+	out := render(true)
+	for _, want := range []string{"#0072b2 0deg 50.0%", `.status-badge.red::before { content: "■ "; }`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("colour-blind report missing %q", want)
+		}
+	}
+}
+
+func TestRenderHTMLReportGradient(t *testing.T) {
+	repo := ReportRepository{Name: "api", FullName: "my-org/api", Freshness: "red", ColourClass: "red", Style: template.CSS("background: hsl(60, 70%, 88%);")}
+	data := ReportInput{
+		Organization: "my-org",
+		Groups:       []ReportGroup{{Label: "Stale", ColourClass: "red", Repositories: []ReportRepository{repo}}},
+	}
+
+	var b strings.Builder
+	if err := RenderHTMLReport(&b, data); err != nil {
+		t.Fatalf("RenderHTMLReport() error = %v", err)
+	}
+
+	want := `<span class="status-badge red" style="` + string(repo.Style) + `">red</span>`
+	if !strings.Contains(b.String(), want) {
+		t.Errorf("report output missing %q", want)
+	}
+}
+
+func TestRenderHTMLReportExemptions(t *testing.T) {
+	data := ReportInput{
+		Organization: "my-org",
+		Summary:      FreshnessSummary{Green: 1, Red: 1, Exempted: 1, Total: 3},
+		Labels:       NewReportLabels(2, 6),
+		GreenPct:     33.3,
+		RedPct:       33.3,
+		ExemptedPct:  33.3,
+		Groups: []ReportGroup{
+			{Label: "Stale", ColourClass: "red", Repositories: []ReportRepository{
+				{Name: "legacy", ColourClass: "red"},
+				{Name: "spec", ColourClass: "red", Exemption: "Frozen reference"},
+			}},
+		},
+	}
+
+	var b strings.Builder
+	if err := RenderHTMLReport(&b, data); err != nil {
+		t.Fatalf("RenderHTMLReport() error = %v", err)
+	}
+	out := b.String()
+
+	for _, want := range []string{
+		`<span class="exempt" title="Frozen reference">exempt</span>`,
+		`<div class="summary-label">Exempted</div>`,
+		`<span>Exempted (33.3%)</span>`,
+		`#e9a9b0 66.6% 99.9%`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("report output missing %q", want)
+		}
+	}
+	if n := strings.Count(out, `class="exempt"`); n != 1 {
+		t.Errorf("exempt markers = %d, want 1", n)
+	}
+}
@@ -0,0 +1,225 @@
+package patina
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+)
+
+var (
+	ErrInvalidRepository  = errors.New("invalid repository name")
+	ErrRepositoryNotFound = errors.New("repository not found")
+)
+
+// repositoryPattern matches an owner/name pair. Repository names may also
+// contain dots, but not consist only of them.
+var repositoryPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]{0,99}/[A-Za-z0-9._-]{1,100}$`)
+
+// repoListKeyPrefix starts the synthetic cache key of a repository list.
+const repoListKeyPrefix = "patina-repos-"
+
+// repositoryFetcher is implemented by clients that can fetch a single repository.
+type repositoryFetcher interface {
+	FetchRepository(fullName string) (Repository, error)
+}
+
+// ValidateRepository checks that a name has the owner/name form GitHub uses.
+func ValidateRepository(fullName string) error {
+	_, name, _ := strings.Cut(fullName, "/")
+	if !repositoryPattern.MatchString(fullName) || strings.Trim(name, ".") == "" {
+		return fmt.Errorf("%w: %q (expected owner/name)", ErrInvalidRepository, fullName)
+	}
+	return nil
+}
+
+// repoListKey returns the cache key for a set of repositories. It is derived
+// from the lower-cased, sorted names, so the same repositories share a cache
+// regardless of order or case, and it is a valid organization name.
+func repoListKey(fullNames []string) string {
+	names := make([]string, len(fullNames))
+	for i, name := range fullNames {
+		names[i] = strings.ToLower(name)
+	}
+	slices.Sort(names)
+	names = slices.Compact(names)
+
+	sum := sha256.Sum256([]byte(strings.Join(names, "\n")))
+	return repoListKeyPrefix + hex.EncodeToString(sum[:8])
+}
+
+// isRepoListKey reports whether a cache key belongs to a repository list
+// rather than an organization.
+func isRepoListKey(key string) bool {
+	return strings.HasPrefix(key, repoListKeyPrefix)
+}
+
+// repositoryPath returns the API path of a single repository.
+func repositoryPath(fullName string) string {
+	return "/repos/" + fullName
+}
+
+// parseRepository converts a single repository response.
+func parseRepository(body []byte) (Repository, error) {
+	var repo ghRepo
+	if err := json.Unmarshal(body, &repo); err != nil {
+		return Repository{}, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return repo.toRepository(), nil
+}
+
+// FetchRepository retrieves a single repository using the GitHub API with a token.
+func (c *tokenClient) FetchRepository(fullName string) (Repository, error) {
+	url := c.baseURL + repositoryPath(fullName)
+
+	slog.Debug("requesting repository", "url", url)
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return Repository{}, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+
+	resp, err := c.executor.Do(req)
+	if err != nil {
+		return Repository{}, fmt.Errorf("failed to fetch repository: %w", err)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return Repository{}, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return parseRepository(body)
+	case http.StatusNotFound:
+		return Repository{}, fmt.Errorf("%w: %s", ErrRepositoryNotFound, fullName)
+	}
+	return Repository{}, apiError(fullName, resp.StatusCode, body)
+}
+
+// FetchRepository retrieves a single repository using an installation token.
+func (c *appClient) FetchRepository(fullName string) (Repository, error) {
+	token, err := c.installationToken()
+	if err != nil {
+		return Repository{}, err
+	}
+
+	client := &tokenClient{token: token, baseURL: c.baseURL, executor: c.executor}
+	return client.FetchRepository(fullName)
+}
+
+// FetchRepository retrieves a single repository using the gh CLI.
+func (c *ghCLIClient) FetchRepository(fullName string) (Repository, error) {
+	if err := c.ensureChecked(); err != nil {
+		return Repository{}, err
+	}
+
+	args := []string{"api", "--method", "GET", repositoryPath(fullName)}
+
+	slog.Debug("running gh", "args", args)
+
+	stdout, stderr, err := c.run(args...)
+	if err != nil {
+		switch {
+		case strings.Contains(stderr.String(), "HTTP 404"):
+			return Repository{}, fmt.Errorf("%w: %s", ErrRepositoryNotFound, fullName)
+		case isGHAccessDenied(stderr.String()):
+			return Repository{}, noAccessError(fullName, strings.TrimSpace(stderr.String()))
+		}
+		return Repository{}, wrapGHError(err, stderr.String())
+	}
+
+	return parseRepository(stdout.Bytes())
+}
+
+// fetchRepositories fetches each named repository concurrently, returning
+// them in the order named. The first failure fails the whole fetch.
+func fetchRepositories(fetcher repositoryFetcher, fullNames []string) ([]Repository, error) {
+	sem := make(chan struct{}, defaultConcurrency)
+	var wg sync.WaitGroup
+
+	repos := make([]Repository, len(fullNames))
+	errs := make([]error, len(fullNames))
+	for i, name := range fullNames {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			repos[i], errs[i] = fetcher.FetchRepository(name)
+		}()
+	}
+
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return repos, nil
+}
+
+// ScanRepositories retrieves the named repositories, which may belong to
+// different owners, one API call each. The set is cached under a key derived
+// from the names, so repeating the same list uses the cache.
+func (s *Scanner) ScanRepositories(fullNames []string, opts ScanOptions) (*ScanResult, error) {
+	if len(fullNames) == 0 {
+		return nil, fmt.Errorf("%w: no repositories named", ErrInvalidRepository)
+	}
+	for _, name := range fullNames {
+		if err := ValidateRepository(name); err != nil {
+			return nil, err
+		}
+	}
+
+	key := repoListKey(fullNames)
+	now := s.clock()
+
+	cached, err := s.cache.Load(key)
+	if err == nil && !opts.Refresh {
+		slog.Debug("cache hit", "key", key, "fetched_at", cached.FetchedAt, "repos", len(cached.Repositories))
+		return newScanResult(key, cached.Repositories, cached.FetchedAt, true, opts), nil
+	}
+	slog.Debug("cache miss", "key", key, "reason", err)
+
+	fetcher, ok := s.client.(repositoryFetcher)
+	if !ok {
+		return nil, errors.New("GitHub client cannot fetch individual repositories")
+	}
+
+	// Skip names repeated with different case, which GitHub treats as one repository
+	var names []string
+	seen := make(map[string]bool)
+	for _, name := range fullNames {
+		if lower := strings.ToLower(name); !seen[lower] {
+			seen[lower] = true
+			names = append(names, name)
+		}
+	}
+
+	repos, err := fetchRepositories(fetcher, names)
+	if err != nil {
+		return nil, err
+	}
+
+	result := newScanResult(key, repos, now, false, opts)
+	if err := s.cache.Save(OrganizationCache{Organization: key, Repositories: repos, FetchedAt: now}); err != nil {
+		result.CacheWriteErr = fmt.Errorf("failed to save cache: %w", err)
+	}
+	return result, nil
+}
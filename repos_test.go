@@ -0,0 +1,146 @@
+package patina
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// mockRepositoryClient is a mockGitHubClient that can also fetch single repositories.
+type mockRepositoryClient struct {
+	mockGitHubClient
+	byName map[string]Repository
+
+	repoMu      sync.Mutex
+	repoLookups int
+}
+
+func (m *mockRepositoryClient) FetchRepository(fullName string) (Repository, error) {
+	m.repoMu.Lock()
+	defer m.repoMu.Unlock()
+	m.repoLookups++
+
+	repo, ok := m.byName[fullName]
+	if !ok {
+		return Repository{}, fmt.Errorf("%w: %s", ErrRepositoryNotFound, fullName)
+	}
+	return repo, nil
+}
+
+func TestValidateRepository(t *testing.T) {
+	valid := []string{"org/repo", "some-user/repo.js", "Org_1/.github"}
+	for _, name := range valid {
+		if err := ValidateRepository(name); err != nil {
+			t.Errorf("ValidateRepository(%q) error = %v", name, err)
+		}
+	}
+
+	invalid := []string{"", "repo", "org/", "/repo", "org/repo/extra", "org/..", "../repo", "org/re po"}
+	for _, name := range invalid {
+		if err := ValidateRepository(name); !errors.Is(err, ErrInvalidRepository) {
+			t.Errorf("ValidateRepository(%q) error = %v, want ErrInvalidRepository", name, err)
+		}
+	}
+}
+
+func TestRepoListKey(t *testing.T) {
+	key := repoListKey([]string{"org-a/one", "org-b/two"})
+
+	if got := repoListKey([]string{"Org-B/Two", "org-a/one"}); got != key {
+		t.Errorf("repoListKey() differs by order or case: %s != %s", got, key)
+	}
+	if got := repoListKey([]string{"org-a/one"}); got == key {
+		t.Error("repoListKey() is the same for different repositories")
+	}
+	if err := ValidateOrganization(key); err != nil {
+		t.Errorf("repoListKey() = %q is not a valid cache key: %v", key, err)
+	}
+	if !isRepoListKey(key) {
+		t.Errorf("isRepoListKey(%q) = false", key)
+	}
+}
+
+func TestTokenClientFetchRepository(t *testing.T) {
+	pushed := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/org/repo":
+			fmt.Fprintf(w, `{"name":"repo","full_name":"org/repo","pushed_at":%q,"owner":{"login":"org"}}`, pushed.Format(time.RFC3339))
+		case "/repos/org/missing":
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message":"Not Found"}`)
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := &tokenClient{
+		token:    "ghp_token",
+		baseURL:  server.URL,
+		executor: newRequestExecutor(server.Client(), 1),
+	}
+
+	repo, err := client.FetchRepository("org/repo")
+	if err != nil {
+		t.Fatalf("FetchRepository() error = %v", err)
+	}
+	if repo.FullName != "org/repo" || !repo.LastUpdated.Equal(pushed) || repo.Owner != "org" {
+		t.Errorf("FetchRepository() = %+v", repo)
+	}
+
+	if _, err := client.FetchRepository("org/missing"); !errors.Is(err, ErrRepositoryNotFound) {
+		t.Errorf("FetchRepository(missing) error = %v, want ErrRepositoryNotFound", err)
+	}
+}
+
+func TestScannerScanRepositories(t *testing.T) {
+	now := time.Now()
+	client := &mockRepositoryClient{byName: map[string]Repository{
+		"org-a/one": {Name: "one", FullName: "org-a/one", LastUpdated: now.AddDate(0, -1, 0)},
+		"org-b/two": {Name: "two", FullName: "org-b/two", LastUpdated: now.AddDate(-1, 0, 0)},
+	}}
+	cache := NewCacheWithDir(t.TempDir())
+	scanner := NewScannerWithDeps(client, cache)
+
+	names := []string{"org-a/one", "org-b/two", "ORG-A/ONE"}
+	result, err := scanner.ScanRepositories(names, ScanOptions{})
+	if err != nil {
+		t.Fatalf("ScanRepositories() error = %v", err)
+	}
+	if result.FromCache || len(result.Repositories) != 2 {
+		t.Errorf("first scan = %d repos, from cache %t; want 2 fetched", len(result.Repositories), result.FromCache)
+	}
+	if client.repoLookups != 2 {
+		t.Errorf("repository lookups = %d, want 2", client.repoLookups)
+	}
+
+	result, err = scanner.ScanRepositories([]string{"org-b/two", "org-a/one"}, ScanOptions{})
+	if err != nil {
+		t.Fatalf("ScanRepositories() error = %v", err)
+	}
+	if !result.FromCache || len(result.Repositories) != 2 {
+		t.Errorf("second scan = %d repos, from cache %t; want 2 cached", len(result.Repositories), result.FromCache)
+	}
+	if client.repoLookups != 2 {
+		t.Errorf("repository lookups after cached scan = %d, want 2", client.repoLookups)
+	}
+
+	// Repository lists are not organizations, so the leaderboard skips them
+	caches, _, err := cache.LoadAll()
+	if err != nil || len(caches) != 0 {
+		t.Errorf("LoadAll() = %d caches, %v; want none", len(caches), err)
+	}
+
+	if _, err := scanner.ScanRepositories([]string{"org-a/missing"}, ScanOptions{}); !errors.Is(err, ErrRepositoryNotFound) {
+		t.Errorf("ScanRepositories(missing) error = %v, want ErrRepositoryNotFound", err)
+	}
+	if _, err := scanner.ScanRepositories([]string{"not-a-repo"}, ScanOptions{}); !errors.Is(err, ErrInvalidRepository) {
+		t.Errorf("ScanRepositories(invalid) error = %v, want ErrInvalidRepository", err)
+	}
+}
@@ -0,0 +1,91 @@
+package patina
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ScoreWeights configures how much each factor contributes to a staleness score.
+//
+// A repository's score is:
+//
+//	Age*months_since_update + Issues*ln(1+open_issues) + Stars*ln(1+stars)
+//
+// Higher scores indicate a higher triage priority: old, popular repositories
+// with many open issues rank first, while recent activity lowers the score.
+// Issue and star counts are log-scaled so a handful of very popular
+// repositories do not drown out age.
+type ScoreWeights struct {
+	Age    float64
+	Issues float64
+	Stars  float64
+}
+
+// DefaultScoreWeights are the weights used when none are configured.
+var DefaultScoreWeights = ScoreWeights{
+	Age:    1.0,
+	Issues: 1.0,
+	Stars:  2.0,
+}
+
+// Score computes the staleness score for a repository using these weights.
+func (w ScoreWeights) Score(repo Repository, now time.Time) float64 {
+	months := now.Sub(repo.LastUpdated).Hours() / 24 / 30
+	if months < 0 {
+		months = 0
+	}
+
+	return w.Age*months +
+		w.Issues*math.Log1p(float64(repo.OpenIssues)) +
+		w.Stars*math.Log1p(float64(repo.Stars))
+}
+
+// StalenessScore computes the staleness score for a repository using DefaultScoreWeights.
+func StalenessScore(repo Repository, now time.Time) float64 {
+	return DefaultScoreWeights.Score(repo, now)
+}
+
+// SortByScore sorts repositories by staleness score, highest first.
+func SortByScore(repos []Repository, weights ScoreWeights, now time.Time) {
+	sort.SliceStable(repos, func(i, j int) bool {
+		return weights.Score(repos[i], now) > weights.Score(repos[j], now)
+	})
+}
+
+// ParseScoreWeights parses a comma-separated list of weight overrides such as
+// "age=1,issues=0.5,stars=3". Weights not mentioned keep their default values.
+func ParseScoreWeights(s string) (ScoreWeights, error) {
+	weights := DefaultScoreWeights
+	if strings.TrimSpace(s) == "" {
+		return weights, nil
+	}
+
+	for _, part := range strings.Split(s, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return weights, fmt.Errorf("invalid score weight %q (must be name=value)", part)
+		}
+
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil || v < 0 {
+			return weights, fmt.Errorf("invalid score weight value %q for %s (must be a non-negative number)", value, key)
+		}
+
+		switch key {
+		case "age":
+			weights.Age = v
+		case "issues":
+			weights.Issues = v
+		case "stars":
+			weights.Stars = v
+		default:
+			return weights, fmt.Errorf("unknown score weight %q (must be age, issues, or stars)", key)
+		}
+	}
+
+	return weights, nil
+}
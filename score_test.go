@@ -0,0 +1,96 @@
+package patina
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestStalenessScore(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+
+	repo := Repository{
+		Name:        "repo1",
+		LastUpdated: now.AddDate(0, 0, -60),
+		OpenIssues:  0,
+		Stars:       0,
+	}
+
+	if got := StalenessScore(repo, now); math.Abs(got-2.0) > 0.001 {
+		t.Errorf("StalenessScore() = %v, want 2.0", got)
+	}
+
+	// Stars raise the score
+	popular := repo
+	popular.Stars = 100
+	if StalenessScore(popular, now) <= StalenessScore(repo, now) {
+		t.Error("StalenessScore() did not increase with stars")
+	}
+
+	// Open issues raise the score
+	issues := repo
+	issues.OpenIssues = 10
+	if StalenessScore(issues, now) <= StalenessScore(repo, now) {
+		t.Error("StalenessScore() did not increase with open issues")
+	}
+
+	// Recent activity lowers the score
+	recent := repo
+	recent.LastUpdated = now.AddDate(0, 0, -1)
+	if StalenessScore(recent, now) >= StalenessScore(repo, now) {
+		t.Error("StalenessScore() did not decrease with recent activity")
+	}
+}
+
+func TestSortByScore(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+
+	repos := []Repository{
+		{Name: "recent", LastUpdated: now.AddDate(0, 0, -1)},
+		{Name: "old-popular", LastUpdated: now.AddDate(-1, 0, 0), Stars: 500},
+		{Name: "old", LastUpdated: now.AddDate(-1, 0, 0)},
+	}
+
+	SortByScore(repos, DefaultScoreWeights, now)
+
+	want := []string{"old-popular", "old", "recent"}
+	for i, name := range want {
+		if repos[i].Name != name {
+			t.Errorf("repos[%d].Name = %s, want %s", i, repos[i].Name, name)
+		}
+	}
+
+	// With age ignored, only popularity matters
+	SortByScore(repos, ScoreWeights{Stars: 1}, now)
+	if repos[0].Name != "old-popular" {
+		t.Errorf("repos[0].Name = %s, want old-popular", repos[0].Name)
+	}
+}
+
+func TestParseScoreWeights(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    ScoreWeights
+		wantErr bool
+	}{
+		{"", DefaultScoreWeights, false},
+		{"age=2", ScoreWeights{Age: 2, Issues: 1, Stars: 2}, false},
+		{"age=0.5, issues=0, stars=3", ScoreWeights{Age: 0.5, Issues: 0, Stars: 3}, false},
+		{"age", ScoreWeights{}, true},
+		{"age=abc", ScoreWeights{}, true},
+		{"age=-1", ScoreWeights{}, true},
+		{"forks=1", ScoreWeights{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseScoreWeights(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseScoreWeights(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("ParseScoreWeights(%q) = %+v, want %+v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
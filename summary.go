@@ -0,0 +1,116 @@
+package patina
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const summaryFileSuffix = ".summary.json"
+
+// CachedSummary is the small summary file written beside each organization's
+// cache, so dashboards that only need counts can skip the full repository data.
+type CachedSummary struct {
+	Organization string           `json:"organization"`
+	FetchedAt    time.Time        `json:"fetched_at"`
+	Summary      FreshnessSummary `json:"summary"`
+
+	// The settings the counts were calculated with
+	ActivityMetric ActivityMetric `json:"activity_metric"`
+	CalendarMonths bool           `json:"calendar_months,omitempty"`
+
+	// ValidUntil is when a repository next changes freshness, and with it the
+	// counts; zero if none will
+	ValidUntil time.Time `json:"valid_until,omitzero"`
+}
+
+// newCachedSummary summarizes cached data as scan shows it by default: forks,
+// mirrors, archived, and empty repositories are left out, and freshness is as
+// of the fetch.
+func newCachedSummary(data OrganizationCache) CachedSummary {
+	var o FreshnessOptions
+	repos := ExcludeNeverPushed(ExcludeArchived(ExcludeMirrors(ExcludeForks(data.Repositories))))
+	return CachedSummary{
+		Organization:   data.Organization,
+		FetchedAt:      data.FetchedAt,
+		Summary:        o.Summary(repos, data.FetchedAt),
+		ActivityMetric: ActivityPushed,
+		ValidUntil:     o.nextFreshnessChange(repos, data.FetchedAt),
+	}
+}
+
+// nextFreshnessChange returns the time after which the first of repos changes
+// freshness, looking on from from, or the zero time if none ever will.
+func (o FreshnessOptions) nextFreshnessChange(repos []Repository, from time.Time) time.Time {
+	var next time.Time
+	for _, repo := range repos {
+		for _, months := range []int{yellowMonths, redMonths} {
+			// Calculate moves a repository on once the time is after the threshold
+			threshold := o.monthsAfter(repo.LastUpdated, months)
+			if from.After(threshold) {
+				continue
+			}
+			if next.IsZero() || threshold.Before(next) {
+				next = threshold
+			}
+		}
+	}
+	return next
+}
+
+// Counts returns the summary's counts as of now if they were calculated with
+// the same settings and no repository has changed freshness since.
+func (s CachedSummary) Counts(o FreshnessOptions, metric ActivityMetric, now time.Time) (FreshnessSummary, bool) {
+	if metric == "" {
+		metric = ActivityPushed
+	}
+	if s.ActivityMetric != metric || s.CalendarMonths != o.CalendarMonths {
+		return FreshnessSummary{}, false
+	}
+	if !s.ValidUntil.IsZero() && now.After(s.ValidUntil) {
+		return FreshnessSummary{}, false
+	}
+	return s.Summary, true
+}
+
+// summaryFilePath returns the path to the summary file for an organization.
+func (c *Cache) summaryFilePath(org string) string {
+	return filepath.Join(c.baseDir, cacheKey(org)+summaryFileSuffix)
+}
+
+// writeSummary stores the summary of an organization's cached data.
+func (c *Cache) writeSummary(data OrganizationCache) error {
+	jsonData, err := json.Marshal(newCachedSummary(data))
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(c.summaryFilePath(data.Organization), jsonData, 0644)
+}
+
+// LoadSummary retrieves an organization's summary without reading its full
+// cache. Like Load, it returns ErrCacheNotFound if there is no summary, which
+// caches written by earlier versions lack, or ErrCacheExpired if it is stale.
+func (c *Cache) LoadSummary(org string) (CachedSummary, error) {
+	var summary CachedSummary
+	if err := ValidateOrganization(org); err != nil {
+		return summary, err
+	}
+
+	jsonData, err := os.ReadFile(c.summaryFilePath(org))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return summary, ErrCacheNotFound
+		}
+		return summary, err
+	}
+
+	if err := json.Unmarshal(jsonData, &summary); err != nil {
+		return summary, err
+	}
+
+	if c.clock().Sub(summary.FetchedAt) > cacheValidity {
+		return summary, ErrCacheExpired
+	}
+	return summary, nil
+}
@@ -0,0 +1,98 @@
+package patina
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCacheSaveWritesSummary(t *testing.T) {
+	cache := NewCacheWithDir(t.TempDir())
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	cache.SetClock(func() time.Time { return now })
+
+	if _, err := cache.LoadSummary("test-org"); !errors.Is(err, ErrCacheNotFound) {
+		t.Errorf("LoadSummary() error = %v before Save(), want ErrCacheNotFound", err)
+	}
+
+	err := cache.Save(OrganizationCache{
+		Organization: "test-org",
+		Repositories: []Repository{
+			{Name: "api", LastUpdated: now.AddDate(0, 0, -1)},
+			{Name: "legacy", LastUpdated: now.AddDate(-1, 0, 0)},
+			{Name: "fork", LastUpdated: now, Fork: true},
+			{Name: "empty", NeverPushed: true},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	summary, err := cache.LoadSummary("test-org")
+	if err != nil {
+		t.Fatalf("LoadSummary() error = %v", err)
+	}
+	want := FreshnessSummary{Green: 1, Red: 1, Total: 2}
+	if summary.Summary != want || !summary.FetchedAt.Equal(now) {
+		t.Errorf("LoadSummary() = %+v, want %+v fetched at %v", summary, want, now)
+	}
+	// api, pushed to a day ago, is the next to change freshness
+	if want := now.AddDate(0, 0, -1).Add(time.Duration(yellowMonths*daysPerMonth) * 24 * time.Hour); !summary.ValidUntil.Equal(want) {
+		t.Errorf("ValidUntil = %v, want %v", summary.ValidUntil, want)
+	}
+
+	// The summary file is not mistaken for an organization's cache
+	caches, _, err := cache.LoadAll()
+	if err != nil || len(caches) != 1 {
+		t.Errorf("LoadAll() = %d caches, %v, want 1", len(caches), err)
+	}
+
+	cache.SetClock(func() time.Time { return now.Add(cacheValidity + time.Hour) })
+	if _, err := cache.LoadSummary("test-org"); !errors.Is(err, ErrCacheExpired) {
+		t.Errorf("LoadSummary() error = %v after expiry, want ErrCacheExpired", err)
+	}
+
+	if err := cache.Clear("test-org"); err != nil {
+		t.Fatalf("Clear() error = %v", err)
+	}
+	if _, err := cache.LoadSummary("test-org"); !errors.Is(err, ErrCacheNotFound) {
+		t.Errorf("LoadSummary() error = %v after Clear(), want ErrCacheNotFound", err)
+	}
+}
+
+func TestCachedSummaryCounts(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	cached := CachedSummary{
+		Summary:        FreshnessSummary{Green: 1, Total: 1},
+		ActivityMetric: ActivityPushed,
+		ValidUntil:     now.AddDate(0, 0, 10),
+	}
+
+	tests := []struct {
+		name    string
+		options FreshnessOptions
+		metric  ActivityMetric
+		now     time.Time
+		want    bool
+	}{
+		{"current", FreshnessOptions{}, "", now, true},
+		{"at the change", FreshnessOptions{}, ActivityPushed, now.AddDate(0, 0, 10), true},
+		{"after the change", FreshnessOptions{}, ActivityPushed, now.AddDate(0, 0, 11), false},
+		{"other metric", FreshnessOptions{}, ActivityUpdated, now, false},
+		{"calendar months", FreshnessOptions{CalendarMonths: true}, ActivityPushed, now, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := cached.Counts(tt.options, tt.metric, tt.now)
+			if ok != tt.want || (ok && got != cached.Summary) {
+				t.Errorf("Counts() = %+v, %t; want %t", got, ok, tt.want)
+			}
+		})
+	}
+
+	// Summaries written before the settings were recorded are never used
+	if _, ok := (CachedSummary{Summary: cached.Summary}).Counts(FreshnessOptions{}, ActivityPushed, now); ok {
+		t.Error("Counts() = ok for a summary without settings")
+	}
+}
@@ -0,0 +1,151 @@
+package patina
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// tokenSwitchThreshold is the remaining quota at which a token pool moves
+// requests to another token, leaving headroom for requests already in flight.
+const tokenSwitchThreshold = 100
+
+// ReadTokens reads GitHub tokens from a file, one per line. Blank lines and
+// lines starting with # are ignored. A file that other users can read is
+// logged as a warning, as anyone who can read it can act as each token's owner.
+func ReadTokens(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tokens file: %w", err)
+	}
+	if info.Mode().Perm()&0o077 != 0 {
+		slog.Warn("tokens file is readable by other users; restrict it with chmod 600", "path", path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tokens file: %w", err)
+	}
+	tokens := parseNameList(string(data))
+	if len(tokens) == 0 {
+		return nil, errors.New("tokens file has no tokens")
+	}
+	return tokens, nil
+}
+
+// splitTokens splits a comma-separated list of tokens, dropping empty entries.
+func splitTokens(list string) []string {
+	var tokens []string
+	for _, token := range strings.Split(list, ",") {
+		if token = strings.TrimSpace(token); token != "" {
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens
+}
+
+// hostTokens returns the tokens set for a host. The variable may list several,
+// separated by commas, to share the load across their rate limits.
+func hostTokens(host string) []string {
+	return splitTokens(hostToken(host))
+}
+
+// tokenPool spreads requests across several tokens, each with its own rate
+// limit. Requests stay on one token until its remaining quota nears
+// tokenSwitchThreshold, then move to the token with the most quota left.
+type tokenPool struct {
+	mu      sync.Mutex
+	tokens  []pooledToken
+	current int
+}
+
+// pooledToken is a token and the rate limit GitHub last reported for it.
+type pooledToken struct {
+	value     string
+	remaining int // -1 until a response reports it
+	resetAt   time.Time
+}
+
+// newTokenPool creates a pool of tokens whose quotas are not yet known.
+func newTokenPool(tokens []string) *tokenPool {
+	pool := &tokenPool{}
+	for _, token := range tokens {
+		pool.tokens = append(pool.tokens, pooledToken{value: token, remaining: -1})
+	}
+	return pool
+}
+
+// available returns the quota a token is known to have left at now, treating
+// an unknown quota, or one whose reset has passed, as unlimited.
+func (t pooledToken) available(now time.Time) int {
+	if t.remaining < 0 || !now.Before(t.resetAt) {
+		return math.MaxInt
+	}
+	return t.remaining
+}
+
+// pick chooses the token for the next request. If every token is exhausted,
+// it returns the one that resets first and how long to wait for it.
+func (p *tokenPool) pick(now time.Time) (string, time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.tokens[p.current].available(now) > tokenSwitchThreshold {
+		return p.tokens[p.current].value, 0
+	}
+
+	best := p.current
+	for i, token := range p.tokens {
+		if token.available(now) > p.tokens[best].available(now) {
+			best = i
+		}
+	}
+	if p.tokens[best].available(now) == 0 {
+		for i, token := range p.tokens {
+			if token.resetAt.Before(p.tokens[best].resetAt) {
+				best = i
+			}
+		}
+		p.current = best
+		return p.tokens[best].value, p.tokens[best].resetAt.Sub(now)
+	}
+
+	if best != p.current {
+		slog.Debug("switching token", "from", p.current, "to", best, "remaining", p.tokens[best].remaining)
+		p.current = best
+	}
+	return p.tokens[best].value, 0
+}
+
+// update records the rate limit GitHub reported for a token.
+func (p *tokenPool) update(value string, remaining int, resetAt time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for i := range p.tokens {
+		if p.tokens[i].value == value {
+			p.tokens[i].remaining = remaining
+			p.tokens[i].resetAt = resetAt
+		}
+	}
+}
+
+// parseRateLimit reads the rate limit headers of a response.
+func parseRateLimit(resp *http.Response) (remaining int, resetAt time.Time, ok bool) {
+	remaining, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return 0, time.Time{}, false
+	}
+	reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return 0, time.Time{}, false
+	}
+	return remaining, time.Unix(reset, 0), true
+}
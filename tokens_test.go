@@ -0,0 +1,127 @@
+package patina
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestReadTokens(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tokens")
+	if err := os.WriteFile(path, []byte("# CI tokens\nghp_one\n\n  ghp_two  \n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tokens, err := ReadTokens(path)
+	if err != nil {
+		t.Fatalf("ReadTokens() error = %v", err)
+	}
+	if want := []string{"ghp_one", "ghp_two"}; !reflect.DeepEqual(tokens, want) {
+		t.Errorf("ReadTokens() = %q, want %q", tokens, want)
+	}
+
+	if err := os.WriteFile(path, []byte("# none yet\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadTokens(path); err == nil {
+		t.Error("ReadTokens() error = nil for a file with no tokens")
+	}
+}
+
+func TestNewGitHubClientSplitsTokens(t *testing.T) {
+	t.Setenv(githubAppIDEnv, "")
+	t.Setenv(githubHostEnv, "")
+	t.Setenv(githubTokenEnv, "ghp_one, ghp_two,,ghp_three")
+
+	client := NewGitHubClient().(*authFallbackClient).token
+	if client.executor.tokens == nil {
+		t.Fatal("executor has no token pool for several tokens")
+	}
+	var tokens []string
+	for _, token := range client.executor.tokens.tokens {
+		tokens = append(tokens, token.value)
+	}
+	if want := []string{"ghp_one", "ghp_two", "ghp_three"}; !reflect.DeepEqual(tokens, want) {
+		t.Errorf("pool tokens = %q, want %q", tokens, want)
+	}
+
+	t.Setenv(githubTokenEnv, "ghp_one")
+	if pool := NewGitHubClient().(*authFallbackClient).token.executor.tokens; pool != nil {
+		t.Error("executor has a token pool for a single token")
+	}
+}
+
+func TestTokenPoolPick(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	pool := newTokenPool([]string{"a", "b", "c"})
+
+	if token, wait := pool.pick(now); token != "a" || wait != 0 {
+		t.Errorf("pick() = %q, %v, want a, 0 before any quota is known", token, wait)
+	}
+
+	pool.update("a", 4000, now.Add(time.Hour))
+	if token, _ := pool.pick(now); token != "a" {
+		t.Errorf("pick() = %q, want a while it has quota", token)
+	}
+
+	// Near its limit, requests move to a token with more quota left
+	pool.update("a", tokenSwitchThreshold, now.Add(time.Hour))
+	pool.update("b", 200, now.Add(time.Hour))
+	if token, _ := pool.pick(now); token != "c" {
+		t.Errorf("pick() = %q, want c, whose quota is unknown", token)
+	}
+
+	// A token whose reset has passed is full again
+	pool.update("c", 0, now.Add(time.Hour))
+	pool.update("b", 0, now.Add(-time.Minute))
+	if token, wait := pool.pick(now); token != "b" || wait != 0 {
+		t.Errorf("pick() = %q, %v, want b, 0 after its reset", token, wait)
+	}
+
+	// With every token exhausted, wait for the first to reset
+	pool.update("a", 0, now.Add(time.Hour))
+	pool.update("b", 0, now.Add(20*time.Minute))
+	pool.update("c", 0, now.Add(40*time.Minute))
+	if token, wait := pool.pick(now); token != "b" || wait != 20*time.Minute {
+		t.Errorf("pick() = %q, %v, want b, 20m0s", token, wait)
+	}
+}
+
+func TestRequestExecutorRotatesTokens(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	remaining := map[string]int{"Bearer ghp_one": 200, "Bearer ghp_two": 5000}
+
+	var used []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		used = append(used, auth)
+		remaining[auth] -= 50
+		w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(remaining[auth]))
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(now.Add(time.Hour).Unix(), 10))
+	}))
+	defer server.Close()
+
+	executor := newRequestExecutor(server.Client(), 1)
+	executor.tokens = newTokenPool([]string{"ghp_one", "ghp_two"})
+	executor.now = func() time.Time { return now }
+	executor.sleep = func(d time.Duration) { t.Errorf("slept %v with quota left", d) }
+
+	for range 3 {
+		req, _ := http.NewRequest("GET", server.URL, nil)
+		req.Header.Set("Authorization", "Bearer ghp_one")
+		resp, err := executor.Do(req)
+		if err != nil {
+			t.Fatalf("Do() error = %v", err)
+		}
+		resp.Body.Close()
+	}
+
+	if want := []string{"Bearer ghp_one", "Bearer ghp_one", "Bearer ghp_two"}; !reflect.DeepEqual(used, want) {
+		t.Errorf("tokens used = %q, want %q", used, want)
+	}
+}
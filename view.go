@@ -0,0 +1,80 @@
+package patina
+
+import "time"
+
+// RepositoryView is a repository together with its freshness at a point in time.
+// It is the shared representation used to render repositories in any output
+// format, and marshals to JSON with the repository fields inlined.
+type RepositoryView struct {
+	Repository
+	Freshness Freshness `json:"freshness"`
+	Age       string    `json:"age"`
+	AgeDays   int       `json:"age_days"`
+
+	// Team is set from an ownership file by Ownership.Annotate
+	Team string `json:"team,omitempty"`
+
+	// Exemption is the reason a stale repository is exempt from the red
+	// count, set from an exemptions file by Exemptions.Annotate
+	Exemption string `json:"exemption,omitempty"`
+
+	// Enriched lists the optional fields populated for this repository, so a
+	// consumer can tell a field that was not fetched from one that is empty
+	Enriched []string `json:"enriched"`
+}
+
+// NewRepositoryView builds the view of a repository relative to now.
+func NewRepositoryView(repo Repository, now time.Time) RepositoryView {
+	return FreshnessOptions{}.RepositoryView(repo, now)
+}
+
+// RepositoryView builds the view of a repository relative to now, counting
+// ages with these options.
+func (o FreshnessOptions) RepositoryView(repo Repository, now time.Time) RepositoryView {
+	return RepositoryView{
+		Repository: repo,
+		Freshness:  o.RepositoryFreshness(repo, now),
+		Age:        o.Age(repo.LastUpdated, now),
+		AgeDays:    int(now.Sub(repo.LastUpdated).Hours() / 24),
+		Enriched:   repo.EnrichedFields(),
+	}
+}
+
+// EnrichedFields returns the JSON names of the optional fields populated for
+// a repository. These come from lookups that only some scans make, such as
+// branch protection and the last commit, or from fields that caches written
+// by earlier versions lack. Their JSON output is omitted when unset.
+func (r Repository) EnrichedFields() []string {
+	fields := []string{}
+	for _, field := range []struct {
+		name string
+		set  bool
+	}{
+		{"visibility", r.Visibility != ""},
+		{"default_branch", r.DefaultBranch != ""},
+		{"protection", r.Protection != ""},
+		{"last_commit_author", r.LastCommitAuthor != ""},
+		{"last_commit_email", r.LastCommitEmail != ""},
+		{"last_commit_date", !r.LastCommitDate.IsZero()},
+	} {
+		if field.set {
+			fields = append(fields, field.name)
+		}
+	}
+	return fields
+}
+
+// NewRepositoryViews builds views of repositories relative to now, preserving order.
+func NewRepositoryViews(repos []Repository, now time.Time) []RepositoryView {
+	return FreshnessOptions{}.RepositoryViews(repos, now)
+}
+
+// RepositoryViews builds views of repositories relative to now, preserving
+// order and counting ages with these options.
+func (o FreshnessOptions) RepositoryViews(repos []Repository, now time.Time) []RepositoryView {
+	views := make([]RepositoryView, 0, len(repos))
+	for _, repo := range repos {
+		views = append(views, o.RepositoryView(repo, now))
+	}
+	return views
+}
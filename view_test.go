@@ -0,0 +1,101 @@
+package patina
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestNewRepositoryViews(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+
+	repos := []Repository{
+		{Name: "fresh", LastUpdated: now.AddDate(0, 0, -5)},
+		{Name: "stale", LastUpdated: now.AddDate(0, 0, -200)},
+	}
+
+	views := NewRepositoryViews(repos, now)
+
+	if len(views) != 2 {
+		t.Fatalf("len(views) = %d, want 2", len(views))
+	}
+
+	tests := []struct {
+		name      string
+		freshness Freshness
+		age       string
+		ageDays   int
+	}{
+		{"fresh", FreshnessGreen, "5 days ago", 5},
+		{"stale", FreshnessRed, "6 months ago", 200},
+	}
+
+	for i, tt := range tests {
+		v := views[i]
+		if v.Name != tt.name {
+			t.Errorf("views[%d].Name = %s, want %s", i, v.Name, tt.name)
+		}
+		if v.Freshness != tt.freshness {
+			t.Errorf("views[%d].Freshness = %s, want %s", i, v.Freshness, tt.freshness)
+		}
+		if v.Age != tt.age {
+			t.Errorf("views[%d].Age = %s, want %s", i, v.Age, tt.age)
+		}
+		if v.AgeDays != tt.ageDays {
+			t.Errorf("views[%d].AgeDays = %d, want %d", i, v.AgeDays, tt.ageDays)
+		}
+	}
+}
+
+func TestRepositoryViewJSON(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	view := NewRepositoryView(Repository{Name: "repo1", FullName: "org/repo1", LastUpdated: now}, now)
+
+	data, err := json.Marshal(view)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	for _, key := range []string{"name", "full_name", "last_updated", "freshness", "age", "age_days", "enriched"} {
+		if _, ok := fields[key]; !ok {
+			t.Errorf("JSON is missing %q: %s", key, data)
+		}
+	}
+	for _, key := range []string{"visibility", "default_branch", "protection", "last_commit_author", "last_commit_date"} {
+		if _, ok := fields[key]; ok {
+			t.Errorf("JSON has %q, which was not fetched: %s", key, data)
+		}
+	}
+}
+
+func TestRepositoryEnrichedFields(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		repo Repository
+		want []string
+	}{
+		{"nothing fetched", Repository{Name: "old-cache"}, []string{}},
+		{"listing only", Repository{Visibility: "private", DefaultBranch: "main"}, []string{"visibility", "default_branch"}},
+		{
+			"protection and last commit",
+			Repository{DefaultBranch: "main", Protection: ProtectionUnprotected, LastCommitAuthor: "ada", LastCommitDate: now},
+			[]string{"default_branch", "protection", "last_commit_author", "last_commit_date"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.repo.EnrichedFields(); !slices.Equal(got, tt.want) || got == nil {
+				t.Errorf("EnrichedFields() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
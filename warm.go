@@ -0,0 +1,150 @@
+package patina
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+const warmConcurrency = 4 // Maximum organizations fetched at once when warming or scanning several
+
+// WarmResult reports the outcome of warming one organization's cache.
+type WarmResult struct {
+	Organization string
+	Repositories int  // Number of repositories cached, including forks, archived, and empty repositories
+	FromCache    bool // True if a valid cache already existed and nothing was fetched
+	Err          error
+}
+
+// ErrScanTimeout is returned for an organization that did not finish scanning
+// within ScanOptions.OrgTimeout or ScanOptions.Deadline.
+var ErrScanTimeout = errors.New("scan timed out")
+
+// ScanStatus is how scanning one organization of several ended.
+type ScanStatus string
+
+const (
+	ScanDone     ScanStatus = "done"
+	ScanTimedOut ScanStatus = "timed-out"
+	ScanErrored  ScanStatus = "errored"
+)
+
+// OrgScanResult is the outcome of scanning one organization of several.
+type OrgScanResult struct {
+	Organization string
+	Status       ScanStatus
+	Result       *ScanResult // Nil if Err is set
+	Err          error
+}
+
+// ScanAll scans several organizations concurrently. All fetches share the
+// scanner's client, so API requests stay within its concurrency and rate
+// limits. Results are returned in the order of orgs.
+//
+// Organizations that exceed opts.OrgTimeout, or are unfinished when
+// opts.Deadline passes, are reported as timed out so the run returns with the
+// others' results. Clients cannot cancel requests, so an abandoned scan keeps
+// running in the background and its result is discarded.
+func (s *Scanner) ScanAll(orgs []string, opts ScanOptions) []OrgScanResult {
+	results := make([]OrgScanResult, len(orgs))
+	sem := make(chan struct{}, warmConcurrency)
+	var wg sync.WaitGroup
+
+	// Closed when the deadline passes; nil, and so never ready, without one
+	var expired chan struct{}
+	if opts.Deadline > 0 {
+		expired = make(chan struct{})
+		timer := time.AfterFunc(opts.Deadline, func() { close(expired) })
+		defer timer.Stop()
+	}
+
+	for i, org := range orgs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+			case <-expired:
+				results[i] = deadlineExceeded(org, opts.Deadline)
+				return
+			}
+			defer func() { <-sem }()
+
+			results[i] = s.scanWithin(org, opts, expired)
+		}()
+	}
+
+	wg.Wait()
+	return results
+}
+
+// scanWithin scans one organization, giving up when opts.OrgTimeout elapses
+// or expired is closed.
+func (s *Scanner) scanWithin(org string, opts ScanOptions, expired <-chan struct{}) OrgScanResult {
+	done := make(chan OrgScanResult, 1)
+	go func() {
+		result, err := s.Scan(org, opts)
+		if err != nil {
+			done <- OrgScanResult{Organization: org, Status: ScanErrored, Err: err}
+			return
+		}
+		done <- OrgScanResult{Organization: org, Status: ScanDone, Result: result}
+	}()
+
+	var timeout <-chan time.Time
+	if opts.OrgTimeout > 0 {
+		timer := time.NewTimer(opts.OrgTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case result := <-done:
+		return result
+	case <-timeout:
+		return OrgScanResult{
+			Organization: org,
+			Status:       ScanTimedOut,
+			Err:          fmt.Errorf("%w after %s", ErrScanTimeout, opts.OrgTimeout),
+		}
+	case <-expired:
+		return deadlineExceeded(org, opts.Deadline)
+	}
+}
+
+// deadlineExceeded is the result of an organization cut off by the deadline.
+func deadlineExceeded(org string, deadline time.Duration) OrgScanResult {
+	return OrgScanResult{
+		Organization: org,
+		Status:       ScanTimedOut,
+		Err:          fmt.Errorf("%w: deadline of %s exceeded", ErrScanTimeout, deadline),
+	}
+}
+
+// Warm fetches and caches data for several organizations concurrently, as
+// ScanAll does. Results are returned in the order of orgs. A failure to
+// record an organization's freshness history is logged rather than failing
+// it, as its cache was still written.
+func (s *Scanner) Warm(orgs []string, opts ScanOptions) []WarmResult {
+	opts.IncludeForks = true
+	opts.IncludeMirrors = true
+	opts.IncludeArchived = true
+
+	scans := s.ScanAll(orgs, opts)
+	results := make([]WarmResult, len(scans))
+	for i, scan := range scans {
+		results[i] = WarmResult{Organization: scan.Organization, Err: scan.Err}
+		if scan.Err != nil {
+			continue
+		}
+		results[i].Repositories = len(scan.Result.Repositories) + scan.Result.NeverPushed
+		results[i].FromCache = scan.Result.FromCache
+		results[i].Err = scan.Result.CacheWriteErr
+		if scan.Result.HistoryErr != nil {
+			slog.Warn("organization cached without recording its freshness history", "org", scan.Organization, "error", scan.Result.HistoryErr)
+		}
+	}
+	return results
+}
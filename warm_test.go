@@ -0,0 +1,158 @@
+package patina
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestScannerWarm(t *testing.T) {
+	cache := NewCacheWithDir(t.TempDir())
+	now := time.Now()
+	mockClient := &mockGitHubClient{
+		repos: []Repository{
+			{Name: "repo1", LastUpdated: now},
+			{Name: "fork", LastUpdated: now, Fork: true},
+		},
+	}
+	scanner := NewScannerWithDeps(mockClient, cache)
+
+	// Pre-populate one organization so it is served from the cache
+	if _, err := scanner.Scan("cached-org", ScanOptions{}); err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+
+	orgs := []string{"org-a", "cached-org", "../bad", "org-b"}
+	results := scanner.Warm(orgs, ScanOptions{})
+
+	if len(results) != len(orgs) {
+		t.Fatalf("len(results) = %d, want %d", len(results), len(orgs))
+	}
+	for i, org := range orgs {
+		if results[i].Organization != org {
+			t.Errorf("results[%d].Organization = %q, want %q", i, results[i].Organization, org)
+		}
+	}
+
+	for _, i := range []int{0, 3} {
+		if results[i].Err != nil {
+			t.Errorf("results[%d].Err = %v, want nil", i, results[i].Err)
+		}
+		if results[i].FromCache {
+			t.Errorf("results[%d].FromCache = true, want false", i)
+		}
+		if results[i].Repositories != 2 {
+			t.Errorf("results[%d].Repositories = %d, want 2 (forks included)", i, results[i].Repositories)
+		}
+		if !cache.IsValid(orgs[i]) {
+			t.Errorf("cache for %s not valid after warming", orgs[i])
+		}
+	}
+
+	if !results[1].FromCache {
+		t.Error("results[1].FromCache = false, want true")
+	}
+	if !errors.Is(results[2].Err, ErrInvalidOrganization) {
+		t.Errorf("results[2].Err = %v, want ErrInvalidOrganization", results[2].Err)
+	}
+}
+
+func TestScannerWarmHistoryFailure(t *testing.T) {
+	cache := NewCacheWithDir(t.TempDir())
+	if err := os.WriteFile(cache.historyFilePath("org"), []byte("not json"), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	scanner := NewScannerWithDeps(&mockGitHubClient{repos: []Repository{{Name: "repo1", LastUpdated: time.Now()}}}, cache)
+
+	results := scanner.Warm([]string{"org"}, ScanOptions{})
+	if results[0].Err != nil {
+		t.Errorf("Err = %v, want nil: the cache was written", results[0].Err)
+	}
+	if results[0].Repositories != 1 || !cache.IsValid("org") {
+		t.Errorf("Repositories = %d, cache valid = %t; want 1, true", results[0].Repositories, cache.IsValid("org"))
+	}
+}
+
+func TestScannerScanAll(t *testing.T) {
+	now := time.Now()
+	mockClient := &mockGitHubClient{
+		repos: []Repository{
+			{Name: "repo1", LastUpdated: now},
+			{Name: "fork", LastUpdated: now, Fork: true},
+		},
+	}
+	scanner := NewScannerWithDeps(mockClient, NewCacheWithDir(t.TempDir()))
+
+	orgs := []string{"org-a", "../bad", "org-b"}
+	results := scanner.ScanAll(orgs, ScanOptions{})
+
+	if len(results) != len(orgs) {
+		t.Fatalf("len(results) = %d, want %d", len(results), len(orgs))
+	}
+	for _, i := range []int{0, 2} {
+		if results[i].Organization != orgs[i] {
+			t.Errorf("results[%d].Organization = %q, want %q", i, results[i].Organization, orgs[i])
+		}
+		if results[i].Err != nil {
+			t.Fatalf("results[%d].Err = %v, want nil", i, results[i].Err)
+		}
+		if len(results[i].Result.Repositories) != 1 {
+			t.Errorf("len(results[%d].Result.Repositories) = %d, want 1 (forks excluded)", i, len(results[i].Result.Repositories))
+		}
+	}
+
+	if !errors.Is(results[1].Err, ErrInvalidOrganization) {
+		t.Errorf("results[1].Err = %v, want ErrInvalidOrganization", results[1].Err)
+	}
+}
+
+// blockingClient blocks fetching the blocked organization until release is
+// closed, then fails so the abandoned scan writes nothing to the cache.
+type blockingClient struct {
+	mockGitHubClient
+	blocked string
+	release chan struct{}
+}
+
+func (c *blockingClient) FetchRepositories(org string) ([]Repository, error) {
+	if org == c.blocked {
+		<-c.release
+		return nil, errors.New("released")
+	}
+	return c.mockGitHubClient.FetchRepositories(org)
+}
+
+func TestScannerScanAllTimeouts(t *testing.T) {
+	tests := []struct {
+		name string
+		opts ScanOptions
+	}{
+		{"org timeout", ScanOptions{OrgTimeout: 20 * time.Millisecond}},
+		{"deadline", ScanOptions{Deadline: 20 * time.Millisecond}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &blockingClient{
+				mockGitHubClient: mockGitHubClient{repos: []Repository{{Name: "repo1", LastUpdated: time.Now()}}},
+				blocked:          "slow",
+				release:          make(chan struct{}),
+			}
+			defer close(client.release)
+			scanner := NewScannerWithDeps(client, NewCacheWithDir(t.TempDir()))
+
+			results := scanner.ScanAll([]string{"org-a", "slow", "../bad"}, tt.opts)
+
+			if results[0].Status != ScanDone || results[0].Result == nil {
+				t.Errorf("results[0] = %+v, want done", results[0])
+			}
+			if results[1].Status != ScanTimedOut || !errors.Is(results[1].Err, ErrScanTimeout) {
+				t.Errorf("results[1] = %+v, want timed out", results[1])
+			}
+			if results[2].Status != ScanErrored || !errors.Is(results[2].Err, ErrInvalidOrganization) {
+				t.Errorf("results[2] = %+v, want errored", results[2])
+			}
+		})
+	}
+}